@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/codegen/golang"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/codegen/gotests"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/codegen/typescript"
+)
+
+// runCodegen implements `emspec codegen`: generate the event and read model
+// types of the board in a target language, Go handler stubs of its slices,
+// or Go test skeletons of its scenarios, to -o or stdout. Returns the exit
+// code.
+func runCodegen(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("codegen", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	var (
+		file       = fset.String("file", "", "CUE file, package directory or glob of .cue files to load (required; - reads standard input)")
+		boardName  = fset.String("board", "", "Board name, or #N for the Nth board in name order, from 0 (default: first declared)")
+		lang       = fset.String("lang", "go", "Target language (go, ts, go-tests: test skeletons from the GWT scenarios)")
+		kind       = fset.String("kind", "types", "What -lang go generates (types, handlers: stub handler per slice)")
+		pkg        = fset.String("package", "", "Package name of the generated Go code (default: events, handlers for -kind handlers)")
+		output     = fset.String("o", "", "Output file (default: stdout)")
+		modRoot    = fset.String("module-root", "", "CUE module root (default: discovered from the board file's directory)")
+		eventsFile = fset.String("events-file", "", "CUE file with shared top-level events merged into the board")
+	)
+	if err := fset.Parse(args); err != nil {
+		return 1
+	}
+	if *file == "" {
+		fmt.Fprintln(stderr, "error: -file is required")
+		fset.Usage()
+		return 1
+	}
+	if *kind != "types" && *kind != "handlers" {
+		fmt.Fprintf(stderr, "error: unknown kind %q\n", *kind)
+		return 1
+	}
+	if *kind == "handlers" && *lang != "go" {
+		fmt.Fprintln(stderr, "error: -kind handlers requires -lang go")
+		return 1
+	}
+	if *pkg == "" {
+		*pkg = "events"
+		if *kind == "handlers" {
+			*pkg = "handlers"
+		}
+	}
+
+	loadOpts := board.LoadOptions{ModuleRoot: *modRoot, EventsFile: *eventsFile}
+	if err := readStdinSource(*file, &loadOpts); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	b, _, err := board.LoadBoardPermissiveWithOptions(*file, *boardName, loadOpts)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	manifest, slices, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+
+	var out []byte
+	switch *lang {
+	case "go":
+		if *kind == "handlers" {
+			out, err = golang.Handlers(*pkg, board.FlowSlices(manifest, slices))
+		} else {
+			out, err = golang.Types(*pkg, board.EventFields(b), board.FlowSlices(manifest, slices))
+		}
+	case "go-tests":
+		out, err = gotests.Tests(*pkg, board.FlowSlices(manifest, slices))
+	case "ts":
+		out = typescript.Types(board.EventFields(b), board.FlowSlices(manifest, slices))
+	default:
+		err = fmt.Errorf("unknown language %q", *lang)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	if *output == "" {
+		stdout.Write(out)
+		return 0
+	}
+	if err := os.WriteFile(*output, out, 0o644); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/diff"
+)
+
+// runDiff implements `emspec diff`: compare the slices of two IR directories.
+// Like diff(1), it exits 0 when they match, 1 when they differ and 2 on errors.
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	var (
+		oldDir  = fset.String("old", "", "IR directory of the old board (required)")
+		newDir  = fset.String("new", "", "IR directory of the new board (required)")
+		byIndex = fset.Bool("by-index", false, "Match slices by flow position instead of name (renames show as changes)")
+		format  = fset.String("format", "text", "Output format (text, json)")
+		width   = fset.Int("width", 80, "Box width of the text output")
+	)
+	if err := fset.Parse(args); err != nil {
+		return 2
+	}
+	if *oldDir == "" || *newDir == "" {
+		fmt.Fprintln(stderr, "error: -old and -new are required")
+		fset.Usage()
+		return 2
+	}
+
+	oldManifest, oldSlices, err := board.ReadBoardFiles(*oldDir)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s: %v\n", *oldDir, err)
+		return 2
+	}
+	newManifest, newSlices, err := board.ReadBoardFiles(*newDir)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s: %v\n", *newDir, err)
+		return 2
+	}
+	result := diff.Compare(oldManifest, oldSlices, newManifest, newSlices, diff.Options{ByIndex: *byIndex})
+
+	switch *format {
+	case "text":
+		fmt.Fprint(stdout, diff.Render(result, *width))
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(result)
+	default:
+		fmt.Fprintf(stderr, "error: unknown format %q\n", *format)
+		return 2
+	}
+	if result.Empty() {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/export/dot"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/export/jsonschema"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/export/markdown"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/export/mermaid"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/export/openapi"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
+)
+
+// runExport implements `emspec export`: load the board and write it in an
+// interchange format, to -o or stdout. Returns the exit code.
+func runExport(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("export", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	var (
+		file       = fset.String("file", "", "CUE file, package directory or glob of .cue files to load (required; - reads standard input)")
+		boardName  = fset.String("board", "", "Board name, or #N for the Nth board in name order, from 0 (default: first declared)")
+		format     = fset.String("format", "openapi", "Export format (openapi, mermaid, dot, jsonschema, markdown, text)")
+		output     = fset.String("o", "", "Output file (default: stdout)")
+		outdir     = fset.String("outdir", "", "Output directory of multi-file formats (jsonschema)")
+		rankdir    = fset.String("rankdir", "LR", "Layout direction of -format dot (TB, LR, BT, RL)")
+		width      = fset.Int("width", 0, "Wrap width of -format text (default: no wrapping)")
+		modRoot    = fset.String("module-root", "", "CUE module root (default: discovered from the board file's directory)")
+		eventsFile = fset.String("events-file", "", "CUE file with shared top-level events merged into the board")
+	)
+	if err := fset.Parse(args); err != nil {
+		return 1
+	}
+	if *file == "" {
+		fmt.Fprintln(stderr, "error: -file is required")
+		fset.Usage()
+		return 1
+	}
+
+	loadOpts := board.LoadOptions{ModuleRoot: *modRoot, EventsFile: *eventsFile}
+	if err := readStdinSource(*file, &loadOpts); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	b, _, err := board.LoadBoardPermissiveWithOptions(*file, *boardName, loadOpts)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	if *format == "jsonschema" {
+		if err := writeEventSchemas(b, *outdir); err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	var out []byte
+	switch *format {
+	case "openapi":
+		out, err = openapi.GenerateOpenAPI(b)
+	case "mermaid":
+		manifest, slices, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+		out = []byte(mermaid.Flowchart(manifest, slices))
+	case "dot":
+		manifest, _, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{Graph: true})
+		var graph string
+		graph, err = dot.Graph(manifest, *rankdir)
+		out = []byte(graph)
+	case "markdown":
+		manifest, slices, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+		out = []byte(markdown.Document(manifest, slices))
+	case "text":
+		manifest, slices, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+		out, err = plainText(board.FlowSlices(manifest, slices), *width)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	if *output == "" {
+		stdout.Write(out)
+		return 0
+	}
+	if err := os.WriteFile(*output, out, 0o644); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// plainText renders the flow's slices and stories as plain text (no box
+// drawing), one after the other, for pipes and CI logs.
+func plainText(flow []map[string]any, width int) ([]byte, error) {
+	var sb strings.Builder
+	for i, data := range flow {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		text, err := render.RenderSliceIRPlain(data, width)
+		if err != nil {
+			return nil, err
+		}
+		sb.WriteString(text)
+	}
+	return []byte(sb.String()), nil
+}
+
+// writeEventSchemas writes one <EventType>.schema.json per board event to dir.
+func writeEventSchemas(b *board.Board, dir string) error {
+	if dir == "" {
+		return fmt.Errorf("-format jsonschema requires -outdir")
+	}
+	schemas, err := jsonschema.GenerateEventSchemas(b)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for eventType, data := range schemas {
+		if err := os.WriteFile(filepath.Join(dir, jsonschema.FileName(eventType)), data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+)
+
+// runList implements `emspec list`: print the boards of a CUE package, one
+// per line, as accepted by -board. Returns the exit code.
+func runList(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("list", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	var (
+		file    = fset.String("file", "", "CUE file, package directory or glob of .cue files to load (required; - reads standard input)")
+		modRoot = fset.String("module-root", "", "CUE module root (default: discovered from the board file's directory)")
+	)
+	if err := fset.Parse(args); err != nil {
+		return 1
+	}
+	if *file == "" {
+		fmt.Fprintln(stderr, "error: -file is required")
+		fset.Usage()
+		return 1
+	}
+
+	loadOpts := board.LoadOptions{ModuleRoot: *modRoot}
+	if err := readStdinSource(*file, &loadOpts); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	boards, err := board.ListBoardsInFile(*file, loadOpts)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	for _, name := range boards {
+		fmt.Fprintln(stdout, name)
+	}
+	return 0
+}
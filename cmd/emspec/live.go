@@ -0,0 +1,81 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// liveMessage is pushed to web clients on /.ws after each regeneration.
+type liveMessage struct {
+	Type   string   `json:"type"`             // "reloaded" or "error"
+	Errors []string `json:"errors,omitempty"` // build error, for "error"
+}
+
+// liveWriteTimeout bounds a send to one /.ws client.
+const liveWriteTimeout = 5 * time.Second
+
+// liveHub tracks the connected /.ws clients and broadcasts reloads to them.
+type liveHub struct {
+	mu      sync.Mutex
+	clients map[*websocket.Conn]bool
+}
+
+func newLiveHub() *liveHub {
+	return &liveHub{clients: make(map[*websocket.Conn]bool)}
+}
+
+// handler serves /.ws: it registers the connection and holds it open until
+// the client goes away (clients don't send anything).
+func (h *liveHub) handler() websocket.Handler {
+	return func(conn *websocket.Conn) {
+		h.mu.Lock()
+		h.clients[conn] = true
+		h.mu.Unlock()
+
+		var discard string
+		for websocket.Message.Receive(conn, &discard) == nil {
+		}
+
+		h.mu.Lock()
+		delete(h.clients, conn)
+		h.mu.Unlock()
+		conn.Close()
+	}
+}
+
+// broadcast sends msg to every client, dropping those that can't be written to
+// within liveWriteTimeout. Sends happen outside the lock, so a stalled client
+// doesn't hold up connects and disconnects. A nil hub (no web server) does nothing.
+func (h *liveHub) broadcast(msg liveMessage) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	conns := make([]*websocket.Conn, 0, len(h.clients))
+	for conn := range h.clients {
+		conns = append(conns, conn)
+	}
+	h.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+		if err := websocket.JSON.Send(conn, msg); err != nil {
+			h.mu.Lock()
+			delete(h.clients, conn)
+			h.mu.Unlock()
+			conn.Close()
+		}
+	}
+}
+
+// close disconnects every client, ending their handlers.
+func (h *liveHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for conn := range h.clients {
+		delete(h.clients, conn)
+		conn.Close()
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// dialHub connects a /.ws client to hub's handler and waits until the hub
+// has registered it.
+func dialHub(t *testing.T, hub *liveHub) *websocket.Conn {
+	t.Helper()
+	srv := httptest.NewServer(hub.handler())
+	t.Cleanup(srv.Close)
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/.ws", "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		hub.mu.Lock()
+		n := len(hub.clients)
+		hub.mu.Unlock()
+		if n == 1 {
+			return conn
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("client never registered")
+		}
+	}
+}
+
+func TestLiveHubBroadcast(t *testing.T) {
+	hub := newLiveHub()
+	conn := dialHub(t, hub)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	for _, want := range []liveMessage{
+		{Type: "reloaded"},
+		{Type: "error", Errors: []string{"build: expected '}'"}},
+	} {
+		hub.broadcast(want)
+		var got liveMessage
+		if err := websocket.JSON.Receive(conn, &got); err != nil {
+			t.Fatalf("receive: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("received %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestLiveHubClose(t *testing.T) {
+	hub := newLiveHub()
+	conn := dialHub(t, hub)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	hub.close()
+	var discard string
+	if err := websocket.Message.Receive(conn, &discard); err == nil {
+		t.Error("connection still open after close")
+	}
+	hub.mu.Lock()
+	n := len(hub.clients)
+	hub.mu.Unlock()
+	if n != 0 {
+		t.Errorf("%d clients left after close", n)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// Log levels, from least to most chatty.
+const (
+	levelError   = iota // -quiet
+	levelInfo           // default
+	levelVerbose        // -v
+	levelDebug          // -vv
+)
+
+// logger gates log output on a verbosity level.
+type logger struct {
+	level int
+	out   *log.Logger
+	exit  func(code int) // os.Exit, replaced in tests
+}
+
+func newLogger(w io.Writer, level int) *logger {
+	return &logger{level: level, out: log.New(w, "", log.LstdFlags), exit: os.Exit}
+}
+
+// logLevel maps the verbosity flags to a level; -quiet wins over -v/-vv.
+func logLevel(quiet, v, vv bool) int {
+	switch {
+	case quiet:
+		return levelError
+	case vv:
+		return levelDebug
+	case v:
+		return levelVerbose
+	}
+	return levelInfo
+}
+
+func (l *logger) logf(level int, format string, args ...any) {
+	if l.level >= level {
+		l.out.Printf(format, args...)
+	}
+}
+
+func (l *logger) Errorf(format string, args ...any)   { l.logf(levelError, "error: "+format, args...) }
+func (l *logger) Infof(format string, args ...any)    { l.logf(levelInfo, format, args...) }
+func (l *logger) Verbosef(format string, args ...any) { l.logf(levelVerbose, format, args...) }
+func (l *logger) Debugf(format string, args ...any)   { l.logf(levelDebug, format, args...) }
+
+// Fatalf logs regardless of level and exits.
+func (l *logger) Fatalf(format string, args ...any) {
+	l.out.Output(2, fmt.Sprintf(format, args...))
+	l.exit(1)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLoggerLevels(t *testing.T) {
+	cases := []struct {
+		name           string
+		quiet, v, vv   bool
+		want, dontWant []string
+	}{
+		{"quiet", true, false, false, []string{"error: e"}, []string{"info", "verbose", "debug"}},
+		{"quiet wins over -vv", true, false, true, []string{"error: e"}, []string{"info", "verbose", "debug"}},
+		{"default", false, false, false, []string{"error: e", "info"}, []string{"verbose", "debug"}},
+		{"-v", false, true, false, []string{"error: e", "info", "verbose"}, []string{"debug"}},
+		{"-vv", false, false, true, []string{"error: e", "info", "verbose", "debug"}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logs := newLogger(&buf, logLevel(c.quiet, c.v, c.vv))
+			logs.Errorf("e")
+			logs.Infof("info")
+			logs.Verbosef("verbose")
+			logs.Debugf("debug")
+			for _, s := range c.want {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("missing %q in:\n%s", s, buf.String())
+				}
+			}
+			for _, s := range c.dontWant {
+				if strings.Contains(buf.String(), s) {
+					t.Errorf("unexpected %q in:\n%s", s, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestLoggerFatalf(t *testing.T) {
+	// Fatal errors go to the configured writer, even at -quiet, then exit 1
+	var buf bytes.Buffer
+	logs := newLogger(&buf, levelError)
+	code := -1
+	logs.exit = func(c int) { code = c }
+	logs.Fatalf("web server: %v", "address in use")
+	if !strings.Contains(buf.String(), "web server: address in use") || code != 1 {
+		t.Errorf("Fatalf wrote %q and exited with %d, want the message and 1", buf.String(), code)
+	}
+}
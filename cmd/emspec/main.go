@@ -1,155 +1,448 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"io/fs"
-	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/fsnotify/fsnotify"
 
 	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
 	"github.com/err0r500/event-modeling-dcb-spec/pkg/tui"
 	"github.com/err0r500/event-modeling-dcb-spec/pkg/web"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "validate":
+			os.Exit(runValidate(os.Args[2:], os.Stdout, os.Stderr))
+		case "export":
+			os.Exit(runExport(os.Args[2:], os.Stdout, os.Stderr))
+		case "codegen":
+			os.Exit(runCodegen(os.Args[2:], os.Stdout, os.Stderr))
+		case "diff":
+			os.Exit(runDiff(os.Args[2:], os.Stdout, os.Stderr))
+		case "list":
+			os.Exit(runList(os.Args[2:], os.Stdout, os.Stderr))
+		case "status":
+			os.Exit(runStatus(os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
+
 	var (
-		file      = flag.String("file", "", "CUE file to load (required)")
-		boardName = flag.String("board", "", "Board name (default: first found)")
-		outdir    = flag.String("outdir", "", "IR output directory (required)")
-		watch     = flag.Bool("watch", true, "Watch CUE files and regenerate IR")
-		webFlag   = flag.Bool("web", false, "Also run web server")
-		port      = flag.Int("port", 3000, "Web server port")
-		noTui     = flag.Bool("no-tui", false, "Disable TUI")
+		file       = flag.String("file", "", "CUE file, package directory or glob of .cue files to load (required; - reads standard input)")
+		boardName  = flag.String("board", "", "Board name, or #N for the Nth board in name order, from 0 (default: first declared)")
+		outdir     = flag.String("outdir", "", "IR output directory (required)")
+		watch      = flag.Bool("watch", true, "Watch CUE files and regenerate IR")
+		webFlag    = flag.Bool("web", false, "Also run web server")
+		port       = flag.Int("port", 3000, "Web server port (0 picks a free one)")
+		noTui      = flag.Bool("no-tui", false, "Disable TUI")
+		width      = flag.Int("width", 0, "Force the TUI rendering width (default: terminal width); also the -format columns width (default 120)")
+		indexPfx   = flag.Bool("index-prefix", false, "Prefix slice files with their flow index (e.g. 000_AddItem.json)")
+		compact    = flag.Bool("compact-manifest", false, "Omit story instance payloads from board.json")
+		notes      = flag.Bool("notes", false, "Record informational reify notes in board.json")
+		graph      = flag.Bool("graph", false, "Record the events each slice consumes and emits in board.json")
+		includeSrc = flag.Bool("include-source", false, "Embed each slice's formatted CUE under _source (debugging)")
+		listCodes  = flag.Bool("list-codes", false, "Print the diagnostic code catalog and exit")
+		format     = flag.String("format", "", "Print the board in the given format and exit (timeline, columns, state-machine)")
+		tag        = flag.String("tag", "", "Tag whose lifecycle -format state-machine renders")
+		scenCheck  = flag.Bool("check-scenario-consistency", false, "Flag scenario given events whose tag values contradict the query")
+		naming     = flag.Bool("check-naming", false, "Warn on event, command and actor names violating naming patterns")
+		namingCfg  = flag.String("naming-config", "", "JSON file with naming patterns ({\"events\", \"commands\", \"actors\"}), implies -check-naming")
+		modRoot    = flag.String("module-root", "", "CUE module root (default: discovered from the board file's directory)")
+		eventsFile = flag.String("events-file", "", "CUE file with shared top-level events merged into the board")
+		failOn     = flag.String("fail-on", "", "Comma-separated diagnostic codes (e.g. E102,E104) that make the initial render exit non-zero")
+		strict     = flag.Bool("strict", false, "Make any diagnostic fail the initial render, writing only the error manifest")
+		quiet      = flag.Bool("quiet", false, "Only log errors")
+		verbose    = flag.Bool("v", false, "Verbose logging (watcher activity)")
+		debug      = flag.Bool("vv", false, "Debug logging (every file event)")
 	)
 	flag.Parse()
 
+	if *listCodes {
+		printCodes(os.Stdout)
+		return
+	}
+
 	if *file == "" {
 		fmt.Fprintln(os.Stderr, "error: -file is required")
 		flag.Usage()
 		os.Exit(1)
 	}
+	loadOpts := board.LoadOptions{ModuleRoot: *modRoot, EventsFile: *eventsFile}
+	if err := readStdinSource(*file, &loadOpts); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if loadOpts.Source != nil {
+		*watch = false // nothing to watch
+	}
+	if *format != "" {
+		if err := printFormat(os.Stdout, *file, *boardName, loadOpts, *format, formatOptions{tag: *tag, width: *width}); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 	if *outdir == "" {
 		fmt.Fprintln(os.Stderr, "error: -outdir is required")
 		flag.Usage()
 		os.Exit(1)
 	}
 
+	// Logs would garble the TUI (errors are shown via the manifest there)
+	logOut := io.Writer(os.Stderr)
+	if !*noTui {
+		logOut = io.Discard
+	}
+	logs := newLogger(logOut, logLevel(*quiet, *verbose, *debug))
+
+	job := irJob{
+		file:      *file,
+		boardName: *boardName,
+		outdir:    *outdir,
+		load:      loadOpts,
+		loader:    board.NewLoader(loadOpts),
+		reify:     board.ReifyOptions{IndexPrefix: *indexPfx, CompactManifest: *compact, Notes: *notes, Graph: *graph, IncludeSource: *includeSrc},
+		lint:      lintOptions{scenarioConsistency: *scenCheck},
+		prev:      new(map[string]map[string]any),
+		strict:    *strict,
+	}
+	if *naming || *namingCfg != "" {
+		cfg, err := loadNamingConfig(*namingCfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		job.lint.naming = &cfg
+	}
+
 	// Initial render
-	if err := writeIR(*file, *boardName, *outdir); err != nil {
+	diags, err := writeIR(job, logs)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
+	if failing := failingDiagnostics(diags, parseCodes(*failOn)); len(failing) > 0 {
+		for _, d := range failing {
+			fmt.Fprintln(os.Stderr, d)
+		}
+		os.Exit(1)
+	}
+
+	job.strict = false // only the initial render is gated, the watcher shows later diagnostics
+
+	// Interrupts stop the watcher and the server between regenerations
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var wg sync.WaitGroup
 
 	// Start web server in background
+	var hub *liveHub
 	if *webFlag {
-		fmt.Printf("starting the webserver on http://localhost:%d", port)
-		go runWebServer(*outdir, *port)
+		// Bind before going to the background so a taken port fails here, and
+		// -port 0 resolves to the port actually chosen
+		ln, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: web server: %v\n", err)
+			os.Exit(1)
+		}
+		logs.Infof("web server at http://localhost:%d", ln.Addr().(*net.TCPAddr).Port)
+		hub = newLiveHub()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runWebServer(ctx, ln, *outdir, logs, hub, func() error { return reloadIR(job, logs, hub) })
+		}()
 	}
 
 	// Start file watcher in background
 	if *watch {
-		// Suppress log output when TUI is active (errors shown via manifest)
-		verbose := *noTui
-		go watchAndWrite(*file, *boardName, *outdir, verbose)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			watchAndWrite(ctx, job, logs, hub)
+		}()
 	}
 
-	// Run TUI (blocking) or just wait
+	// Run TUI (blocking) or just wait, then let the background work finish
 	if !*noTui {
-		runTUI(*outdir)
+		runTUI(*outdir, tui.IRModelOptions{Width: *width})
 	} else if *watch || *webFlag {
-		// Keep running without TUI
-		select {}
+		<-ctx.Done()
+	}
+	stop()
+	wg.Wait()
+}
+
+func printCodes(out io.Writer) {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CODE\tNAME\tSEVERITY\tDESCRIPTION")
+	for _, c := range render.ErrorCodeCatalog() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Code, c.Name, c.Severity, c.Description)
+	}
+	tw.Flush()
+}
+
+// stdinFile is the -file value reading the board from standard input.
+const stdinFile = "-"
+
+// readStdinSource reads the board source from standard input into opts
+// when file is stdinFile.
+func readStdinSource(file string, opts *board.LoadOptions) error {
+	if file != stdinFile {
+		return nil
 	}
+	src, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("read stdin: %w", err)
+	}
+	opts.Source = src
+	return nil
+}
+
+// formatOptions holds the flags of the individual -format outputs.
+type formatOptions struct {
+	tag   string // state-machine
+	width int    // columns
 }
 
-func writeIR(filePath, boardName, outdir string) error {
-	b, warnings, err := board.LoadBoardPermissive(filePath, boardName)
+// printFormat renders the board to out in one of the text formats.
+func printFormat(out io.Writer, filePath, boardName string, loadOpts board.LoadOptions, format string, opts formatOptions) error {
+	b, _, err := board.LoadBoardPermissiveWithOptions(filePath, boardName, loadOpts)
 	if err != nil {
-		board.WriteBoardError(outdir, boardName, []string{err.Error()})
 		return err
 	}
+	manifest, slices, _ := board.ReifyBoardFiles(b, b.Diagnostics, board.ReifyOptions{})
+	flow := board.FlowSlices(manifest, slices)
+
+	switch format {
+	case "timeline":
+		fmt.Fprint(out, render.RenderTimeline(manifest.Name, flow))
+	case "columns":
+		width := opts.width
+		if width <= 0 {
+			width = 120
+		}
+		fmt.Fprint(out, render.RenderBoardASCIIColumns(manifest.Name, flow, width))
+	case "state-machine":
+		if opts.tag == "" {
+			return fmt.Errorf("-format state-machine requires -tag")
+		}
+		diagram, err := render.RenderStateMachine(flow, opts.tag)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, diagram)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+	return nil
+}
+
+// lintOptions enables the opt-in validation passes.
+type lintOptions struct {
+	scenarioConsistency bool
+	naming              *render.NamingConfig // nil disables the naming pass
+}
+
+// loadNamingConfig reads naming patterns from a JSON file over the defaults.
+// An empty path returns the defaults.
+func loadNamingConfig(path string) (render.NamingConfig, error) {
+	cfg := render.DefaultNamingConfig()
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("naming config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("naming config %s: %w", path, err)
+	}
+	return cfg, nil
+}
 
-	srcDir := filepath.Dir(filePath)
-	manifest, slices, images := board.ReifyBoardFiles(b, warnings)
-	return board.WriteBoardFiles(outdir, manifest, slices, srcDir, images)
+// irJob describes one board → IR directory generation.
+type irJob struct {
+	file      string
+	boardName string
+	outdir    string
+	load      board.LoadOptions
+	loader    *board.Loader // loads with load, reusing one CUE context across regenerations
+	reify     board.ReifyOptions
+	lint      lintOptions
+	prev      *map[string]map[string]any // slice data of the last render, only changes are written
+	strict    bool                       // any diagnostic fails the render, only the error manifest is written
 }
 
-func watchAndWrite(filePath, boardName, outdir string, verbose bool) {
-	absPath, err := filepath.Abs(filePath)
+// writeIR generates the IR directory of job and returns the diagnostics
+// recorded in its manifest. In strict mode, diagnostics are a
+// board.ValidationErrors error and only the error manifest is written.
+func writeIR(job irJob, logs *logger) ([]string, error) {
+	lint := job.lint
+	b, _, err := job.loader.Reload(job.file, job.boardName)
 	if err != nil {
-		log.Fatalf("abs path: %v", err)
+		board.WriteBoardError(job.outdir, job.boardName, []render.ValidationError{{Message: err.Error()}})
+		return nil, err
+	}
+	diags := b.Diagnostics
+	if lint.scenarioConsistency {
+		diags = append(diags, render.ValidateScenarioConsistency(b.Value)...)
+	}
+	if lint.naming != nil {
+		namingWarnings, err := render.ValidateNaming(b.Value, *lint.naming)
+		if err != nil {
+			return nil, err
+		}
+		diags = append(diags, namingWarnings...)
+	}
+	warnings := render.FormatDiagnostics(diags)
+	if job.strict && len(warnings) > 0 {
+		board.WriteBoardError(job.outdir, b.Name, diags)
+		return warnings, board.ValidationErrors(warnings)
+	}
+
+	srcDir := board.SourceDir(job.file) // "." for stdin: images resolve against the working directory
+	res := board.ReifyBoardFilesIncremental(b, diags, job.reify, *job.prev)
+	failed, err := board.WriteBoardFilesIncremental(job.outdir, res, srcDir)
+	if err != nil {
+		return nil, err
+	}
+	*job.prev = res.Slices
+	logs.Debugf("%d of %d slice files changed", len(res.Changed), len(res.Slices))
+	for _, img := range failed {
+		logs.Errorf("image %s could not be copied", img)
+	}
+	return warnings, nil
+}
+
+// parseCodes splits a comma-separated -fail-on list.
+func parseCodes(list string) map[string]bool {
+	codes := make(map[string]bool)
+	for code := range strings.SplitSeq(list, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			codes[code] = true
+		}
+	}
+	return codes
+}
+
+// failingDiagnostics returns the diagnostics that gate the run: those with a
+// code in failOn.
+func failingDiagnostics(diags []string, failOn map[string]bool) []string {
+	var failing []string
+	for _, d := range diags {
+		code := render.DiagnosticCode(d)
+		if failOn[code] {
+			failing = append(failing, d)
+		}
+	}
+	return failing
+}
+
+// irMu serializes regenerations from the watcher and /.reload.
+var irMu sync.Mutex
+
+// reloadIR regenerates the IR directory of job and tells the web clients,
+// sending the build error instead when the board doesn't load.
+func reloadIR(job irJob, logs *logger, hub *liveHub) error {
+	irMu.Lock()
+	defer irMu.Unlock()
+	if _, err := writeIR(job, logs); err != nil {
+		hub.broadcast(liveMessage{Type: "error", Errors: []string{err.Error()}})
+		return err
 	}
-	dir := filepath.Dir(absPath)
+	hub.broadcast(liveMessage{Type: "reloaded"})
+	return nil
+}
 
-	watcher, err := fsnotify.NewWatcher()
+// watchAndWrite regenerates the IR each time the board's sources change,
+// until ctx is done.
+func watchAndWrite(ctx context.Context, job irJob, logs *logger, hub *liveHub) {
+	absPath, err := filepath.Abs(job.file)
 	if err != nil {
-		log.Fatalf("watcher: %v", err)
-	}
-	defer watcher.Close()
-
-	if err := watcher.Add(dir); err != nil {
-		log.Fatalf("watch dir: %v", err)
-	}
-
-	if verbose {
-		log.Printf("watching %s → %s", dir, outdir)
-	}
-
-	for {
-		select {
-		case ev, ok := <-watcher.Events:
-			if !ok {
-				return
-			}
-			if ev.Op&(fsnotify.Write|fsnotify.Create) == 0 {
-				continue
-			}
-			time.Sleep(100 * time.Millisecond)
-			for len(watcher.Events) > 0 {
-				<-watcher.Events
-			}
-			if err := writeIR(filePath, boardName, outdir); err != nil {
-				if verbose {
-					log.Printf("error: %v", err)
-				}
-			}
-		case err, ok := <-watcher.Errors:
-			if !ok {
-				return
-			}
-			if verbose {
-				log.Printf("watcher error: %v", err)
-			}
+		logs.Fatalf("abs path: %v", err)
+	}
+	dir := board.SourceDir(absPath)
+	dirs := []string{dir}
+	if job.load.EventsFile != "" {
+		if eventsDir, err := filepath.Abs(filepath.Dir(job.load.EventsFile)); err == nil && eventsDir != dir {
+			dirs = append(dirs, eventsDir)
+		}
+	}
+
+	logs.Infof("watching %s → %s", dir, job.outdir)
+
+	err = board.Watch(ctx, dirs, func() {
+		if err := reloadIR(job, logs, hub); err != nil {
+			logs.Errorf("%v", err)
+			return
 		}
+		logs.Verbosef("regenerated %s", job.outdir)
+	}, board.WatchOptions{
+		Event: func(ev fsnotify.Event) { logs.Debugf("file event: %s", ev) },
+		Error: func(err error) { logs.Errorf("watcher: %v", err) },
+	})
+	if err != nil {
+		logs.Fatalf("%v", err)
 	}
 }
 
-func runWebServer(outdir string, port int) {
+// runWebServer serves the web view and the IR directory on ln until ctx is
+// done, then shuts down gracefully. Clients connected to /.ws are told when
+// the IR is regenerated; POST /.reload regenerates it.
+func runWebServer(ctx context.Context, ln net.Listener, outdir string, logs *logger, hub *liveHub, reload func() error) {
 	distFS, err := fs.Sub(web.Assets, "dist")
 	if err != nil {
-		log.Fatalf("web assets: %v", err)
+		logs.Fatalf("web assets: %v", err)
 	}
 
 	mux := http.NewServeMux()
-	mux.Handle("/.board/", http.StripPrefix("/.board/", http.FileServer(http.Dir(outdir))))
+	mux.Handle("/.board/", http.StripPrefix("/.board/", web.IRHandler(outdir)))
+	mux.Handle("/.ws", hub.handler())
+	mux.HandleFunc("POST /.reload", func(w http.ResponseWriter, r *http.Request) {
+		if err := reload(); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
 	mux.Handle("/", http.FileServer(http.FS(distFS)))
 
-	addr := fmt.Sprintf(":%d", port)
-	log.Printf("web server at http://localhost%s", addr)
-	if err := http.ListenAndServe(addr, mux); err != nil {
-		log.Fatalf("web server: %v", err)
+	srv := &http.Server{Handler: mux}
+	go func() {
+		<-ctx.Done()
+		// /.ws connections are hijacked, Shutdown doesn't wait for them
+		hub.close()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logs.Errorf("web server shutdown: %v", err)
+		}
+	}()
+	if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+		logs.Fatalf("web server: %v", err)
 	}
 }
 
-func runTUI(outdir string) {
-	m, err := tui.NewIRModel(outdir)
+func runTUI(outdir string, opts tui.IRModelOptions) {
+	m, err := tui.NewIRModelWithOptions(outdir, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
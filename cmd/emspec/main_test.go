@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+)
+
+// writeTestBoard writes validateBoardSrc, named name, to file.
+func writeTestBoard(t *testing.T, file, name string) {
+	t.Helper()
+	src := strings.NewReplacer("VERB", "POST", "GHOST", "Done", `name: "Test"`, `name: "`+name+`"`).Replace(validateBoardSrc)
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// manifestName returns the board name in outdir's board.json, "" if unreadable.
+func manifestName(outdir string) string {
+	data, err := os.ReadFile(filepath.Join(outdir, "board.json"))
+	if err != nil {
+		return ""
+	}
+	var manifest board.BoardManifest
+	if json.Unmarshal(data, &manifest) != nil {
+		return ""
+	}
+	return manifest.Name
+}
+
+func TestWatchAndWriteReloads(t *testing.T) {
+	dir, err := os.MkdirTemp(".", "watch-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	file := filepath.Join(dir, "board.cue")
+	writeTestBoard(t, file, "Test")
+
+	job := irJob{
+		file:   file,
+		outdir: t.TempDir(),
+		loader: board.NewLoader(board.LoadOptions{}),
+		prev:   new(map[string]map[string]any),
+	}
+	logs := newLogger(io.Discard, levelError)
+	if _, err := writeIR(job, logs); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchAndWrite(ctx, job, logs, nil)
+	}()
+	t.Cleanup(func() { cancel(); <-done })
+
+	// Both saves go through the same loader; the file is rewritten until
+	// the watcher, which may not be set up yet, picks it up
+	for _, name := range []string{"Renamed", "RenamedAgain"} {
+		deadline := time.Now().Add(20 * time.Second)
+		for manifestName(job.outdir) != name {
+			if time.Now().After(deadline) {
+				t.Fatalf("board.json name = %q, want %q", manifestName(job.outdir), name)
+			}
+			writeTestBoard(t, file, name)
+			time.Sleep(300 * time.Millisecond)
+		}
+	}
+}
+
+func TestRunWebServerServesIR(t *testing.T) {
+	outdir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outdir, "board.json"), []byte(`{"name":"Test"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runWebServer(ctx, ln, outdir, newLogger(io.Discard, levelError), newLiveHub(), func() error { return nil })
+	}()
+	t.Cleanup(func() { cancel(); <-done })
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/.board/board.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || string(body) != `{"name":"Test"}` {
+		t.Errorf("GET /.board/board.json = %d %q, want 200 with the manifest", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
+)
+
+// runStatus implements `emspec status`: print how many slices of each
+// context are at each devstatus, the delivery progress of the board.
+// Returns the exit code.
+func runStatus(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("status", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	var (
+		file       = fset.String("file", "", "CUE file, package directory or glob of .cue files to load (required; - reads standard input)")
+		boardName  = fset.String("board", "", "Board name, or #N for the Nth board in name order, from 0 (default: first declared)")
+		modRoot    = fset.String("module-root", "", "CUE module root (default: discovered from the board file's directory)")
+		eventsFile = fset.String("events-file", "", "CUE file with shared top-level events merged into the board")
+	)
+	if err := fset.Parse(args); err != nil {
+		return 1
+	}
+	if *file == "" {
+		fmt.Fprintln(stderr, "error: -file is required")
+		fset.Usage()
+		return 1
+	}
+
+	loadOpts := board.LoadOptions{ModuleRoot: *modRoot, EventsFile: *eventsFile}
+	if err := readStdinSource(*file, &loadOpts); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	b, _, err := board.LoadBoardPermissiveWithOptions(*file, *boardName, loadOpts)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	manifest, slices, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	printStatus(stdout, board.StatusSummary(manifest, slices))
+	return 0
+}
+
+// printStatus prints the summary as a table, a column per status and a
+// total row.
+func printStatus(out io.Writer, summary []board.ContextStatus) {
+	statuses := append(append([]string{}, render.DevStatuses...), board.StatusUnspecified)
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	row := func(name string, counts map[string]int, n int) {
+		fmt.Fprint(tw, name)
+		for _, s := range statuses {
+			fmt.Fprintf(tw, "\t%d", counts[s])
+		}
+		fmt.Fprintf(tw, "\t%d\n", n)
+	}
+
+	fmt.Fprint(tw, "CONTEXT")
+	for _, s := range statuses {
+		fmt.Fprintf(tw, "\t%s", strings.ToUpper(s))
+	}
+	fmt.Fprintln(tw, "\tTOTAL")
+	totals := make(map[string]int)
+	total := 0
+	for _, cs := range summary {
+		row(cs.Context, cs.Counts, cs.Total)
+		for s, n := range cs.Counts {
+			totals[s] += n
+		}
+		total += cs.Total
+	}
+	row("(all)", totals, total)
+	tw.Flush()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
+)
+
+// Exit codes of emspec validate.
+const (
+	validateClean       = 0 // no diagnostics
+	validateDiagnostics = 1 // board loads, validation reported diagnostics
+	validateBuildError  = 2 // board doesn't load (CUE build error, bad flags)
+)
+
+// validateDiagnostic is one diagnostic of emspec validate -format json.
+type validateDiagnostic struct {
+	render.ValidationError
+	Severity string `json:"severity"`
+}
+
+// runValidate implements `emspec validate`: load the board, print its
+// diagnostics and return the exit code.
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	var (
+		file       = fset.String("file", "", "CUE file, package directory or glob of .cue files to load (required; - reads standard input)")
+		boardName  = fset.String("board", "", "Board name, or #N for the Nth board in name order, from 0 (default: first declared)")
+		format     = fset.String("format", "text", "Output format (text, json)")
+		modRoot    = fset.String("module-root", "", "CUE module root (default: discovered from the board file's directory)")
+		eventsFile = fset.String("events-file", "", "CUE file with shared top-level events merged into the board")
+	)
+	if err := fset.Parse(args); err != nil {
+		return validateBuildError
+	}
+	if *file == "" {
+		fmt.Fprintln(stderr, "error: -file is required")
+		fset.Usage()
+		return validateBuildError
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "error: unknown format %q\n", *format)
+		return validateBuildError
+	}
+
+	loadOpts := board.LoadOptions{ModuleRoot: *modRoot, EventsFile: *eventsFile}
+	if err := readStdinSource(*file, &loadOpts); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return validateBuildError
+	}
+	b, warnings, err := board.LoadBoardPermissiveWithOptions(*file, *boardName, loadOpts)
+
+	var diags []validateDiagnostic
+	code := validateClean
+	if err != nil {
+		diags = []validateDiagnostic{{ValidationError: render.ValidationError{Message: err.Error()}, Severity: render.SeverityError}}
+		code = validateBuildError
+	} else {
+		for _, d := range b.Diagnostics {
+			diags = append(diags, validateDiagnostic{ValidationError: d, Severity: render.CodeSeverity(d.Code)})
+		}
+		if len(diags) > 0 {
+			code = validateDiagnostics
+		}
+	}
+
+	if *format == "json" {
+		if diags == nil {
+			diags = []validateDiagnostic{}
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(diags)
+		return code
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return code
+	}
+	for _, w := range warnings {
+		fmt.Fprintln(stdout, w)
+	}
+	return code
+}
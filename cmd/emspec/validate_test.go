@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
+)
+
+// validateBoardSrc is a one-slice board. VERB is its endpoint verb and GHOST
+// the event it queries, declared but only emitted when it is Done.
+const validateBoardSrc = `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		Done: {fields: {a: string}, tags: []}
+		GHOST: {fields: {a: string}, tags: []}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "Emit"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "VERB", params: {}, body: {a: string}, path: "/test"}}
+				command: {name: "Emit", fields: {a: string}, query: {items: [{types: [events.GHOST], tags: []}]}}
+				emits: [events.Done]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+
+func TestRunValidate(t *testing.T) {
+	// Boards are written under the module so the em import resolves, each
+	// in its own directory (the files of a directory form one package)
+	dir, err := os.MkdirTemp(".", "validate-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, verb, ghost string) string {
+		src := strings.NewReplacer("VERB", verb, "GHOST", ghost).Replace(validateBoardSrc)
+		path := filepath.Join(dir, name, "board.cue")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	clean := write("clean", "POST", "Done")
+	warned := write("warned", "POST", "Ghost")
+	broken := write("broken", "POSTT", "Done")
+
+	const ghostWarning = `slice "Emit" queries event "Ghost", which no slice emits`
+	cases := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string // expected substring; "" means no output
+		stderr string
+		json   []validateDiagnostic // with -format json, the decoded stdout (messages as substrings)
+	}{
+		{name: "clean", args: []string{"-file", clean}, code: validateClean},
+		{name: "example", args: []string{"-file", "../../examples/cart.cue"}, code: validateClean},
+		{name: "diagnostics", args: []string{"-file", warned}, code: validateDiagnostics, stdout: "E320: " + ghostWarning},
+		{name: "build error", args: []string{"-file", broken}, code: validateBuildError, stderr: `E107: slice at flow index 0: endpoint verb "POSTT"`},
+		{name: "missing file flag", code: validateBuildError, stderr: "-file is required"},
+		{name: "unknown format", args: []string{"-file", clean, "-format", "xml"}, code: validateBuildError, stderr: `unknown format "xml"`},
+		{name: "json clean", args: []string{"-file", clean, "-format", "json"}, code: validateClean, json: []validateDiagnostic{}},
+		{name: "json diagnostics", args: []string{"-file", warned, "-format", "json"}, code: validateDiagnostics, json: []validateDiagnostic{
+			{ValidationError: render.ValidationError{Code: "E320", Message: ghostWarning}, Severity: render.SeverityWarning},
+		}},
+		{name: "json build error", args: []string{"-file", broken, "-format", "json"}, code: validateBuildError, json: []validateDiagnostic{
+			{ValidationError: render.ValidationError{Message: `E107: slice at flow index 0: endpoint verb "POSTT"`}, Severity: render.SeverityError},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := runValidate(c.args, &stdout, &stderr); code != c.code {
+				t.Fatalf("exit code = %d, want %d (stdout %q, stderr %q)", code, c.code, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stderr.String(), c.stderr) || (c.stderr == "" && stderr.Len() != 0) {
+				t.Errorf("stderr = %q, want %q", stderr.String(), c.stderr)
+			}
+			if c.json == nil {
+				if !strings.Contains(stdout.String(), c.stdout) || (c.stdout == "" && stdout.Len() != 0) {
+					t.Errorf("stdout = %q, want %q", stdout.String(), c.stdout)
+				}
+				return
+			}
+
+			// A JSON array, never null, of {code, message, severity, ...}
+			var got []validateDiagnostic
+			if err := json.Unmarshal(stdout.Bytes(), &got); err != nil || got == nil {
+				t.Fatalf("stdout %q is not a JSON array: %v", stdout.String(), err)
+			}
+			if len(got) != len(c.json) {
+				t.Fatalf("got %d diagnostics, want %d: %+v", len(got), len(c.json), got)
+			}
+			for i, want := range c.json {
+				d := got[i]
+				if d.Code != want.Code || d.Severity != want.Severity || !strings.Contains(d.Message, want.Message) {
+					t.Errorf("diagnostic %d = %+v, want %+v", i, d, want)
+				}
+			}
+		})
+	}
+}
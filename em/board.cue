@@ -143,8 +143,8 @@ import (
 					}
 				}
 
-				// Validate endpoint path params exist in params
-				if strings.Contains(inst.trigger.endpoint.path, "{") {
+				// Validate endpoint path params exist in params (malformed paths are E108 in Go)
+				if regexp.Match("\\{\\w+\\}", inst.trigger.endpoint.path) {
 					let _pathParams = [for m in regexp.FindAllSubmatch("\\{(\\w+)\\}", inst.trigger.endpoint.path, -1) {m[1]}]
 					for p in _pathParams {
 						("slice_\(inst.name)_endpoint_path_param_\(p)_must_be_in_params"): list.Contains(_paramFields, p) & true
@@ -171,6 +171,25 @@ import (
 				}
 			}
 
+			// UI trigger: fields from ui.fields
+			if inst.trigger.kind == "ui" {
+				let _uiFields = [for k, _ in inst.trigger.ui.fields {k}]
+				for fieldName, fieldType in inst.command.fields {
+					let inUI = list.Contains(_uiFields, fieldName)
+					let isComputed = list.Contains(_computedFields, fieldName)
+					let inMapping = list.Contains(_mappedFields, fieldName)
+					("slice_\(inst.name)_field_\(fieldName)_must_come_from_trigger"): (inUI | isComputed | inMapping) & true
+
+					// Type validation (skip computed)
+					if isComputed == false && inMapping == true {
+						("slice_\(inst.name)_field_\(fieldName)_type"): inst.command.mapping[fieldName] & fieldType
+					}
+					if isComputed == false && inMapping == false && inUI == true {
+						("slice_\(inst.name)_field_\(fieldName)_type"): inst.trigger.ui.fields[fieldName] & fieldType
+					}
+				}
+			}
+
 			// InternalEvent trigger: fields from internalEvent.fields, causality check
 			if inst.trigger.kind == "internalEvent" {
 				// Causality: the triggering event must be emitted before this slice
@@ -524,7 +543,7 @@ import (
 
 			// Validate endpoint path params exist in params (only if endpoint defined)
 			if inst.endpoint != _|_ {
-				if strings.Contains(inst.endpoint.path, "{") {
+				if regexp.Match("\\{\\w+\\}", inst.endpoint.path) {
 					let _pathParams = [for m in regexp.FindAllSubmatch("\\{(\\w+)\\}", inst.endpoint.path, -1) {m[1]}]
 					let _endpointParams = [for k, _ in inst.endpoint.params {k}]
 					for p in _pathParams {
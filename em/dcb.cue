@@ -25,12 +25,16 @@ package em
 //   - AND event has ALL of the listed tags (AND/intersection)
 //
 // Fields:
-//   types: [...#Event] - event types to match (OR semantics)
+//   types: [...#Event] - event types to match (OR semantics); [] matches
+//     every event type carrying the tags (tag-only query)
 //   tags: [...#Tag | #TagRef] - required tags (AND semantics)
 //
 // Example: Get all cart events for a specific cart
 //   {types: [_events.CartCreated, _events.ItemAdded],
 //    tags: [{tag: tags.cartId, value: command.fields.cartId}]}
+//
+// Example: Get every event of a specific cart, whatever its type
+//   {types: [], tags: [{tag: tags.cartId, value: command.fields.cartId}]}
 #QueryItem: {
 	types!: [...#Event]          // OR - event matches if ANY (reference board events)
 	tags: [...#Tag | #TagRef] | *[]   // AND - event must have ALL
@@ -21,9 +21,12 @@ package em
 //     path: "/carts/{cartId}/items"
 //   }
 #Endpoint: {
-	verb!:   "GET" | "POST" | "PUT" | "PATCH" | "DELETE"
+	verb!:   #HTTPVerb
 	params!: #Field
 	body:    #Field | *{}
 	auth:    #Field | *{}
 	path!:   string
 }
+
+// #HTTPVerb - HTTP method of an endpoint, uppercase
+#HTTPVerb: "GET" | "POST" | "PUT" | "PATCH" | "DELETE"
@@ -138,6 +138,9 @@ package em
 	// Optional relative path to mockup/screenshot
 	image?: string
 
+	// Automations run without an actor
+	actor?: null
+
 	trigger!: #AutomationTrigger
 
 	// ReadModels consumed by this automation - their fields available to command
@@ -8,6 +8,7 @@ package em
 //   slice: #ChangeSlice - direct reference to the change slice
 //   description?: string - narrative context for this step
 //   image?: string - optional illustration
+//   actor?: #Actor - who performs this step (must be defined in board.actors)
 //   emits?: [...#EventInstance] - concrete event instances emitted in this step
 #ChangeStoryStep: {
 	kind: "story"
@@ -15,6 +16,7 @@ package em
 	slice: #ChangeSlice
 	description: string | *""
 	image?:      string
+	actor?:      #Actor
 	emits?: [...#EventInstance]
 }
 
@@ -26,6 +28,7 @@ package em
 //   slice: #ViewSlice - direct reference to the view slice
 //   description?: string - narrative context for this step
 //   image?: string - optional illustration
+//   actor?: #Actor - who looks at this step (must be defined in board.actors)
 //   instance?: slice.readModel.fields - concrete read model instance for this step
 #ViewStoryStep: {
 	kind: "story"
@@ -33,6 +36,7 @@ package em
 	slice: #ViewSlice
 	description: string | *""
 	image?:      string
+	actor?:      #Actor
 	instance?:   slice.readModel.fields
 }
 
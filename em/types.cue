@@ -88,7 +88,8 @@ package em
 //   kind: "endpoint" -> HTTP request
 //   kind: "externalEvent" -> event from external system
 //   kind: "internalEvent" -> event from within this board (automation)
-#Trigger: #EndpointTrigger | #ExternalEventTrigger | #InternalEventTrigger
+//   kind: "ui" -> user action in the UI, no endpoint
+#Trigger: #EndpointTrigger | #ExternalEventTrigger | #InternalEventTrigger | #UITrigger
 
 // #AutomationTrigger - Triggers for automation slices (no endpoint)
 //
@@ -105,6 +106,23 @@ package em
 	externalEvent: #ExternalEvent
 }
 
+// #UIAction - User action in the UI that triggers a command without an endpoint
+//
+// Command fields must come from the action's fields, mapping, or computed.
+//
+// Fields:
+//   name: string - action identifier (e.g., "ClickCheckout")
+//   fields: #Field - data the UI provides with the action
+#UIAction: {
+	name!:   string
+	fields!: #Field
+}
+
+#UITrigger: {
+	kind: "ui"
+	ui:   #UIAction
+}
+
 // #InternalEventTrigger - Automation trigger from internal event
 //
 // Triggers a command when an event is emitted within this board.
@@ -125,7 +143,7 @@ package em
 //   fields: #Field - command input schema (must match endpoint inputs)
 //   query: #DCBQuery - events to load for consistency check before emitting
 //   dependentQuery?: #DependentQuery - optional second-phase query using extracted values
-//   computed?: #Field - fields derived at runtime (e.g., timestamp, generated IDs)
+//   computed?: {[string]: string | #CommandComputed} - fields derived at runtime (e.g., timestamp, generated IDs)
 //   mapping?: #Field - rename endpoint fields (cmdField: endpoint.params.x or endpoint.body.x)
 #Command: {
 	name:    string
@@ -134,8 +152,8 @@ package em
 	query!: #DCBQuery
 	// Optional dependent query using values extracted from primary query
 	dependentQuery?: #DependentQuery
-	// Fields not from endpoint (computed) - field name → description
-	computed: {[string]: string} | *{}
+	// Fields not from endpoint (computed) - field name → description or {description, expr}
+	computed: {[string]: string | #CommandComputed} | *{}
 	// Field mapping: cmdField -> endpoint.params.x or endpoint.body.x
 	mapping: #Field | *{}
 
@@ -150,6 +168,19 @@ package em
 	}
 }
 
+// #CommandComputed - Command field derived at runtime
+//
+// Fields:
+//   description?: string - what the value is (e.g., "sum of line items")
+//   expr?: string - how it's derived (e.g., "sum(items.price)")
+//
+// Example:
+//   computed: {total: {description: "cart total", expr: "sum(items.price)"}}
+#CommandComputed: {
+	description?: string
+	expr?:        string
+}
+
 // #ComputedField - ReadModel field derived from event fields
 //
 // For view fields that aggregate or transform event data.
@@ -227,6 +258,10 @@ package em
 //   fields: #Field - output schema
 //   computed?: {[string]: #ComputedField} - aggregated/transformed fields
 //   mapping?: {[string]: #MappedField} - renamed fields (type-checked)
+//   references?: {[string]: string} - field → name of the read model it identifies
+//
+// Example: an OrderSummary pointing at the customer it belongs to
+//   references: {customerId: "CustomerView"}
 #ReadModel: {
 	name!:        string
 	cardinality!: "single" | "table"
@@ -243,4 +278,6 @@ package em
 	computed: {[string]: #ComputedField} | *{}
 	// Fields renamed from event fields (type must match)
 	mapping: {[string]: #MappedField} | *{}
+	// Fields holding the id of another read model (field → read model name)
+	references: {[string]: string} | *{}
 }
@@ -5,8 +5,8 @@ import "github.com/err0r500/event-modeling-dcb-spec/em"
 // Package-level definitions for use in separate files
 _tags: [Name=string]: em.#Tag & {name: Name}
 _tags: {
-	item_id:    {param: "itemId", type: int}
-	shopper_id: {param: "shopper_id", type: string}
+	item_id:    {param: "itemId", type: string}
+	shopper_id: {param: "shopperId", type: string}
 	cart_id:    {param: "cartId", type: string}
 	product_id: {param: "productId", type: string}
 }
@@ -42,6 +42,7 @@ cartBoard: em.#Board & {
 						ViewEmptyCart,
 						AddOneItemCartStory,
 						ViewCartItems,
+						DeleteCart,
 					]
 				},
 				{
@@ -52,6 +53,14 @@ cartBoard: em.#Board & {
 						ViewProductsInventories,
 					]
 				},
+				{
+					name:        "Submit Cart"
+					description: "Customer submits the cart"
+					flow: [
+						SubmitCart,
+						AutoCloseCart,
+					]
+				},
 				{
 					name:        "Price Change"
 					description: "Handles changes from the pricing context"
@@ -62,14 +71,6 @@ cartBoard: em.#Board & {
 						ArchiveItems,
 					]
 				},
-				{
-					name:        "Submit Cart"
-					description: "Customer submits the cart"
-					flow: [
-						SubmitCart,
-						AutoCloseCart,
-					]
-				},
 			]
 		},
 		{
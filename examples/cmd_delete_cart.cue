@@ -0,0 +1,37 @@
+package examples
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+DeleteCart: em.#ChangeSlice & {
+	name:  "DeleteCart"
+	actor: _actors.User
+
+	trigger: em.#EndpointTrigger & {
+		endpoint: {
+			verb: "DELETE"
+			params: {cartId: string}
+			auth: {userId: string}
+			path: "/carts/{cartId}"
+		}
+	}
+
+	command: {
+		fields: {
+			cartId:    string
+			shopperId: string
+		}
+
+		mapping: {shopperId: trigger.endpoint.auth.userId}
+
+		query: {
+			items: [{
+				types: [_events.CartCreated, _events.CartDeleted]
+				tags: [{tag: _tags.cart_id, value: fields.cartId}]
+			}]
+		}
+	}
+
+	emits: [
+		_events.CartDeleted,
+	]
+}
@@ -0,0 +1,53 @@
+package board
+
+// SliceComplexity returns a rough sizing score for a reified slice:
+//
+//	query items (primary + dependent)
+//	+ fields (command fields, or read model fields for views)
+//	+ scenarios
+//	+ emitted events
+//
+// Each counts 1 per entry; nested fields are not counted. The score only
+// depends on the slice data, so it is stable across runs and round trips.
+// Stories and unknown kinds score 0.
+func SliceComplexity(data map[string]any) int {
+	if data["kind"] != "slice" {
+		return 0
+	}
+
+	score := len(asList(data["scenarios"])) + len(asList(data["emits"]))
+
+	switch data["type"] {
+	case "view":
+		score += len(asList(data["query"]))
+		score += len(asList(asMap(data["dependentQuery"])["items"]))
+		score += len(asMap(asMap(data["readModel"])["fields"]))
+	default:
+		cmd := asMap(data["command"])
+		score += len(asList(cmd["query"]))
+		score += len(asList(asMap(cmd["dependentQuery"])["items"]))
+		score += len(asMap(cmd["fields"]))
+	}
+	return score
+}
+
+// asList returns v as a list, accepting in-memory and JSON-decoded forms.
+func asList(v any) []any {
+	switch l := v.(type) {
+	case []any:
+		return l
+	case []map[string]any:
+		out := make([]any, len(l))
+		for i, m := range l {
+			out[i] = m
+		}
+		return out
+	}
+	return nil
+}
+
+// asMap returns v as a map, or nil.
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
@@ -0,0 +1,167 @@
+package board
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SliceEmits returns the event types a reified slice emits, in declaration order.
+func SliceEmits(data map[string]any) []string {
+	var out []string
+	for _, e := range asList(data["emits"]) {
+		if t, _ := asMap(e)["type"].(string); t != "" && !slices.Contains(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// SliceConsumes returns the event types a reified slice queries (the view
+// query, or the command query of change and automation slices, then their
+// dependent query), in declaration order without duplicates.
+func SliceConsumes(data map[string]any) []string {
+	src := data
+	if data["type"] != "view" {
+		src = asMap(data["command"])
+	}
+	items := asList(src["query"])
+	items = append(items, asList(asMap(src["dependentQuery"])["items"])...)
+
+	var out []string
+	for _, item := range items {
+		for _, t := range asStrings(asMap(item)["types"]) {
+			if !slices.Contains(out, t) {
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
+
+// SliceTriggerEvent returns the event triggering a reified change or
+// automation slice: the external event name or the internal event type.
+// It returns "" for other triggers.
+func SliceTriggerEvent(data map[string]any) string {
+	trigger := asMap(data["trigger"])
+	switch trigger["kind"] {
+	case "externalEvent":
+		name, _ := asMap(trigger["externalEvent"])["name"].(string)
+		return name
+	case "internalEvent":
+		eventType, _ := asMap(trigger["internalEvent"])["eventType"].(string)
+		return eventType
+	}
+	return ""
+}
+
+// SliceInputs lists what a reified slice consumes: the event triggering it
+// (external or internal), then the event types it queries.
+func SliceInputs(data map[string]any) []string {
+	var out []string
+	if t := SliceTriggerEvent(data); t != "" {
+		out = append(out, t)
+	}
+	for _, t := range SliceConsumes(data) {
+		if !slices.Contains(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// CatalogEntry is an event type of a board with the slices emitting and
+// consuming it.
+type CatalogEntry struct {
+	Type      string
+	Fields    map[string]any // as emitted; nil for events no slice emits
+	Emitters  []string       // slice names, in flow order
+	Consumers []string       // slice names querying or triggered by the event, in flow order
+}
+
+// EventCatalog lists the event types the reified slices emit, query or are
+// triggered by, sorted by type. flow is the reified slice data in flow order
+// (see FlowSlices).
+func EventCatalog(flow []map[string]any) []CatalogEntry {
+	byType := map[string]*CatalogEntry{}
+	entry := func(eventType string) *CatalogEntry {
+		e, ok := byType[eventType]
+		if !ok {
+			e = &CatalogEntry{Type: eventType}
+			byType[eventType] = e
+		}
+		return e
+	}
+	add := func(names []string, name string) []string {
+		if slices.Contains(names, name) {
+			return names
+		}
+		return append(names, name)
+	}
+
+	for _, data := range flow {
+		name, _ := data["name"].(string)
+		for _, e := range asList(data["emits"]) {
+			eventType, _ := asMap(e)["type"].(string)
+			if eventType == "" {
+				continue
+			}
+			ce := entry(eventType)
+			ce.Emitters = add(ce.Emitters, name)
+			if ce.Fields == nil {
+				ce.Fields = asMap(asMap(e)["fields"])
+			}
+		}
+		if t := SliceTriggerEvent(data); t != "" {
+			ce := entry(t)
+			ce.Consumers = add(ce.Consumers, name)
+		}
+		for _, t := range SliceConsumes(data) {
+			ce := entry(t)
+			ce.Consumers = add(ce.Consumers, name)
+		}
+	}
+
+	out := make([]CatalogEntry, 0, len(byType))
+	for _, e := range byType {
+		out = append(out, *e)
+	}
+	slices.SortFunc(out, func(a, b CatalogEntry) int { return cmp.Compare(a.Type, b.Type) })
+	return out
+}
+
+// asStrings returns v as a string list, accepting in-memory and JSON-decoded forms.
+func asStrings(v any) []string {
+	switch l := v.(type) {
+	case []string:
+		return l
+	case []any:
+		var out []string
+		for _, s := range l {
+			if str, ok := s.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// EventFields returns the fields of every event declared in board.events,
+// keyed by event type, with nested structs and lists reified like read model
+// fields. Types CUE can't narrow to one kind keep their kind string ("(int|string)").
+func EventFields(b *Board) map[string]map[string]any {
+	out := map[string]map[string]any{}
+	iter, err := lookupPath(b.Value, "events").Fields()
+	if err != nil {
+		return out
+	}
+	for iter.Next() {
+		evt := iter.Value()
+		eventType := getString(evt, "eventType")
+		if eventType == "" {
+			eventType = selectorLabel(iter.Selector())
+		}
+		out[eventType] = reifyFieldsDeep(lookupPath(evt, "fields"))
+	}
+	return out
+}
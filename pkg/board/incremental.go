@@ -0,0 +1,58 @@
+package board
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
+)
+
+// IncrementalResult is the outcome of ReifyBoardFilesIncremental.
+type IncrementalResult struct {
+	Manifest BoardManifest
+	Slices   map[string]map[string]any // every slice file, for the next call's prev
+	Changed  map[string]map[string]any // slice files that differ from prev
+	Keep     map[string]bool           // slice files of the board
+	Images   []string
+}
+
+// ReifyBoardFilesIncremental is ReifyBoardFiles reporting which slice files
+// differ from prev (the Slices of the previous call), so that only those are
+// written. With nil prev every slice file is changed.
+//
+// Every slice is still reified: CUE values are rebuilt on each load and have
+// no identity across loads, and hashing one structurally (formatting or
+// walking it) costs more than reifying it.
+func ReifyBoardFilesIncremental(b *Board, diags []render.ValidationError, opts ReifyOptions, prev map[string]map[string]any) IncrementalResult {
+	res := IncrementalResult{
+		Changed: make(map[string]map[string]any),
+		Keep:    make(map[string]bool),
+	}
+	res.Manifest, res.Slices, res.Images = ReifyBoardFiles(b, diags, opts)
+	for filename, data := range res.Slices {
+		res.Keep[filename] = true
+		if old, ok := prev[filename]; !ok || !reflect.DeepEqual(old, data) {
+			res.Changed[filename] = data
+		}
+	}
+	return res
+}
+
+// WriteBoardFilesIncremental writes the result of ReifyBoardFilesIncremental
+// like WriteBoardFiles, but only the changed slice files (and unchanged ones
+// missing from outdir); the other kept files are left untouched.
+func WriteBoardFilesIncremental(outdir string, res IncrementalResult, srcDir string) ([]string, error) {
+	if err := os.MkdirAll(outdir, 0o755); err != nil {
+		return nil, err
+	}
+	write := make(map[string]map[string]any, len(res.Changed))
+	for filename, data := range res.Slices {
+		if _, changed := res.Changed[filename]; changed {
+			write[filename] = data
+		} else if _, err := os.Stat(filepath.Join(outdir, filename)); err != nil {
+			write[filename] = data
+		}
+	}
+	return writeBoardFiles(outdir, res.Manifest, write, res.Keep, srcDir, res.Images)
+}
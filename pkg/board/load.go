@@ -2,11 +2,18 @@ package board
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/load"
+	"cuelang.org/go/cue/token"
 
 	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
 )
@@ -16,6 +23,9 @@ type Board struct {
 	Name  string
 	Value cue.Value
 	Flow  []FlowItem
+	// Diagnostics are the board's validation diagnostics, broken into their
+	// parts; LoadBoardPermissive returns them formatted as its warnings.
+	Diagnostics []render.ValidationError
 }
 
 // FlowItem is a lightweight representation of one instant in the flow.
@@ -35,58 +45,393 @@ func LoadBoard(filePath, boardName string) (*Board, error) {
 		return nil, err
 	}
 	if len(warnings) > 0 {
-		return nil, fmt.Errorf("validation errors: %v", warnings)
+		return nil, ValidationErrors(warnings)
 	}
 	return b, nil
 }
 
+// ValidationErrors is the error of a board that loads but has diagnostics:
+// the formatted messages, one per line.
+type ValidationErrors []string
+
+func (e ValidationErrors) Error() string {
+	return "validation errors:\n" + strings.Join(e, "\n")
+}
+
+// LoadOptions tweaks how the CUE instance holding the board is loaded.
+type LoadOptions struct {
+	// ModuleRoot is the CUE module root directory (the one containing cue.mod).
+	// Empty means CUE discovers it from the board file's directory upwards.
+	ModuleRoot string
+	// EventsFile is a CUE file with a top-level `events` struct (e.g. an
+	// org-wide catalog) merged into the board's events before validation.
+	// Board-local events win; same-named events with another shape are reported.
+	EventsFile string
+	// Source, when set, is compiled instead of the board file: as a
+	// synthetic StdinFileName in the current directory (and its module), so
+	// relative image paths resolve against the working directory.
+	Source []byte
+}
+
+// StdinFileName is the name of the synthetic file LoadOptions.Source is
+// compiled as; CUE positions in diagnostics point at it.
+const StdinFileName = "stdin.cue"
+
 // LoadBoardPermissive loads a board, returning validation issues as warnings instead of errors.
 // Hard errors (CUE parse/build failures) are still returned as errors.
+// filePath is a .cue file (its whole package is loaded), a package
+// directory, or a glob pattern of .cue files in one directory.
 func LoadBoardPermissive(filePath, boardName string) (*Board, []string, error) {
-	absFile, err := filepath.Abs(filePath)
+	return LoadBoardPermissiveWithOptions(filePath, boardName, LoadOptions{})
+}
+
+// LoadBoardPermissiveWithOptions is LoadBoardPermissive with explicit load options.
+func LoadBoardPermissiveWithOptions(filePath, boardName string, opts LoadOptions) (*Board, []string, error) {
+	return loadBoard(cuecontext.New(), filePath, boardName, opts)
+}
+
+// LoadBoardFromSource loads a board from CUE source held in memory, like
+// LoadBoardPermissive does from a file, without writing it anywhere: src is
+// compiled through a load.Overlay as StdinFileName in the working directory,
+// so it may import the em package of the CUE module around it. The board
+// validates and reifies as if loaded from a file. There is no source
+// directory in this mode, so image copying is skipped: pass WriteBoardFiles
+// an empty srcDir.
+func LoadBoardFromSource(src, boardName string) (*Board, []string, error) {
+	return LoadBoardPermissiveWithOptions("", boardName, LoadOptions{Source: []byte(src)})
+}
+
+// Loader loads a board repeatedly in one persistent CUE context, instead of
+// a fresh one per load. The emspec watch loop and /.reload hold one for
+// every regeneration of the IR.
+type Loader struct {
+	mu   sync.Mutex
+	ctx  *cue.Context
+	opts LoadOptions
+}
+
+// NewLoader returns a Loader loading boards with opts.
+func NewLoader(opts LoadOptions) *Loader {
+	return &Loader{ctx: cuecontext.New(), opts: opts}
+}
+
+// Reload loads the board like LoadBoardPermissiveWithOptions, reusing the
+// loader's CUE context. Calls are serialized.
+func (l *Loader) Reload(filePath, boardName string) (*Board, []string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return loadBoard(l.ctx, filePath, boardName, l.opts)
+}
+
+// loadBoard loads a board, building its package in ctx.
+func loadBoard(ctx *cue.Context, filePath, boardName string, opts LoadOptions) (*Board, []string, error) {
+	cfg, v, err := buildPackage(ctx, filePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	boardVal := FindBoard(v, boardName)
+	if !boardVal.Exists() {
+		return nil, nil, boardNotFound(v, boardName)
+	}
+
+	// Merge shared events first: references to them are unresolved until then
+	var sharedWarnings []render.ValidationError
+	if opts.EventsFile != "" {
+		shared, err := loadSharedEvents(ctx, opts.EventsFile, cfg.ModuleRoot)
+		if err != nil {
+			return nil, nil, err
+		}
+		sharedWarnings = render.ValidateSharedEvents(lookupPath(boardVal, "events"), shared)
+		boardVal = mergeSharedEvents(boardVal, shared)
+	}
+
+	if boardVal.Err() != nil {
+		return nil, nil, fmt.Errorf("board: %s", render.FormatCUEError(boardVal.Err()))
+	}
+
+	diags := append(render.ValidateBoardStructured(boardVal), sharedWarnings...)
+
+	name := getString(boardVal, "name")
+	flow, err := extractFlow(boardVal)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &Board{Name: name, Value: boardVal, Flow: flow, Diagnostics: diags}, render.FormatDiagnostics(diags), nil
+}
+
+// loadSharedEvents builds the `events` struct of a shared events file in ctx.
+func loadSharedEvents(ctx *cue.Context, eventsFile, moduleRoot string) (cue.Value, error) {
+	absFile, err := filepath.Abs(eventsFile)
 	if err != nil {
-		return nil, nil, fmt.Errorf("abs path: %w", err)
+		return cue.Value{}, fmt.Errorf("events file: %w", err)
 	}
+	cfg := &load.Config{Dir: filepath.Dir(absFile), ModuleRoot: moduleRoot}
+	instances := load.Instances([]string{absFile}, cfg)
+	if len(instances) == 0 {
+		return cue.Value{}, fmt.Errorf("events file: no instances loaded")
+	}
+	if instances[0].Err != nil {
+		return cue.Value{}, fmt.Errorf("events file: %w", instances[0].Err)
+	}
+
+	v := ctx.BuildInstance(instances[0])
+	if err := v.Validate(cue.All()); err != nil {
+		return cue.Value{}, fmt.Errorf("events file: %s", render.FormatCUEError(err))
+	}
+	events := lookupPath(v, "events")
+	if !events.Exists() {
+		return cue.Value{}, fmt.Errorf("events file %s: no top-level events", eventsFile)
+	}
+	return events, nil
+}
 
-	cfg := &load.Config{Dir: filepath.Dir(absFile)}
-	instances := load.Instances([]string{"."}, cfg)
+// mergeSharedEvents adds the shared events the board doesn't define itself.
+func mergeSharedEvents(boardVal, shared cue.Value) cue.Value {
+	iter, err := shared.Fields()
+	if err != nil {
+		return boardVal
+	}
+	for iter.Next() {
+		path := cue.MakePath(cue.Str("events"), iter.Selector())
+		if boardVal.LookupPath(path).Exists() {
+			continue
+		}
+		boardVal = boardVal.FillPath(path, iter.Value())
+	}
+	return boardVal
+}
+
+// buildPackage loads and builds the CUE package of filePath (see
+// packageArgs), or of opts.Source when set, in ctx.
+func buildPackage(ctx *cue.Context, filePath string, opts LoadOptions) (*load.Config, cue.Value, error) {
+	args := []string{"."}
+	var overlay map[string]load.Source
+	if opts.Source != nil {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, cue.Value{}, fmt.Errorf("working directory: %w", err)
+		}
+		filePath = filepath.Join(cwd, StdinFileName)
+		overlay = map[string]load.Source{filePath: load.FromBytes(opts.Source)}
+		args = []string{"./" + StdinFileName}
+	}
+	dir := filepath.Dir(filePath)
+	if opts.Source == nil {
+		var err error
+		if dir, args, err = packageArgs(filePath); err != nil {
+			return nil, cue.Value{}, err
+		}
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, cue.Value{}, fmt.Errorf("abs path: %w", err)
+	}
+
+	cfg := &load.Config{Dir: absDir, Overlay: overlay}
+	if opts.ModuleRoot != "" {
+		root, err := filepath.Abs(opts.ModuleRoot)
+		if err != nil {
+			return nil, cue.Value{}, fmt.Errorf("module root: %w", err)
+		}
+		cfg.ModuleRoot = root
+	}
+	instances := load.Instances(args, cfg)
 	if len(instances) == 0 {
-		return nil, nil, fmt.Errorf("no instances loaded")
+		return nil, cue.Value{}, fmt.Errorf("no instances loaded")
 	}
 
 	inst := instances[0]
 	if inst.Err != nil {
-		return nil, nil, fmt.Errorf("load: %w", inst.Err)
+		return nil, cue.Value{}, fmt.Errorf("load: %w", inst.Err)
 	}
 
-	ctx := cuecontext.New()
 	v := ctx.BuildInstance(inst)
 	// Use Validate(All) to get full error details including type mismatches
 	if err := v.Validate(cue.All()); err != nil {
-		return nil, nil, fmt.Errorf("build: %s", render.FormatCUEError(err))
+		errs := errors.Append(errors.Promote(err, ""), flowElementErrors(v, err))
+		return nil, cue.Value{}, fmt.Errorf("build: %s", render.FormatCUEError(errs))
 	}
+	return cfg, v, nil
+}
 
-	boardVal := FindBoard(v, boardName)
-	if !boardVal.Exists() {
-		return nil, nil, fmt.Errorf("board not found: %q", boardName)
+// flowElementErrors validates the flow elements of v's boards one by one,
+// returning the errors of those err doesn't report. CUE stops at the first
+// failing element of a list, so of several invalid slices only the first
+// would be reported. The errors are put under the element's index in the
+// board's flat flow, where CUE reports that first one.
+func flowElementErrors(v cue.Value, err error) errors.Error {
+	reported := make(map[string]bool) // "<board>.flow.<index>"
+	for _, e := range errors.Errors(err) {
+		if path := e.Path(); len(path) >= 3 && path[1] == "flow" {
+			reported[strings.Join(path[:3], ".")] = true
+		}
 	}
-	if boardVal.Err() != nil {
-		return nil, nil, fmt.Errorf("board: %s", render.FormatCUEError(boardVal.Err()))
+
+	var errs errors.Error
+	iter, iterErr := v.Fields()
+	if iterErr != nil {
+		return nil
 	}
+	for iter.Next() {
+		contexts := lookupPath(iter.Value(), "contexts")
+		index := 0
+		for c := 0; ; c++ {
+			chapters := contexts.LookupPath(cue.MakePath(cue.Index(c), cue.Str("chapters")))
+			if !chapters.Exists() {
+				break
+			}
+			for h := 0; ; h++ {
+				flow := chapters.LookupPath(cue.MakePath(cue.Index(h), cue.Str("flow")))
+				if !flow.Exists() {
+					break
+				}
+				for i := 0; ; i++ {
+					elem := flow.LookupPath(cue.MakePath(cue.Index(i)))
+					if !elem.Exists() {
+						break
+					}
+					to := []string{iter.Selector().String(), "flow", strconv.Itoa(index)}
+					if !reported[strings.Join(to, ".")] {
+						for _, e := range errors.Errors(elem.Validate(cue.All())) {
+							if len(e.Path()) >= 7 {
+								errs = errors.Append(errs, &flowError{err: e, to: to})
+							}
+						}
+					}
+					index++
+				}
+			}
+		}
+	}
+	return errs
+}
 
-	warnings := render.ValidateBoard(boardVal)
+// flowError is a CUE error on the element of a context's chapter's flow,
+// reported under the element's path in the board's flat flow.
+type flowError struct {
+	err errors.Error
+	to  []string // the "<board>.flow.<index>" path
+}
 
-	name := getString(boardVal, "name")
-	flow, err := extractFlow(boardVal)
+func (e *flowError) Position() token.Pos         { return e.err.Position() }
+func (e *flowError) InputPositions() []token.Pos { return e.err.InputPositions() }
+func (e *flowError) Msg() (string, []any)        { return e.err.Msg() }
+
+// Path replaces the "<board>.contexts.C.chapters.H.flow.I" prefix.
+func (e *flowError) Path() []string {
+	return append(slices.Clone(e.to), e.err.Path()[7:]...)
+}
+
+func (e *flowError) Error() string {
+	from := strings.Join(e.err.Path()[:7], ".")
+	return strings.Replace(e.err.Error(), from, strings.Join(e.to, "."), 1)
+}
+
+// packageArgs resolves a board path to the directory to load from and the
+// load.Instances arguments. A file loads its whole package, so a board may
+// be split across the .cue files of its directory; a directory loads the
+// package in it; a glob pattern (e.g. "board/*.cue") loads just the
+// matching files, which must share a directory.
+func packageArgs(filePath string) (dir string, args []string, err error) {
+	if info, err := os.Stat(filePath); err == nil && info.IsDir() {
+		return filePath, []string{"."}, nil
+	}
+	if !strings.ContainsAny(filePath, "*?[") {
+		return filepath.Dir(filePath), []string{"."}, nil
+	}
+
+	matches, err := filepath.Glob(filePath)
 	if err != nil {
-		return nil, nil, err
+		return "", nil, fmt.Errorf("file pattern %q: %w", filePath, err)
+	}
+	dir = filepath.Dir(filePath)
+	for _, m := range matches {
+		if filepath.Ext(m) != ".cue" {
+			continue
+		}
+		if filepath.Dir(m) != dir {
+			return "", nil, fmt.Errorf("file pattern %q matches files outside %s; a CUE instance is one directory", filePath, dir)
+		}
+		args = append(args, "./"+filepath.Base(m))
+	}
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("file pattern %q matches no .cue file", filePath)
 	}
+	return dir, args, nil
+}
 
-	return &Board{Name: name, Value: boardVal, Flow: flow}, warnings, nil
+// SourceDir returns the directory of the board loaded from filePath (a
+// file, a package directory or a glob pattern, see LoadBoardPermissive):
+// the directory itself, or the one holding the file or the pattern's
+// matches. Relative image paths in the board resolve against it, whichever
+// file of the package (including files in parent directories of the same
+// CUE package) declares them. It is "." for standard input.
+func SourceDir(filePath string) string {
+	if info, err := os.Stat(filePath); err == nil && info.IsDir() {
+		return filePath
+	}
+	return filepath.Dir(filePath)
 }
 
-// FindBoard finds a board in the CUE value by name, or returns the first board found.
+// ListBoardsInFile returns the boards of the CUE package of filePath (see ListBoards).
+func ListBoardsInFile(filePath string, opts LoadOptions) ([]string, error) {
+	_, v, err := buildPackage(cuecontext.New(), filePath, opts)
+	if err != nil {
+		return nil, err
+	}
+	return ListBoards(v), nil
+}
+
+// ListBoards returns the top-level fields of v that have a board shape (a
+// flow list), sorted by name. They are the valid board names, and "#N"
+// selects the Nth of them.
+func ListBoards(v cue.Value) []string {
+	var names []string
+	for _, b := range boards(v) {
+		names = append(names, b.name)
+	}
+	return names
+}
+
+// namedBoard is a board-shaped top-level field.
+type namedBoard struct {
+	name  string
+	value cue.Value
+}
+
+// boards returns the boards of v sorted by name: unlike declaration order,
+// the order doesn't change when the package's files or fields are
+// rearranged.
+func boards(v cue.Value) []namedBoard {
+	iter, err := v.Fields()
+	if err != nil {
+		return nil
+	}
+	var found []namedBoard
+	for iter.Next() {
+		if isBoard(iter.Value()) {
+			found = append(found, namedBoard{selectorLabel(iter.Selector()), iter.Value()})
+		}
+	}
+	slices.SortFunc(found, func(a, b namedBoard) int { return strings.Compare(a.name, b.name) })
+	return found
+}
+
+// isBoard reports whether v has a board shape.
+func isBoard(v cue.Value) bool {
+	flow := lookupPath(v, "flow")
+	return flow.Err() == nil && flow.IncompleteKind() == cue.ListKind
+}
+
+// FindBoard finds a board in the CUE value by name, or returns the first
+// board declared. A name of the form "#N" selects the board at index N
+// instead (see FindBoardByIndex).
 func FindBoard(v cue.Value, boardName string) cue.Value {
+	if n, ok := boardIndex(boardName); ok {
+		return FindBoardByIndex(v, n)
+	}
 	if boardName != "" {
 		return v.LookupPath(cue.ParsePath(boardName))
 	}
@@ -95,16 +440,48 @@ func FindBoard(v cue.Value, boardName string) cue.Value {
 		return cue.Value{}
 	}
 	for iter.Next() {
-		val := iter.Value()
-		if flow := val.LookupPath(cue.ParsePath("flow")); flow.Err() == nil {
+		if val := iter.Value(); isBoard(val) {
 			return val
 		}
 	}
 	return cue.Value{}
 }
 
+// FindBoardByIndex returns the board at zero-based index n among the boards
+// of v, sorted by name (see ListBoards): for scripts over packages whose
+// boards keep their names but not their place in the files.
+func FindBoardByIndex(v cue.Value, n int) cue.Value {
+	found := boards(v)
+	if n < 0 || n >= len(found) {
+		return cue.Value{}
+	}
+	return found[n].value
+}
+
+// boardIndex parses a "#N" board selector.
+func boardIndex(boardName string) (int, bool) {
+	digits, ok := strings.CutPrefix(boardName, "#")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(digits)
+	return n, err == nil
+}
+
+// boardNotFound explains why FindBoard(v, boardName) found nothing.
+func boardNotFound(v cue.Value, boardName string) error {
+	boards := ListBoards(v)
+	if len(boards) == 0 {
+		return fmt.Errorf("no board found (a top-level value with a flow)")
+	}
+	if n, ok := boardIndex(boardName); ok {
+		return fmt.Errorf("board #%d out of range: there are %d boards (#0-#%d): %s", n, len(boards), len(boards)-1, strings.Join(boards, ", "))
+	}
+	return fmt.Errorf("board %q not found; available boards: %s", boardName, strings.Join(boards, ", "))
+}
+
 func extractFlow(boardVal cue.Value) ([]FlowItem, error) {
-	flowVal := boardVal.LookupPath(cue.ParsePath("flow"))
+	flowVal := lookupPath(boardVal, "flow")
 	if flowVal.Err() != nil {
 		return nil, fmt.Errorf("flow not found: %w", flowVal.Err())
 	}
@@ -143,8 +520,24 @@ func extractFlow(boardVal cue.Value) ([]FlowItem, error) {
 	return items, nil
 }
 
+// parsedPaths caches cue.ParsePath results: reify looks up the same few dozen
+// paths on every slice, and parsing them dominates large boards. Only the
+// package's literal paths go through it, so it stays as small as that set;
+// paths built from user input (board names) are parsed uncached.
+var parsedPaths sync.Map // string → cue.Path
+
+// lookupPath is v.LookupPath(cue.ParsePath(path)) with the parse cached.
+// path must be a literal (see parsedPaths).
+func lookupPath(v cue.Value, path string) cue.Value {
+	p, ok := parsedPaths.Load(path)
+	if !ok {
+		p, _ = parsedPaths.LoadOrStore(path, cue.ParsePath(path))
+	}
+	return v.LookupPath(p.(cue.Path))
+}
+
 func getString(v cue.Value, path string) string {
-	val := v.LookupPath(cue.ParsePath(path))
+	val := lookupPath(v, path)
 	if val.Err() != nil {
 		return ""
 	}
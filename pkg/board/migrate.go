@@ -0,0 +1,39 @@
+package board
+
+import "fmt"
+
+// IRSchemaVersion is the version of the IR layout written by this package.
+// Bump it when the manifest or slice files change shape, and teach
+// MigrateManifest how to read the previous version.
+//
+// History:
+//
+//	0 - unversioned: no context/chapter hierarchy
+//	1 - contexts with chapters indexing into the flat flow
+const IRSchemaVersion = 1
+
+// MigrateManifest upgrades a manifest read from disk to IRSchemaVersion in place.
+// It returns warnings for versions it can't migrate (written by a newer tool).
+func MigrateManifest(m *BoardManifest) []string {
+	if m.SchemaVersion > IRSchemaVersion {
+		return []string{fmt.Sprintf("board.json schema version %d is newer than supported version %d; some fields may be ignored", m.SchemaVersion, IRSchemaVersion)}
+	}
+	if m.SchemaVersion < 1 {
+		migrateDefaultContext(m)
+	}
+	m.SchemaVersion = IRSchemaVersion
+	return nil
+}
+
+// migrateDefaultContext puts the whole flow in a single context and chapter
+// when the manifest predates the hierarchy.
+func migrateDefaultContext(m *BoardManifest) {
+	if len(m.Contexts) > 0 || len(m.Flow) == 0 {
+		return
+	}
+	chapter := ChapterEntry{Name: "Main"}
+	for _, entry := range m.Flow {
+		chapter.FlowIndices = append(chapter.FlowIndices, entry.Index)
+	}
+	m.Contexts = []ContextEntry{{Name: "Default", Chapters: []ChapterEntry{chapter}}}
+}
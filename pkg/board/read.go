@@ -0,0 +1,51 @@
+package board
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ReadBoardFiles reads an IR directory written by WriteBoardFiles back into a
+// manifest and per-slice data. Slice files that are missing or unparsable are
+// skipped (they may not be written yet). Manifests from older versions are
+// migrated; a manifest from a newer version gets a warning in its Errors.
+func ReadBoardFiles(dir string) (*BoardManifest, map[string]map[string]any, error) {
+	return decodeBoardFiles(func(name string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(dir, name))
+	})
+}
+
+// decodeBoardFiles decodes board.json and the slice files it references,
+// reading each file through readFile.
+func decodeBoardFiles(readFile func(name string) ([]byte, error)) (*BoardManifest, map[string]map[string]any, error) {
+	data, err := readFile("board.json")
+	if err != nil {
+		return nil, nil, fmt.Errorf("read board.json: %w", err)
+	}
+
+	var manifest BoardManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("parse board.json: %w", err)
+	}
+	manifest.Errors = append(manifest.Errors, MigrateManifest(&manifest)...)
+
+	slices := make(map[string]map[string]any)
+	for _, entry := range manifest.Flow {
+		if entry.File == "" {
+			continue
+		}
+		sliceData, err := readFile(entry.File)
+		if err != nil {
+			continue // slice file may not exist yet
+		}
+		var m map[string]any
+		if err := json.Unmarshal(sliceData, &m); err != nil {
+			continue
+		}
+		slices[entry.File] = m
+	}
+
+	return &manifest, slices, nil
+}
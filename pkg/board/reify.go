@@ -1,11 +1,19 @@
 package board
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 
 	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/token"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
 )
 
 // ReifyBoard transforms a loaded Board into a compact JSON-serializable map.
@@ -22,11 +30,25 @@ func ReifyBoard(b *Board) map[string]any {
 
 // BoardManifest is the top-level manifest written to board.json.
 type BoardManifest struct {
-	Name     string         `json:"name"`
-	Actors   []string       `json:"actors"`
-	Contexts []ContextEntry `json:"contexts"`
-	Flow     []FlowEntry    `json:"flow"`
-	Errors   []string       `json:"errors,omitempty"`
+	SchemaVersion int                      `json:"schemaVersion"` // see IRSchemaVersion
+	Name          string                   `json:"name"`
+	Actors        []string                 `json:"actors"`
+	Contexts      []ContextEntry           `json:"contexts"`
+	Flow          []FlowEntry              `json:"flow"`
+	Errors        []string                 `json:"errors,omitempty"`
+	Diagnostics   []render.ValidationError `json:"diagnostics,omitempty"` // Errors broken into their parts
+	Notes         []string                 `json:"notes,omitempty"`       // informational, see ReifyOptions.Notes
+	Graph         []GraphEntry             `json:"graph,omitempty"`       // see ReifyOptions.Graph
+}
+
+// GraphEntry is a slice of the manifest's event-flow graph: the events it
+// consumes and emits.
+type GraphEntry struct {
+	Index    int      `json:"index"` // into Flow
+	Slice    string   `json:"slice"`
+	Type     string   `json:"type"`
+	Consumes []string `json:"consumes,omitempty"` // see SliceInputs
+	Emits    []string `json:"emits,omitempty"`
 }
 
 // ContextEntry represents a bounded context containing chapters.
@@ -55,19 +77,99 @@ type FlowEntry struct {
 	Instance    map[string]any `json:"instance,omitempty"`
 	Emits       []any          `json:"emits,omitempty"`
 	Image       string         `json:"image,omitempty"`
+	Actor       string         `json:"actor,omitempty"`
+}
+
+// ReifyOptions tweaks how ReifyBoardFiles lays out its output.
+type ReifyOptions struct {
+	// IndexPrefix names slice files after their flow index (e.g. "003_AddItem.json")
+	// so that a lexical sort of the output directory matches flow order.
+	IndexPrefix bool
+	// CompactManifest leaves story instance payloads (read model instances and
+	// emitted event instances) out of the manifest, keeping only the TOC fields.
+	CompactManifest bool
+	// Notes collects informational reify-time observations (empty command
+	// fields, empty queries, ...) into the manifest. They are not errors.
+	Notes bool
+	// IncludeSource embeds the formatted CUE of each slice under "_source".
+	// Meant for debugging: it makes slice files considerably larger.
+	IncludeSource bool
+	// Graph lists, in the manifest, the events each slice consumes and
+	// emits, so that tools can draw the event flow without reading every
+	// slice file.
+	Graph bool
+}
+
+// ReifyHook post-processes the reified data of one slice before it is written.
+// It returns the data to keep (which may be the same map, modified in place).
+type ReifyHook func(sliceName string, data map[string]any) map[string]any
+
+var (
+	reifyHooksMu sync.RWMutex
+	reifyHooks   []ReifyHook
+)
+
+// RegisterReifyHook adds a hook applied by ReifyBoardFiles to every slice,
+// after the hooks registered before it. Hooks run serially, one slice at a
+// time in flow order, on the goroutine calling ReifyBoardFiles; a hook
+// registered while a board is being reified applies from its next slice.
+func RegisterReifyHook(h ReifyHook) {
+	reifyHooksMu.Lock()
+	defer reifyHooksMu.Unlock()
+	reifyHooks = append(reifyHooks, h)
+}
+
+// applyReifyHooks runs the registered hooks in registration order.
+func applyReifyHooks(sliceName string, data map[string]any) map[string]any {
+	reifyHooksMu.RLock()
+	defer reifyHooksMu.RUnlock()
+	for _, h := range reifyHooks {
+		data = h(sliceName, data)
+	}
+	return data
+}
+
+// locateDiagnostics returns diags with those without a position (most
+// Go-side checks) pointed at the CUE source of the slice they are about.
+func locateDiagnostics(b *Board, diags []render.ValidationError) []render.ValidationError {
+	diags = slices.Clone(diags)
+	positions := make(map[string]token.Pos)
+	if iter, err := lookupPath(b.Value, "flow").List(); err == nil {
+		for iter.Next() {
+			if name := getString(iter.Value(), "name"); name != "" {
+				if _, ok := positions[name]; !ok {
+					positions[name] = lookupPath(iter.Value(), "name").Pos()
+				}
+			}
+		}
+	}
+	for i, d := range diags {
+		pos, ok := positions[d.Slice]
+		if d.File != "" || !ok || !pos.IsValid() {
+			continue
+		}
+		diags[i].File, diags[i].Line, diags[i].Col = pos.Filename(), pos.Line(), pos.Column()
+	}
+	return diags
 }
 
 // ReifyBoardFiles splits a board into a manifest + per-slice data maps.
 // Stories are inline in the manifest only (no separate file).
+// Registered ReifyHooks are applied to each slice's data, in order.
+// The manifest records diags (usually b.Diagnostics, plus any opt-in lint
+// diagnostics) both formatted and broken into their parts.
 // Returns manifest, slice data, and list of image paths to copy.
-func ReifyBoardFiles(b *Board, errors []string) (BoardManifest, map[string]map[string]any, []string) {
+func ReifyBoardFiles(b *Board, diags []render.ValidationError, opts ReifyOptions) (BoardManifest, map[string]map[string]any, []string) {
 	manifest := BoardManifest{
-		Name:   b.Name,
-		Errors: errors,
+		SchemaVersion: IRSchemaVersion,
+		Name:          b.Name,
+		Errors:        render.FormatDiagnostics(diags),
+		Diagnostics:   locateDiagnostics(b, diags),
 	}
 	slices := make(map[string]map[string]any)
-	seen := make(map[string]int) // for dedup filenames
+	seen := map[string]int{"board": 1, "diagnostics": 1} // for dedup filenames; reserves the IR's own files
 	var images []string
+	indexWidth := len(strconv.Itoa(len(b.Flow)))
 
 	for i, item := range b.Flow {
 		entry := FlowEntry{
@@ -79,10 +181,30 @@ func ReifyBoardFiles(b *Board, errors []string) (BoardManifest, map[string]map[s
 
 		switch item.Kind {
 		case "slice":
-			data := reifyInstant(item)
+			data := applyReifyHooks(item.Name, reifyInstant(item))
+			if opts.IncludeSource {
+				if src, err := reifySource(item.CUEValue); err == nil {
+					data["_source"] = src
+				}
+			}
 			filename := sanitizeFilename(item.Name, seen) + ".json"
+			if opts.IndexPrefix {
+				filename = fmt.Sprintf("%0*d_%s", indexWidth, i, filename)
+			}
 			entry.File = filename
 			slices[filename] = data
+			if opts.Notes {
+				manifest.Notes = append(manifest.Notes, reifyNotes(data)...)
+			}
+			if opts.Graph {
+				manifest.Graph = append(manifest.Graph, GraphEntry{
+					Index:    i,
+					Slice:    item.Name,
+					Type:     item.Type,
+					Consumes: SliceInputs(data),
+					Emits:    SliceEmits(data),
+				})
+			}
 			// Collect image if present
 			if img, ok := data["image"].(string); ok && img != "" {
 				images = append(images, img)
@@ -93,10 +215,13 @@ func ReifyBoardFiles(b *Board, errors []string) (BoardManifest, map[string]map[s
 			if desc, ok := storyData["description"].(string); ok {
 				entry.Description = desc
 			}
-			if inst, ok := storyData["instance"].(map[string]any); ok {
+			if actor, ok := storyData["actor"].(string); ok {
+				entry.Actor = actor
+			}
+			if inst, ok := storyData["instance"].(map[string]any); ok && !opts.CompactManifest {
 				entry.Instance = inst
 			}
-			if emits, ok := storyData["emits"].([]any); ok {
+			if emits, ok := storyData["emits"].([]any); ok && !opts.CompactManifest {
 				entry.Emits = emits
 			}
 			if img, ok := storyData["image"].(string); ok && img != "" {
@@ -117,9 +242,83 @@ func ReifyBoardFiles(b *Board, errors []string) (BoardManifest, map[string]map[s
 	return manifest, slices, images
 }
 
+// reifySource formats the evaluated CUE of a flow instant.
+func reifySource(v cue.Value) (string, error) {
+	b, err := format.Node(v.Syntax(cue.Final()))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// BoardBundle is the single-document form of the IR: the manifest plus every
+// slice's data, keyed by the file name referenced from the flow entries.
+type BoardBundle struct {
+	BoardManifest
+	Slices map[string]map[string]any `json:"slices"`
+}
+
+// ReifyBoardBundle reifies a board into a single bundled document.
+func ReifyBoardBundle(b *Board, diags []render.ValidationError, opts ReifyOptions) BoardBundle {
+	manifest, slices, _ := ReifyBoardFiles(b, diags, opts)
+	return BoardBundle{BoardManifest: manifest, Slices: slices}
+}
+
+// ToJSON returns the board as an indented bundled JSON document.
+func (b *Board) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(ReifyBoardBundle(b, nil, ReifyOptions{}), "", "  ")
+}
+
+// FlowSlices returns the slice data of a manifest in flow order, skipping
+// stories and entries without a slice file.
+func FlowSlices(manifest BoardManifest, slices map[string]map[string]any) []map[string]any {
+	var out []map[string]any
+	for _, entry := range manifest.Flow {
+		if data, ok := slices[entry.File]; ok && entry.File != "" {
+			out = append(out, data)
+		}
+	}
+	return out
+}
+
+// reifyNotes reports reify-time anomalies of a reified slice that validation
+// lets through but are usually unintended.
+func reifyNotes(data map[string]any) []string {
+	var notes []string
+	name, _ := data["name"].(string)
+
+	switch data["type"] {
+	case "change", "automation":
+		cmd, _ := data["command"].(map[string]any)
+		if fields, _ := cmd["fields"].(map[string]any); len(fields) == 0 {
+			notes = append(notes, fmt.Sprintf("slice %q has no command fields", name))
+		}
+		if emits, _ := data["emits"].([]any); len(emits) == 0 {
+			notes = append(notes, fmt.Sprintf("slice %q emits no events", name))
+		}
+	case "view":
+		if query, _ := data["query"].([]any); len(query) == 0 {
+			notes = append(notes, fmt.Sprintf("view %q has empty query", name))
+		}
+		rm, _ := data["readModel"].(map[string]any)
+		if fields, _ := rm["fields"].(map[string]any); len(fields) == 0 {
+			notes = append(notes, fmt.Sprintf("view %q has no read model fields", name))
+		}
+	}
+
+	if scenarios, _ := data["scenarios"].([]any); len(scenarios) == 0 {
+		kind := "slice"
+		if data["type"] == "view" {
+			kind = "view"
+		}
+		notes = append(notes, fmt.Sprintf("%s %q has no scenarios", kind, name))
+	}
+	return notes
+}
+
 // extractContexts builds the context/chapter hierarchy from the CUE board value.
 func extractContexts(boardVal cue.Value) []ContextEntry {
-	contextsVal := boardVal.LookupPath(cue.ParsePath("contexts"))
+	contextsVal := lookupPath(boardVal, "contexts")
 	if contextsVal.Err() != nil {
 		return nil
 	}
@@ -139,7 +338,7 @@ func extractContexts(boardVal cue.Value) []ContextEntry {
 			Description: getString(ctxVal, "description"),
 		}
 
-		chaptersVal := ctxVal.LookupPath(cue.ParsePath("chapters"))
+		chaptersVal := lookupPath(ctxVal, "chapters")
 		if chapIter, err := chaptersVal.List(); err == nil {
 			for chapIter.Next() {
 				chapVal := chapIter.Value()
@@ -148,7 +347,7 @@ func extractContexts(boardVal cue.Value) []ContextEntry {
 					Description: getString(chapVal, "description"),
 				}
 
-				flowVal := chapVal.LookupPath(cue.ParsePath("flow"))
+				flowVal := lookupPath(chapVal, "flow")
 				if flowIter, err := flowVal.List(); err == nil {
 					for flowIter.Next() {
 						chap.FlowIndices = append(chap.FlowIndices, flowIdx)
@@ -168,7 +367,7 @@ func extractContexts(boardVal cue.Value) []ContextEntry {
 
 // extractActors returns actor names in definition order from board.actors
 func extractActors(boardVal cue.Value) []string {
-	actorsVal := boardVal.LookupPath(cue.ParsePath("actors"))
+	actorsVal := lookupPath(boardVal, "actors")
 	if actorsVal.Err() != nil {
 		return nil
 	}
@@ -223,10 +422,10 @@ func reifyChangeSlice(v cue.Value) map[string]any {
 		"type":      "change",
 		"name":      sliceName,
 		"actor":     getString(v, "actor.name"),
-		"trigger":   reifyTrigger(v.LookupPath(cue.ParsePath("trigger"))),
-		"command":   reifyCommand(v.LookupPath(cue.ParsePath("command"))),
-		"emits":     reifyEmits(v.LookupPath(cue.ParsePath("emits"))),
-		"scenarios": reifyGWTScenarios(v.LookupPath(cue.ParsePath("scenarios")), sliceName),
+		"trigger":   reifyTrigger(lookupPath(v, "trigger")),
+		"command":   reifyCommand(lookupPath(v, "command")),
+		"emits":     reifyEmits(lookupPath(v, "emits")),
+		"scenarios": reifyGWTScenarios(lookupPath(v, "scenarios"), sliceName),
 	}
 	if img := getString(v, "image"); img != "" {
 		out["image"] = img
@@ -243,16 +442,16 @@ func reifyViewSlice(v cue.Value) map[string]any {
 		"type":      "view",
 		"name":      getString(v, "name"),
 		"actor":     getString(v, "actor.name"),
-		"query":     reifyQueryItems(v.LookupPath(cue.ParsePath("query.items"))),
-		"readModel": reifyReadModel(v.LookupPath(cue.ParsePath("readModel"))),
-		"scenarios": reifyViewScenarios(v.LookupPath(cue.ParsePath("scenarios"))),
+		"query":     reifyQueryItems(lookupPath(v, "query.items")),
+		"readModel": reifyReadModel(lookupPath(v, "readModel")),
+		"scenarios": reifyViewScenarios(lookupPath(v, "scenarios")),
 	}
 
-	if ep := v.LookupPath(cue.ParsePath("endpoint")); ep.Exists() && ep.Err() == nil {
+	if ep := lookupPath(v, "endpoint"); ep.Exists() && ep.Err() == nil {
 		out["endpoint"] = reifyEndpoint(ep)
 	}
 
-	if depQuery := reifyDependentQuery(v.LookupPath(cue.ParsePath("dependentQuery"))); depQuery != nil {
+	if depQuery := reifyDependentQuery(lookupPath(v, "dependentQuery")); depQuery != nil {
 		out["dependentQuery"] = depQuery
 	}
 
@@ -271,12 +470,12 @@ func reifyAutomationSlice(v cue.Value) map[string]any {
 		"kind":      "slice",
 		"type":      "automation",
 		"name":      sliceName,
-		"trigger":   reifyTrigger(v.LookupPath(cue.ParsePath("trigger"))),
-		"command":   reifyCommand(v.LookupPath(cue.ParsePath("command"))),
-		"emits":     reifyEmits(v.LookupPath(cue.ParsePath("emits"))),
-		"scenarios": reifyGWTScenarios(v.LookupPath(cue.ParsePath("scenarios")), sliceName),
+		"trigger":   reifyTrigger(lookupPath(v, "trigger")),
+		"command":   reifyCommand(lookupPath(v, "command")),
+		"emits":     reifyEmits(lookupPath(v, "emits")),
+		"scenarios": reifyGWTScenarios(lookupPath(v, "scenarios"), sliceName),
 	}
-	if consumes := reifyConsumes(v.LookupPath(cue.ParsePath("consumes"))); len(consumes) > 0 {
+	if consumes := reifyConsumes(lookupPath(v, "consumes")); len(consumes) > 0 {
 		out["consumes"] = consumes
 	}
 	if img := getString(v, "image"); img != "" {
@@ -319,12 +518,15 @@ func reifyStory(v cue.Value) map[string]any {
 	if img := getString(v, "image"); img != "" {
 		out["image"] = img
 	}
-	if inst := v.LookupPath(cue.ParsePath("instance")); inst.Exists() && inst.Err() == nil {
+	if actor := getString(v, "actor.name"); actor != "" {
+		out["actor"] = actor
+	}
+	if inst := lookupPath(v, "instance"); inst.Exists() && inst.Err() == nil {
 		if cv, ok := reifyConcreteValue(inst).(map[string]any); ok && len(cv) > 0 {
 			out["instance"] = cv
 		}
 	}
-	if emits := v.LookupPath(cue.ParsePath("emits")); emits.Exists() && emits.Err() == nil {
+	if emits := lookupPath(v, "emits"); emits.Exists() && emits.Err() == nil {
 		if items := reifyEventInstances(emits); len(items) > 0 {
 			out["emits"] = items
 		}
@@ -337,11 +539,13 @@ func reifyTrigger(v cue.Value) map[string]any {
 	out := map[string]any{"kind": kind}
 
 	if kind == "endpoint" {
-		out["endpoint"] = reifyEndpoint(v.LookupPath(cue.ParsePath("endpoint")))
+		out["endpoint"] = reifyEndpoint(lookupPath(v, "endpoint"))
 	} else if kind == "externalEvent" {
-		out["externalEvent"] = reifyExternalEvent(v.LookupPath(cue.ParsePath("externalEvent")))
+		out["externalEvent"] = reifyExternalEvent(lookupPath(v, "externalEvent"))
 	} else if kind == "internalEvent" {
-		out["internalEvent"] = reifyInternalEvent(v.LookupPath(cue.ParsePath("internalEvent")))
+		out["internalEvent"] = reifyInternalEvent(lookupPath(v, "internalEvent"))
+	} else if kind == "ui" {
+		out["ui"] = reifyUIAction(lookupPath(v, "ui"))
 	}
 	return out
 }
@@ -350,14 +554,21 @@ func reifyExternalEvent(v cue.Value) map[string]any {
 	return map[string]any{
 		"name":   getString(v, "name"),
 		"source": getString(v, "source"),
-		"fields": reifyFields(v.LookupPath(cue.ParsePath("fields"))),
+		"fields": reifyFields(lookupPath(v, "fields")),
+	}
+}
+
+func reifyUIAction(v cue.Value) map[string]any {
+	return map[string]any{
+		"name":   getString(v, "name"),
+		"fields": reifyFields(lookupPath(v, "fields")),
 	}
 }
 
 func reifyInternalEvent(v cue.Value) map[string]any {
 	return map[string]any{
 		"eventType": getString(v, "eventType"),
-		"fields":    reifyFields(v.LookupPath(cue.ParsePath("fields"))),
+		"fields":    reifyFields(lookupPath(v, "fields")),
 	}
 }
 
@@ -366,13 +577,13 @@ func reifyEndpoint(v cue.Value) map[string]any {
 		"verb": getString(v, "verb"),
 		"path": getString(v, "path"),
 	}
-	if params := reifyFields(v.LookupPath(cue.ParsePath("params"))); len(params) > 0 {
+	if params := reifyFields(lookupPath(v, "params")); len(params) > 0 {
 		out["params"] = params
 	}
-	if body := reifyFields(v.LookupPath(cue.ParsePath("body"))); len(body) > 0 {
+	if body := reifyFields(lookupPath(v, "body")); len(body) > 0 {
 		out["body"] = body
 	}
-	if auth := reifyFields(v.LookupPath(cue.ParsePath("auth"))); len(auth) > 0 {
+	if auth := reifyFields(lookupPath(v, "auth")); len(auth) > 0 {
 		out["auth"] = auth
 	}
 	return out
@@ -380,26 +591,28 @@ func reifyEndpoint(v cue.Value) map[string]any {
 
 func reifyCommand(v cue.Value) map[string]any {
 	out := map[string]any{}
-	if fields := reifyFields(v.LookupPath(cue.ParsePath("fields"))); len(fields) > 0 {
+	if fields := reifyFields(lookupPath(v, "fields")); len(fields) > 0 {
 		out["fields"] = fields
 	}
-	if mapping := reifyCommandMapping(v.LookupPath(cue.ParsePath("mapping"))); len(mapping) > 0 {
+	if mapping := reifyCommandMapping(lookupPath(v, "mapping")); len(mapping) > 0 {
 		out["mapping"] = mapping
 	}
-	if query := reifyQueryItems(v.LookupPath(cue.ParsePath("query.items"))); len(query) > 0 {
+	if query := reifyQueryItems(lookupPath(v, "query.items")); len(query) > 0 {
 		out["query"] = query
 	}
-	if depQuery := reifyDependentQuery(v.LookupPath(cue.ParsePath("dependentQuery"))); depQuery != nil {
+	if depQuery := reifyDependentQuery(lookupPath(v, "dependentQuery")); depQuery != nil {
 		out["dependentQuery"] = depQuery
 	}
-	if comp := reifyCommandComputed(v.LookupPath(cue.ParsePath("computed"))); len(comp) > 0 {
+	if comp := reifyCommandComputed(lookupPath(v, "computed"), lookupPath(v, "fields")); len(comp) > 0 {
 		out["computed"] = comp
 	}
 	return out
 }
 
-// reifyCommandComputed extracts command computed fields: {fieldName: {type, description}}
-func reifyCommandComputed(v cue.Value) map[string]any {
+// reifyCommandComputed extracts command computed fields: {fieldName: {type, description, expr}}.
+// A computed entry is either a description string or a #CommandComputed struct;
+// the type comes from the matching command field.
+func reifyCommandComputed(v, fields cue.Value) map[string]any {
 	if !v.Exists() || v.Err() != nil {
 		return nil
 	}
@@ -415,17 +628,22 @@ func reifyCommandComputed(v cue.Value) map[string]any {
 		}
 		fv := iter.Value()
 		item := map[string]any{}
-		// Extract type
-		if typeVal := fv.LookupPath(cue.ParsePath("type")); typeVal.Exists() {
+		if typeVal := fields.LookupPath(cue.MakePath(cue.Str(label))); typeVal.Exists() {
 			item["type"] = reifyFieldType(typeVal)
 		}
-		// Extract description (concrete string)
-		if desc := getString(fv, "description"); desc != "" {
-			item["description"] = desc
-		}
-		if len(item) > 0 {
-			out[label] = item
+		if desc, err := fv.String(); err == nil {
+			if desc != "" {
+				item["description"] = desc
+			}
+		} else {
+			if desc := getString(fv, "description"); desc != "" {
+				item["description"] = desc
+			}
+			if expr := getString(fv, "expr"); expr != "" {
+				item["expr"] = expr
+			}
 		}
+		out[label] = item
 	}
 	if len(out) == 0 {
 		return nil
@@ -482,8 +700,9 @@ func formatCUEPath(v cue.Value) string {
 
 // cleanMappingPath extracts the relative path (trigger.*, command.*, etc.)
 func cleanMappingPath(fullPath string) string {
-	// Look for common prefixes and extract relative part
-	prefixes := []string{"trigger.", "command.", "fields."}
+	// Look for common prefixes and extract relative part; endpoint.* is a
+	// view's own endpoint (a change slice's is under trigger.)
+	prefixes := []string{"trigger.", "command.", "fields.", "endpoint."}
 	for _, prefix := range prefixes {
 		if idx := strings.Index(fullPath, prefix); idx >= 0 {
 			return fullPath[idx:]
@@ -507,7 +726,7 @@ func reifyQueryItems(v cue.Value) []any {
 func reifyQueryItem(v cue.Value) map[string]any {
 	// types: extract event type names
 	var types []string
-	typesVal := v.LookupPath(cue.ParsePath("types"))
+	typesVal := lookupPath(v, "types")
 	if iter, err := typesVal.List(); err == nil {
 		for iter.Next() {
 			if et := getString(iter.Value(), "eventType"); et != "" {
@@ -518,22 +737,24 @@ func reifyQueryItem(v cue.Value) map[string]any {
 
 	// tags
 	var tags []any
-	tagsVal := v.LookupPath(cue.ParsePath("tags"))
+	tagsVal := lookupPath(v, "tags")
 	if iter, err := tagsVal.List(); err == nil {
 		for iter.Next() {
 			tv := iter.Value()
 			tag := map[string]any{}
 			// Could be a bare #Tag or a #TagRef {tag: #Tag, value: ...}
-			tagField := tv.LookupPath(cue.ParsePath("tag"))
+			tagField := lookupPath(tv, "tag")
 			if tagField.Exists() && tagField.Err() == nil {
 				// TagRef form
 				tag["tag"] = getString(tagField, "name")
 				if param := getString(tagField, "param"); param != "" {
 					tag["param"] = param
 				}
-				// Check for fromExtract
+				// The bound value (a reference path, or a literal) or the extract name
 				if fromExtract := getString(tv, "fromExtract"); fromExtract != "" {
 					tag["fromExtract"] = fromExtract
+				} else if value := reifyTagValue(lookupPath(tv, "value")); value != "" {
+					tag["value"] = value
 				}
 			} else {
 				// Bare tag
@@ -546,6 +767,10 @@ func reifyQueryItem(v cue.Value) map[string]any {
 		}
 	}
 
+	// A tag-only item (types: []) is a valid DCB query across all event types
+	if types == nil {
+		types = []string{}
+	}
 	if tags == nil {
 		tags = []any{}
 	}
@@ -555,6 +780,21 @@ func reifyQueryItem(v cue.Value) map[string]any {
 	}
 }
 
+// reifyTagValue returns the value bound to a query tag: the path it
+// references (e.g. "fields.cartId"), or the literal it is set to.
+func reifyTagValue(v cue.Value) string {
+	if !v.Exists() || v.Err() != nil {
+		return ""
+	}
+	if path := formatCUEPath(v); path != "" {
+		return path
+	}
+	if v.IsConcrete() {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
 // reifyDependentQuery extracts dependentQuery: {extract: {...}, items: [...]}
 func reifyDependentQuery(v cue.Value) map[string]any {
 	if !v.Exists() || v.Err() != nil {
@@ -564,7 +804,7 @@ func reifyDependentQuery(v cue.Value) map[string]any {
 	out := map[string]any{}
 
 	// Extract
-	extractVal := v.LookupPath(cue.ParsePath("extract"))
+	extractVal := lookupPath(v, "extract")
 	if extractVal.Exists() && extractVal.Err() == nil {
 		extract := map[string]any{}
 		if iter, err := extractVal.Fields(); err == nil {
@@ -579,7 +819,7 @@ func reifyDependentQuery(v cue.Value) map[string]any {
 					"field": getString(ev, "field"),
 				}
 				// Include many if true (default is false)
-				if manyVal := ev.LookupPath(cue.ParsePath("many")); manyVal.Exists() {
+				if manyVal := lookupPath(ev, "many"); manyVal.Exists() {
 					if b, err := manyVal.Bool(); err == nil && b {
 						item["many"] = true
 					}
@@ -593,7 +833,7 @@ func reifyDependentQuery(v cue.Value) map[string]any {
 	}
 
 	// Items
-	if items := reifyQueryItems(v.LookupPath(cue.ParsePath("items"))); len(items) > 0 {
+	if items := reifyQueryItems(lookupPath(v, "items")); len(items) > 0 {
 		out["items"] = items
 	}
 
@@ -613,12 +853,12 @@ func reifyEmits(v cue.Value) []any {
 		ev := iter.Value()
 		item := map[string]any{
 			"type":   getString(ev, "eventType"),
-			"fields": reifyFields(ev.LookupPath(cue.ParsePath("fields"))),
+			"fields": reifyFields(lookupPath(ev, "fields")),
 		}
 
 		// tags as string names
 		var tagNames []string
-		tagsVal := ev.LookupPath(cue.ParsePath("tags"))
+		tagsVal := lookupPath(ev, "tags")
 		if ti, err := tagsVal.List(); err == nil {
 			for ti.Next() {
 				if n := getString(ti.Value(), "name"); n != "" {
@@ -629,7 +869,7 @@ func reifyEmits(v cue.Value) []any {
 		item["tags"] = tagNames
 
 		// mapping (optional, skip if empty)
-		if mapping := reifyMappingEmit(ev.LookupPath(cue.ParsePath("mapping"))); len(mapping) > 0 {
+		if mapping := reifyMappingEmit(lookupPath(ev, "mapping")); len(mapping) > 0 {
 			item["mapping"] = mapping
 		}
 
@@ -678,9 +918,9 @@ func reifyGWTScenarios(v cue.Value, sliceName string) []any {
 		sv := iter.Value()
 		s := map[string]any{
 			"name":  getString(sv, "name"),
-			"given": reifyEventInstances(sv.LookupPath(cue.ParsePath("given"))),
-			"when":  reifyWhen(sv.LookupPath(cue.ParsePath("when")), sliceName),
-			"then":  reifyOutcome(sv.LookupPath(cue.ParsePath("then"))),
+			"given": reifyEventInstances(lookupPath(sv, "given")),
+			"when":  reifyWhen(lookupPath(sv, "when"), sliceName),
+			"then":  reifyOutcome(lookupPath(sv, "then")),
 		}
 		scenarios = append(scenarios, s)
 	}
@@ -697,9 +937,9 @@ func reifyViewScenarios(v cue.Value) []any {
 		sv := iter.Value()
 		s := map[string]any{
 			"name":   getString(sv, "name"),
-			"given":  reifyEventInstances(sv.LookupPath(cue.ParsePath("given"))),
-			"query":  reifyConcreteValue(sv.LookupPath(cue.ParsePath("query"))),
-			"expect": reifyConcreteValue(sv.LookupPath(cue.ParsePath("expect"))),
+			"given":  reifyEventInstances(lookupPath(sv, "given")),
+			"query":  reifyConcreteValue(lookupPath(sv, "query")),
+			"expect": reifyConcreteValue(lookupPath(sv, "expect")),
 		}
 		scenarios = append(scenarios, s)
 	}
@@ -728,7 +968,7 @@ func reifyEventInstance(v cue.Value) any {
 	}
 
 	// Extract concrete field values (if any)
-	fieldsVal := v.LookupPath(cue.ParsePath("fields"))
+	fieldsVal := lookupPath(v, "fields")
 	var concreteFields map[string]any
 	if fieldsVal.Exists() && fieldsVal.Err() == nil {
 		concreteFields = extractConcreteFields(fieldsVal)
@@ -736,7 +976,7 @@ func reifyEventInstance(v cue.Value) any {
 
 	// Check for fromFuture flag
 	fromFuture := false
-	ff := v.LookupPath(cue.ParsePath("fromFuture"))
+	ff := lookupPath(v, "fromFuture")
 	if ff.Exists() {
 		if b, err := ff.Bool(); err == nil && b {
 			fromFuture = true
@@ -801,11 +1041,11 @@ func reifyWhen(v cue.Value, sliceName string) map[string]any {
 }
 
 func reifyOutcome(v cue.Value) map[string]any {
-	successVal := v.LookupPath(cue.ParsePath("success"))
+	successVal := lookupPath(v, "success")
 	success, _ := successVal.Bool()
 	out := map[string]any{"success": success}
 	if success {
-		out["events"] = reifyEventInstances(v.LookupPath(cue.ParsePath("events")))
+		out["events"] = reifyEventInstances(lookupPath(v, "events"))
 	} else {
 		if errStr := getString(v, "error"); errStr != "" {
 			out["error"] = errStr
@@ -818,19 +1058,41 @@ func reifyReadModel(v cue.Value) map[string]any {
 	out := map[string]any{
 		"name":        getString(v, "name"),
 		"cardinality": getString(v, "cardinality"),
-		"fields":      reifyFieldsDeep(v.LookupPath(cue.ParsePath("fields"))),
+		"fields":      reifyFieldsDeep(lookupPath(v, "fields")),
+	}
+	if columns := reifyFieldsDeep(lookupPath(v, "columns")); len(columns) > 0 {
+		out["columns"] = columns
 	}
 
 	// mapping: field -> "Event.field"
-	if mapping := reifyReadModelMapping(v.LookupPath(cue.ParsePath("mapping"))); len(mapping) > 0 {
+	if mapping := reifyReadModelMapping(lookupPath(v, "mapping")); len(mapping) > 0 {
 		out["mapping"] = mapping
 	}
 
 	// computed
-	if computed := reifyReadModelComputed(v.LookupPath(cue.ParsePath("computed"))); len(computed) > 0 {
+	if computed := reifyReadModelComputed(lookupPath(v, "computed")); len(computed) > 0 {
 		out["computed"] = computed
 	}
 
+	// references: field -> read model name
+	if refs := reifyReadModelReferences(lookupPath(v, "references")); len(refs) > 0 {
+		out["references"] = refs
+	}
+
+	return out
+}
+
+func reifyReadModelReferences(v cue.Value) map[string]any {
+	iter, err := v.Fields()
+	if err != nil {
+		return nil
+	}
+	out := map[string]any{}
+	for iter.Next() {
+		if name, err := iter.Value().String(); err == nil {
+			out[selectorLabel(iter.Selector())] = name
+		}
+	}
 	return out
 }
 
@@ -873,7 +1135,7 @@ func reifyReadModelComputed(v cue.Value) map[string]any {
 		cv := iter.Value()
 		eventType := getString(cv, "event.eventType")
 		var fields []string
-		fieldsVal := cv.LookupPath(cue.ParsePath("fields"))
+		fieldsVal := lookupPath(cv, "fields")
 		if fi, err := fieldsVal.List(); err == nil {
 			for fi.Next() {
 				if s, err := fi.Value().String(); err == nil {
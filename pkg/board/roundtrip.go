@@ -0,0 +1,87 @@
+package board
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
+)
+
+// roundTripWidth is the box width used when comparing renders.
+const roundTripWidth = 120
+
+// RoundTripCheck reifies b, passes the result through the same JSON encoding
+// and decoding the TUI uses (WriteBoardFiles → ReadBoardFiles), and checks
+// that every slice renders identically before and after.
+func RoundTripCheck(b *Board) error {
+	manifest, sliceData, _ := ReifyBoardFiles(b, nil, ReifyOptions{})
+
+	files := make(map[string][]byte)
+	encoded, err := marshalIR(manifest)
+	if err != nil {
+		return fmt.Errorf("marshal board.json: %w", err)
+	}
+	files["board.json"] = encoded
+	for filename, data := range sliceData {
+		encoded, err := marshalIR(data)
+		if err != nil {
+			return fmt.Errorf("marshal %s: %w", filename, err)
+		}
+		files[filename] = encoded
+	}
+
+	_, decoded, err := decodeBoardFiles(func(name string) ([]byte, error) {
+		data, ok := files[name]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var diffs []string
+	for _, entry := range manifest.Flow {
+		if entry.File == "" {
+			continue
+		}
+		want, err := render.RenderSliceIR(sliceData[entry.File], roundTripWidth)
+		if err != nil {
+			return fmt.Errorf("render %s: %w", entry.File, err)
+		}
+		data, ok := decoded[entry.File]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: lost in round trip", entry.File))
+			continue
+		}
+		got, err := render.RenderSliceIR(data, roundTripWidth)
+		if err != nil {
+			return fmt.Errorf("render decoded %s: %w", entry.File, err)
+		}
+		if line, ok := firstDiff(want, got); !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: %s", entry.File, line))
+		}
+	}
+
+	if len(diffs) > 0 {
+		return fmt.Errorf("round trip changed rendering:\n%s", strings.Join(diffs, "\n"))
+	}
+	return nil
+}
+
+// firstDiff compares two renders line by line and describes the first mismatch.
+func firstDiff(want, got string) (string, bool) {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := range min(len(wantLines), len(gotLines)) {
+		if wantLines[i] != gotLines[i] {
+			return fmt.Sprintf("want %q, got %q", strings.TrimSpace(wantLines[i]), strings.TrimSpace(gotLines[i])), false
+		}
+	}
+	if len(wantLines) != len(gotLines) {
+		return fmt.Sprintf("want %d lines, got %d", len(wantLines), len(gotLines)), false
+	}
+	return "", true
+}
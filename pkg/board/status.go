@@ -0,0 +1,52 @@
+package board
+
+import (
+	"slices"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
+)
+
+// StatusUnspecified counts the slices whose devstatus is missing or unknown.
+const StatusUnspecified = "unspecified"
+
+// ContextStatus counts a context's slices by devstatus.
+type ContextStatus struct {
+	Context string
+	Counts  map[string]int // render.DevStatuses and StatusUnspecified
+	Total   int
+}
+
+// StatusSummary counts the slices of each context by devstatus, in context
+// order. A slice placed several times in a context's flow counts once.
+func StatusSummary(manifest BoardManifest, slices map[string]map[string]any) []ContextStatus {
+	out := make([]ContextStatus, 0, len(manifest.Contexts))
+	for _, c := range manifest.Contexts {
+		cs := ContextStatus{Context: c.Name, Counts: make(map[string]int)}
+		seen := make(map[string]bool)
+		for _, ch := range c.Chapters {
+			for _, idx := range ch.FlowIndices {
+				if idx < 0 || idx >= len(manifest.Flow) {
+					continue
+				}
+				entry := manifest.Flow[idx]
+				if entry.Kind != "slice" || seen[entry.Name] {
+					continue
+				}
+				seen[entry.Name] = true
+				cs.Counts[sliceStatus(slices[entry.File])]++
+				cs.Total++
+			}
+		}
+		out = append(out, cs)
+	}
+	return out
+}
+
+// sliceStatus returns a slice's devstatus, or StatusUnspecified.
+func sliceStatus(data map[string]any) string {
+	status, _ := data["devstatus"].(string)
+	if !slices.Contains(render.DevStatuses, status) {
+		return StatusUnspecified
+	}
+	return status
+}
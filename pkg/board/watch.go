@@ -0,0 +1,102 @@
+package board
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchOptions tweaks Watch.
+type WatchOptions struct {
+	// Settle is how long Watch waits for the file events to stop before
+	// calling changed once for the whole burst: each event restarts the
+	// wait, so an editor saving in several steps regenerates once. Zero
+	// means 100ms.
+	Settle time.Duration
+	// Event, when set, is called with every file event (debug logging).
+	Event func(fsnotify.Event)
+	// Error, when set, is called with the errors the watcher reports.
+	Error func(error)
+}
+
+// Watch calls changed each time files are written, created, renamed or
+// removed in dirs (editors saving atomically rename a temporary file over
+// the original), until ctx is done. A watched directory that was itself
+// removed or renamed is added back at the next change if it exists again.
+// changed runs on Watch's goroutine, so a regeneration in progress
+// completes before Watch returns. It returns nil once ctx is done, or the
+// error setting up the watch.
+func Watch(ctx context.Context, dirs []string, changed func(), opts WatchOptions) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("watch %s: %w", dir, err)
+		}
+	}
+
+	settle := opts.Settle
+	if settle == 0 {
+		settle = 100 * time.Millisecond
+	}
+	event := func(ev fsnotify.Event) {
+		if opts.Event != nil {
+			opts.Event(ev)
+		}
+	}
+	fail := func(err error) {
+		if opts.Error != nil {
+			opts.Error(err)
+		}
+	}
+
+	// The debounce timer runs only while a burst is pending
+	timer := time.NewTimer(settle)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			event(ev)
+			if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+				continue
+			}
+			timer.Reset(settle)
+		case <-timer.C:
+			rewatch(watcher, dirs, fail)
+			changed()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fail(err)
+		}
+	}
+}
+
+// rewatch adds back the dirs the watcher dropped because they were removed
+// or renamed. A dir that isn't back yet is retried on the next change.
+func rewatch(watcher *fsnotify.Watcher, dirs []string, fail func(error)) {
+	watched := watcher.WatchList()
+	for _, dir := range dirs {
+		if slices.Contains(watched, dir) {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			fail(fmt.Errorf("watch %s: %w", dir, err))
+		}
+	}
+}
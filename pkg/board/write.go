@@ -6,56 +6,99 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
+)
+
+// Image reads are retried to ride out editors replacing the file during a save.
+const (
+	imageCopyAttempts = 3
+	imageCopyBackoff  = 50 * time.Millisecond
 )
 
 // WriteBoardFiles writes the manifest and per-slice JSON files atomically.
 // Stale .json files not in the current set are removed.
-// If srcDir and images are provided, copies image files preserving relative paths.
-func WriteBoardFiles(outdir string, manifest BoardManifest, slices map[string]map[string]any, srcDir string, images []string) error {
+// The files are canonical (see marshalIR): reifying an unchanged board
+// rewrites them byte for byte, so the IR directory diffs cleanly in git.
+// If srcDir and images are provided, copies image files preserving relative paths;
+// an empty srcDir (a board loaded from source) skips them.
+// Images that still can't be copied after retrying are returned; they don't fail the write.
+func WriteBoardFiles(outdir string, manifest BoardManifest, slices map[string]map[string]any, srcDir string, images []string) ([]string, error) {
 	if err := os.MkdirAll(outdir, 0o755); err != nil {
-		return err
+		return nil, err
 	}
+	keep := make(map[string]bool, len(slices))
+	for filename := range slices {
+		keep[filename] = true
+	}
+	return writeBoardFiles(outdir, manifest, slices, keep, srcDir, images)
+}
 
-	keep := map[string]bool{"board.json": true}
+// writeBoardFiles writes the given slice files, the manifest and the images,
+// then removes the .json files that are neither written nor in keepSlices.
+func writeBoardFiles(outdir string, manifest BoardManifest, slices map[string]map[string]any, keepSlices map[string]bool, srcDir string, images []string) ([]string, error) {
+	keep := map[string]bool{"board.json": true, "diagnostics.json": true}
+	for filename := range keepSlices {
+		keep[filename] = true
+	}
 
 	// Write slice files
 	for filename, data := range slices {
 		keep[filename] = true
-		b, err := json.MarshalIndent(data, "", "  ")
+		b, err := marshalIR(data)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if err := writeIfChanged(filepath.Join(outdir, filename), b); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	// Write manifest
-	b, err := json.MarshalIndent(manifest, "", "  ")
+	b, err := marshalIR(manifest)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if err := writeIfChanged(filepath.Join(outdir, "board.json"), b); err != nil {
-		return err
+		return nil, err
+	}
+	if err := writeDiagnostics(outdir, manifest.Diagnostics); err != nil {
+		return nil, err
 	}
 
 	// Copy images
+	var failed []string
+	if srcDir == "" {
+		images = nil
+	}
 	for _, img := range images {
 		srcPath := filepath.Join(srcDir, img)
 		dstPath := filepath.Join(outdir, img)
 		if err := copyFile(srcPath, dstPath); err != nil {
-			// Log but don't fail - image might not exist yet
+			// Don't fail - image might not exist yet
+			failed = append(failed, img)
 			continue
 		}
 		keep[img] = true
 	}
 
-	return cleanStale(outdir, keep)
+	return failed, cleanStale(outdir, keep)
 }
 
 // copyFile copies a file, creating parent directories as needed.
+// The read is retried with a short backoff.
 func copyFile(src, dst string) error {
-	data, err := os.ReadFile(src)
+	var data []byte
+	var err error
+	backoff := imageCopyBackoff
+	for attempt := 1; ; attempt++ {
+		if data, err = os.ReadFile(src); err == nil || attempt == imageCopyAttempts {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
 	if err != nil {
 		return err
 	}
@@ -66,20 +109,52 @@ func copyFile(src, dst string) error {
 }
 
 // WriteBoardError writes a board.json with errors only, removing all slice files.
-func WriteBoardError(outdir string, boardName string, errs []string) error {
+func WriteBoardError(outdir string, boardName string, diags []render.ValidationError) error {
 	if err := os.MkdirAll(outdir, 0o755); err != nil {
 		return err
 	}
 
-	manifest := BoardManifest{Name: boardName, Errors: errs}
-	b, err := json.MarshalIndent(manifest, "", "  ")
+	manifest := BoardManifest{SchemaVersion: IRSchemaVersion, Name: boardName, Errors: render.FormatDiagnostics(diags), Diagnostics: diags}
+	b, err := marshalIR(manifest)
 	if err != nil {
 		return err
 	}
 	if err := writeIfChanged(filepath.Join(outdir, "board.json"), b); err != nil {
 		return err
 	}
-	return cleanStale(outdir, map[string]bool{"board.json": true})
+	if err := writeDiagnostics(outdir, manifest.Diagnostics); err != nil {
+		return err
+	}
+	return cleanStale(outdir, map[string]bool{"board.json": true, "diagnostics.json": true})
+}
+
+// writeDiagnostics writes the manifest's diagnostics as a JSON array to
+// diagnostics.json, for frontends annotating the board.
+func writeDiagnostics(outdir string, diags []render.ValidationError) error {
+	if diags == nil {
+		diags = []render.ValidationError{}
+	}
+	b, err := marshalIR(diags)
+	if err != nil {
+		return err
+	}
+	return writeIfChanged(filepath.Join(outdir, "diagnostics.json"), b)
+}
+
+// marshalIR encodes an IR file canonically: two-space indentation, object
+// keys sorted (encoding/json sorts map keys; structs keep their field
+// order), lists in board order (flow order, then declaration order for
+// emits, query items, scenarios and tags), no HTML escaping of <, > and &
+// in expressions, and a final newline.
+func marshalIR(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 // writeIfChanged writes data only if the file content differs. Uses atomic tmp+rename.
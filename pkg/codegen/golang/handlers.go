@@ -0,0 +1,199 @@
+// Package golang generates Go scaffolding from a reified board.
+package golang
+
+import (
+	"fmt"
+	"go/format"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Handlers generates a Go package with one stub handler per slice:
+// change and automation slices take their command fields and return the
+// events they may emit, view slices take their endpoint params and return
+// the read model. Bodies are TODOs; the output is gofmt'ed.
+//
+// flow is the reified slice data in flow order (see board.FlowSlices).
+func Handlers(pkg string, flow []map[string]any) ([]byte, error) {
+	g := &generator{declared: make(map[string]bool)}
+
+	g.printf("// Code generated by emspec codegen -lang go -kind handlers. Scaffolding: edit freely.\n\n")
+	g.printf("package %s\n\n", pkg)
+	g.printf("import \"context\"\n\n")
+	g.printf("// Event is implemented by every domain event.\n")
+	g.printf("type Event interface {\n\tEventType() string\n}\n\n")
+
+	for _, data := range flow {
+		switch str(data, "type") {
+		case "change", "automation":
+			g.command(data)
+		case "view":
+			g.view(data)
+		}
+	}
+
+	src, err := format.Source([]byte(g.sb.String()))
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return src, nil
+}
+
+type generator struct {
+	sb       strings.Builder
+	declared map[string]bool // type names already emitted
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.sb, format, args...)
+}
+
+// command emits the input struct, the emitted event types and the handler.
+func (g *generator) command(data map[string]any) {
+	name := Identifier(str(data, "name"))
+	cmd := mapOf(data, "command")
+
+	input := name + "Input"
+	g.printf("// %s holds the %s command fields.\n", input, str(data, "name"))
+	g.structType(input, mapOf(cmd, "fields"))
+
+	var emitted []string
+	for _, e := range listOf(data, "emits") {
+		evt, _ := e.(map[string]any)
+		eventType := str(evt, "type")
+		emitted = append(emitted, eventType)
+		ident := Identifier(eventType)
+		if g.declared[ident] {
+			continue
+		}
+		g.printf("// %s is the %s event.\n", ident, eventType)
+		g.structType(ident, mapOf(evt, "fields"))
+		g.printf("func (%s) EventType() string { return %q }\n\n", ident, eventType)
+	}
+
+	g.printf("// %s handles the %s slice", name, str(data, "name"))
+	if len(emitted) > 0 {
+		g.printf(", emitting %s", strings.Join(emitted, ", "))
+	}
+	g.printf(".\n")
+	g.printf("func %s(ctx context.Context, in %s) ([]Event, error) {\n", name, input)
+	g.printf("\t// TODO: load the command query, decide, return the events\n")
+	g.printf("\treturn nil, nil\n}\n\n")
+}
+
+// view emits the query struct, the read model type and the handler.
+func (g *generator) view(data map[string]any) {
+	name := Identifier(str(data, "name"))
+	ep := mapOf(data, "endpoint")
+	rm := mapOf(data, "readModel")
+
+	query := name + "Query"
+	g.printf("// %s holds the %s endpoint params.\n", query, str(data, "name"))
+	g.structType(query, mapOf(ep, "params"))
+
+	model := Identifier(str(rm, "name"))
+	if model == "" || model == name {
+		model = name + "ReadModel"
+	}
+	fields := mapOf(rm, "fields")
+	if fields == nil {
+		fields = mapOf(rm, "columns")
+	}
+	if !g.declared[model] {
+		g.printf("// %s is the %s read model.\n", model, str(rm, "name"))
+		g.structType(model, fields)
+	}
+
+	result := model
+	if str(rm, "cardinality") == "table" {
+		result = "[]" + model
+	}
+	g.printf("// %s handles the %s view.\n", name, str(data, "name"))
+	g.printf("func %s(ctx context.Context, q %s) (%s, error) {\n", name, query, result)
+	g.printf("\t// TODO: load the query events and project them\n")
+	g.printf("\tvar out %s\n\treturn out, nil\n}\n\n", result)
+}
+
+// structType declares a named struct type once.
+func (g *generator) structType(name string, fields map[string]any) {
+	g.declared[name] = true
+	g.printf("type %s %s\n\n", name, structOf(fields))
+}
+
+// structOf renders the fields as a Go struct type, sorted by name.
+func structOf(fields map[string]any) string {
+	if len(fields) == 0 {
+		return "struct{}"
+	}
+	var sb strings.Builder
+	sb.WriteString("struct {\n")
+	for _, k := range slices.Sorted(maps.Keys(fields)) {
+		fmt.Fprintf(&sb, "%s %s `json:%q`\n", Identifier(k), GoType(fields[k]), k)
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// GoType maps a reified CUE field type to a Go type.
+func GoType(t any) string {
+	switch v := t.(type) {
+	case string:
+		switch v {
+		case "string", "bool", "int":
+			return v
+		case "float":
+			return "float64"
+		case "bytes":
+			return "[]byte"
+		}
+		return "any"
+	case map[string]any:
+		return structOf(v)
+	case []any:
+		if len(v) == 0 {
+			return "[]any"
+		}
+		return "[]" + GoType(v[0])
+	}
+	return "any"
+}
+
+// Identifier turns a board name into an exported Go identifier
+// ("cart_id" → "CartId", "Add item" → "AddItem").
+func Identifier(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	id := sb.String()
+	if id != "" && unicode.IsDigit([]rune(id)[0]) {
+		id = "X" + id
+	}
+	return id
+}
+
+func str(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func mapOf(m map[string]any, key string) map[string]any {
+	r, _ := m[key].(map[string]any)
+	return r
+}
+
+func listOf(m map[string]any, key string) []any {
+	r, _ := m[key].([]any)
+	return r
+}
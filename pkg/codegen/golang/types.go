@@ -0,0 +1,116 @@
+package golang
+
+import (
+	"fmt"
+	"go/format"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Types generates a Go package with a struct per event type and per view
+// read model. Nested structs become named types (parent + field name, e.g.
+// OrderPlacedLines), lists slices, and kind unions like int | string `any`
+// with the alternatives in a comment. json tags carry the board field names.
+//
+// events maps event types to their reified fields (see board.EventFields);
+// flow is the reified slice data in flow order (see board.FlowSlices).
+func Types(pkg string, events map[string]map[string]any, flow []map[string]any) ([]byte, error) {
+	g := &generator{declared: make(map[string]bool)}
+
+	g.printf("// Code generated by emspec codegen -lang go. DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", pkg)
+
+	for _, eventType := range slices.Sorted(maps.Keys(events)) {
+		g.namedStruct(Identifier(eventType), fmt.Sprintf("the %s event", eventType), events[eventType])
+	}
+
+	for _, data := range flow {
+		if str(data, "type") != "view" {
+			continue
+		}
+		rm := mapOf(data, "readModel")
+		model := Identifier(str(rm, "name"))
+		if model == "" {
+			model = Identifier(str(data, "name")) + "ReadModel"
+		}
+		if g.declared[model] {
+			continue
+		}
+		fields := mapOf(rm, "fields")
+		if fields == nil {
+			fields = mapOf(rm, "columns")
+		}
+		g.namedStruct(model, fmt.Sprintf("the %s read model", str(rm, "name")), fields)
+	}
+
+	src, err := format.Source([]byte(g.sb.String()))
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// namedStruct declares a struct type for fields, then the named types of its
+// nested structs.
+func (g *generator) namedStruct(name, what string, fields map[string]any) {
+	g.declared[name] = true
+	type nestedType struct {
+		name, what string
+		fields     map[string]any
+	}
+	var nested []nestedType
+
+	var body strings.Builder
+	for _, k := range slices.Sorted(maps.Keys(fields)) {
+		t, comment := namedGoType(name+Identifier(k), fields[k], func(sub string, f map[string]any) {
+			nested = append(nested, nestedType{sub, fmt.Sprintf("the type of %s.%s", name, k), f})
+		})
+		fmt.Fprintf(&body, "%s %s `json:%q`", Identifier(k), t, k)
+		if comment != "" {
+			fmt.Fprintf(&body, " // %s", comment)
+		}
+		body.WriteString("\n")
+	}
+
+	g.printf("// %s is %s.\n", name, what)
+	if body.Len() == 0 {
+		g.printf("type %s struct{}\n\n", name)
+	} else {
+		g.printf("type %s struct {\n%s}\n\n", name, body.String())
+	}
+	for _, n := range nested {
+		if !g.declared[n.name] {
+			g.namedStruct(n.name, n.what, n.fields)
+		}
+	}
+}
+
+// namedGoType maps a reified field type to a Go type like GoType, naming
+// nested structs (name) through declare instead of inlining them. The
+// comment lists the alternatives of a kind union.
+func namedGoType(name string, t any, declare func(name string, fields map[string]any)) (goType, comment string) {
+	switch v := t.(type) {
+	case string:
+		if kinds := strings.Split(strings.Trim(v, "()"), "|"); len(kinds) > 1 {
+			for i := range kinds {
+				kinds[i] = strings.TrimSpace(kinds[i])
+			}
+			return "any", strings.Join(kinds, " | ")
+		}
+		return GoType(v), ""
+	case map[string]any:
+		if len(v) == 0 {
+			return "struct{}", ""
+		}
+		declare(name, v)
+		return name, ""
+	case []any:
+		if len(v) == 0 {
+			return "[]any", ""
+		}
+		elem, comment := namedGoType(name, v[0], declare)
+		return "[]" + elem, comment
+	}
+	return "any", ""
+}
@@ -0,0 +1,207 @@
+// Package gotests generates Go test skeletons from a reified board's GWT
+// scenarios.
+package gotests
+
+import (
+	"fmt"
+	"go/format"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/codegen/golang"
+)
+
+// Tests generates a Go test file with one table-driven test per change and
+// automation slice that has scenarios. Each scenario is a row: the given
+// events, the when command fields, and the then events or error. The test
+// body skips with a TODO where the command is to be run against the given
+// events, followed by the assertions on the outcome, so the file compiles
+// as is and passes once the TODO is filled in.
+//
+// flow is the reified slice data in flow order (see board.FlowSlices).
+func Tests(pkg string, flow []map[string]any) ([]byte, error) {
+	g := &generator{}
+
+	g.printf("// Code generated by emspec codegen -lang go-tests. Scaffolding: edit freely.\n\n")
+	g.printf("package %s\n\n", pkg)
+	g.printf("import (\n\t\"reflect\"\n\t\"testing\"\n)\n\n")
+	g.printf("// scenarioEvent is an event of a GWT scenario: its type and the field\n")
+	g.printf("// values the scenario pins (other fields are free).\n")
+	g.printf("type scenarioEvent struct {\n\tType   string\n\tValues map[string]any\n}\n\n")
+	g.printf("// matchEvents reports whether got are the want events, in order, with at\n")
+	g.printf("// least the values want pins.\n")
+	g.printf(`func matchEvents(got, want []scenarioEvent) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range want {
+		if got[i].Type != want[i].Type {
+			return false
+		}
+		for k, v := range want[i].Values {
+			if !reflect.DeepEqual(got[i].Values[k], v) {
+				return false
+			}
+		}
+	}
+	return true
+}
+`)
+
+	for _, data := range flow {
+		switch str(data, "type") {
+		case "change", "automation":
+			if scenarios, _ := data["scenarios"].([]any); len(scenarios) > 0 {
+				g.sliceTest(str(data, "name"), scenarios)
+			}
+		}
+	}
+
+	src, err := format.Source([]byte(g.sb.String()))
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return src, nil
+}
+
+type generator struct {
+	sb strings.Builder
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.sb, format, args...)
+}
+
+// sliceTest declares the test of one slice's scenarios.
+func (g *generator) sliceTest(name string, scenarios []any) {
+	g.printf("\n// Test%s runs the %s scenarios.\n", golang.Identifier(name), name)
+	g.printf("func Test%s(t *testing.T) {\n", golang.Identifier(name))
+	g.printf("tests := []struct {\n")
+	g.printf("name string\n")
+	g.printf("given []scenarioEvent\n")
+	g.printf("when map[string]any // %s command fields\n", name)
+	g.printf("then []scenarioEvent // emitted on success\n")
+	g.printf("wantErr string // expected error, \"\" on success\n")
+	g.printf("}{\n")
+	for _, s := range scenarios {
+		sc, _ := s.(map[string]any)
+		g.printf("{\n")
+		g.printf("name: %q,\n", str(sc, "name"))
+		if given := events(sc["given"]); given != "" {
+			g.printf("given: %s,\n", given)
+		}
+		when, _ := sc["when"].(map[string]any)
+		if values, _ := when["values"].(map[string]any); len(values) > 0 {
+			g.printf("when: %s,\n", literal(values))
+		}
+		then, _ := sc["then"].(map[string]any)
+		if success, _ := then["success"].(bool); success {
+			if emitted := events(then["events"]); emitted != "" {
+				g.printf("then: %s,\n", emitted)
+			}
+		} else {
+			wantErr := str(then, "error")
+			if wantErr == "" {
+				wantErr = "error"
+			}
+			g.printf("wantErr: %q,\n", wantErr)
+		}
+		g.printf("},\n")
+	}
+	g.printf("}\n")
+	g.printf(`for _, tt := range tests {
+	t.Run(tt.name, func(t *testing.T) {
+		t.Skip("TODO: replay tt.given, then run %[1]s with tt.when")
+
+		var emitted []scenarioEvent
+		var err error
+		// TODO: emitted, err = %[1]s(tt.given, tt.when)
+
+		if tt.wantErr != "" {
+			if err == nil {
+				t.Fatalf("want error %%q, got events %%v", tt.wantErr, emitted)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %%v", err)
+		}
+		if !matchEvents(emitted, tt.then) {
+			t.Errorf("emitted %%v, want %%v", emitted, tt.then)
+		}
+	})
+}
+}
+`, name)
+}
+
+// events renders reified event instances (bare types or {type, values}) as
+// a []scenarioEvent literal, or "" when there are none.
+func events(v any) string {
+	items, _ := v.([]any)
+	if len(items) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("[]scenarioEvent{\n")
+	for _, item := range items {
+		switch e := item.(type) {
+		case string:
+			fmt.Fprintf(&sb, "{Type: %q},\n", e)
+		case map[string]any:
+			fmt.Fprintf(&sb, "{Type: %q", str(e, "type"))
+			if values, _ := e["values"].(map[string]any); len(values) > 0 {
+				fmt.Fprintf(&sb, ", Values: %s", literal(values))
+			}
+			sb.WriteString("},\n")
+		}
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// literal renders a concrete reified value as a Go expression: structs as
+// map[string]any, lists as []any.
+func literal(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(x)
+	case bool:
+		return strconv.FormatBool(x)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		s := strconv.FormatFloat(x, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eE") {
+			s += ".0"
+		}
+		return s
+	case map[string]any:
+		var sb strings.Builder
+		sb.WriteString("map[string]any{")
+		for i, k := range slices.Sorted(maps.Keys(x)) {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(&sb, "%q: %s", k, literal(x[k]))
+		}
+		sb.WriteString("}")
+		return sb.String()
+	case []any:
+		parts := make([]string, len(x))
+		for i, e := range x {
+			parts[i] = literal(e)
+		}
+		return "[]any{" + strings.Join(parts, ", ") + "}"
+	}
+	return fmt.Sprintf("%#v", v)
+}
+
+func str(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
@@ -0,0 +1,184 @@
+// Package typescript generates TypeScript declarations from a reified board.
+package typescript
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Types generates a TypeScript module with an interface per event type and
+// per view read model, plus `type Event`, the union of the events
+// discriminated on their eventType literal. Nested structs become named
+// interfaces (parent + field name, e.g. OrderPlacedLines), lists `T[]`, and
+// kind unions like int | string `number | string`.
+//
+// events maps event types to their reified fields (see board.EventFields);
+// flow is the reified slice data in flow order (see board.FlowSlices).
+func Types(events map[string]map[string]any, flow []map[string]any) []byte {
+	g := &generator{declared: make(map[string]bool)}
+
+	g.printf("// Code generated by emspec codegen -lang ts. DO NOT EDIT.\n\n")
+
+	eventTypes := slices.Sorted(maps.Keys(events))
+	var union []string
+	for _, eventType := range eventTypes {
+		name := Identifier(eventType)
+		union = append(union, name)
+		g.iface(name, fmt.Sprintf("The %s event.", eventType), fmt.Sprintf("eventType: %q;", eventType), events[eventType])
+	}
+	if len(union) > 0 {
+		g.printf("/** Any event of the board, discriminated on eventType. */\n")
+		g.printf("export type Event =\n    | %s;\n\n", strings.Join(union, "\n    | "))
+	}
+
+	for _, data := range flow {
+		if str(data, "type") != "view" {
+			continue
+		}
+		rm := mapOf(data, "readModel")
+		model := Identifier(str(rm, "name"))
+		if model == "" {
+			model = Identifier(str(data, "name")) + "ReadModel"
+		}
+		if g.declared[model] {
+			continue
+		}
+		fields := mapOf(rm, "fields")
+		if fields == nil {
+			fields = mapOf(rm, "columns")
+		}
+		g.iface(model, fmt.Sprintf("The %s read model.", str(rm, "name")), "", fields)
+	}
+
+	return []byte(strings.TrimSuffix(g.sb.String(), "\n"))
+}
+
+type generator struct {
+	sb       strings.Builder
+	declared map[string]bool // interface names already emitted
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.sb, format, args...)
+}
+
+// iface declares an interface for fields (after the optional first member),
+// then the interfaces of its nested structs.
+func (g *generator) iface(name, doc, first string, fields map[string]any) {
+	g.declared[name] = true
+	type nestedType struct {
+		name, doc string
+		fields    map[string]any
+	}
+	var nested []nestedType
+
+	g.printf("/** %s */\n", doc)
+	g.printf("export interface %s {\n", name)
+	if first != "" {
+		g.printf("    %s\n", first)
+	}
+	for _, k := range slices.Sorted(maps.Keys(fields)) {
+		t := TSType(name+Identifier(k), fields[k], func(sub string, f map[string]any) {
+			nested = append(nested, nestedType{sub, fmt.Sprintf("The type of %s.%s.", name, k), f})
+		})
+		g.printf("    %s: %s;\n", propertyName(k), t)
+	}
+	g.printf("}\n\n")
+
+	for _, n := range nested {
+		if !g.declared[n.name] {
+			g.iface(n.name, n.doc, "", n.fields)
+		}
+	}
+}
+
+// TSType maps a reified field type to a TypeScript type. Nested structs are
+// named name and passed to declare; empty ones are `Record<string, never>`.
+func TSType(name string, t any, declare func(name string, fields map[string]any)) string {
+	switch v := t.(type) {
+	case string:
+		if kinds := strings.Split(strings.Trim(v, "()"), "|"); len(kinds) > 1 {
+			var alts []string
+			for _, k := range kinds {
+				if alt := TSType(name, strings.TrimSpace(k), declare); !slices.Contains(alts, alt) {
+					alts = append(alts, alt)
+				}
+			}
+			return strings.Join(alts, " | ")
+		}
+		switch v {
+		case "string", "bytes":
+			return "string"
+		case "int", "float", "number":
+			return "number"
+		case "bool":
+			return "boolean"
+		case "null":
+			return "null"
+		}
+		return "unknown"
+	case map[string]any:
+		if len(v) == 0 {
+			return "Record<string, never>"
+		}
+		declare(name, v)
+		return name
+	case []any:
+		if len(v) == 0 {
+			return "unknown[]"
+		}
+		elem := TSType(name, v[0], declare)
+		if strings.Contains(elem, " | ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	}
+	return "unknown"
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// propertyName quotes field names that aren't TypeScript identifiers.
+func propertyName(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+// Identifier turns a board name into a PascalCase TypeScript type name
+// ("cart_id" → "CartId", "Add item" → "AddItem").
+func Identifier(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	id := sb.String()
+	if id != "" && unicode.IsDigit([]rune(id)[0]) {
+		id = "T" + id
+	}
+	return id
+}
+
+func str(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func mapOf(m map[string]any, key string) map[string]any {
+	r, _ := m[key].(map[string]any)
+	return r
+}
@@ -0,0 +1,225 @@
+// Package diff compares two reified boards slice by slice.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
+)
+
+// Options tunes how slices of the two boards are matched.
+type Options struct {
+	// ByIndex matches slices by flow position instead of name, so a renamed
+	// slice shows as a change rather than a removal and an addition.
+	ByIndex bool
+}
+
+// Result is the semantic difference between two boards.
+type Result struct {
+	Added   []string      `json:"added,omitempty"`   // slices only in the new board
+	Removed []string      `json:"removed,omitempty"` // slices only in the old board
+	Changed []SliceChange `json:"changed,omitempty"`
+}
+
+// Empty reports whether the boards have the same slices.
+func (r Result) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
+
+// SliceChange lists the changes of one slice present in both boards.
+type SliceChange struct {
+	Slice   string   `json:"slice"`             // name in the new board
+	OldName string   `json:"oldName,omitempty"` // name in the old board, if renamed (ByIndex)
+	Changes []Change `json:"changes"`           // empty for a rename alone
+}
+
+// Change is one difference within a slice.
+type Change struct {
+	Path string `json:"path"` // e.g. "command.fields.quantity", "emits", "readModel.cardinality"
+	Kind string `json:"kind"` // "added", "removed" or "changed"
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// Change kinds.
+const (
+	KindAdded   = "added"
+	KindRemoved = "removed"
+	KindChanged = "changed"
+)
+
+// Compare diffs the slices of two IR directories' contents (see
+// board.ReadBoardFiles): slice type, command fields, emitted events,
+// queried events and read model.
+func Compare(oldManifest *board.BoardManifest, oldSlices map[string]map[string]any, newManifest *board.BoardManifest, newSlices map[string]map[string]any, opts Options) Result {
+	oldFlow := board.FlowSlices(*oldManifest, oldSlices)
+	newFlow := board.FlowSlices(*newManifest, newSlices)
+
+	key := func(i int, data map[string]any) string {
+		if opts.ByIndex {
+			return fmt.Sprint(i)
+		}
+		return str(data, "name")
+	}
+	oldByKey := map[string]map[string]any{}
+	for i, data := range oldFlow {
+		oldByKey[key(i, data)] = data
+	}
+
+	var r Result
+	matched := map[string]bool{}
+	for i, data := range newFlow {
+		k := key(i, data)
+		old, ok := oldByKey[k]
+		if !ok {
+			r.Added = append(r.Added, str(data, "name"))
+			continue
+		}
+		matched[k] = true
+		sc := SliceChange{Slice: str(data, "name"), Changes: compareSlice(old, data)}
+		if name := str(old, "name"); name != sc.Slice {
+			sc.OldName = name
+		}
+		if len(sc.Changes) > 0 || sc.OldName != "" {
+			r.Changed = append(r.Changed, sc)
+		}
+	}
+	for i, data := range oldFlow {
+		if !matched[key(i, data)] {
+			r.Removed = append(r.Removed, str(data, "name"))
+		}
+	}
+	return r
+}
+
+// compareSlice returns the changes from old to new of one slice.
+func compareSlice(old, new map[string]any) []Change {
+	var changes []Change
+	if str(old, "type") != str(new, "type") {
+		changes = append(changes, Change{Path: "type", Kind: KindChanged, Old: str(old, "type"), New: str(new, "type")})
+	}
+	changes = append(changes, compareFields("command.fields", mapOf(mapOf(old, "command"), "fields"), mapOf(mapOf(new, "command"), "fields"))...)
+	changes = append(changes, compareNames("emits", board.SliceEmits(old), board.SliceEmits(new))...)
+	changes = append(changes, compareNames("query", board.SliceConsumes(old), board.SliceConsumes(new))...)
+
+	oldRM, newRM := mapOf(old, "readModel"), mapOf(new, "readModel")
+	for _, k := range []string{"name", "cardinality"} {
+		if str(oldRM, k) != str(newRM, k) {
+			changes = append(changes, Change{Path: "readModel." + k, Kind: KindChanged, Old: str(oldRM, k), New: str(newRM, k)})
+		}
+	}
+	changes = append(changes, compareFields("readModel.fields", mapOf(oldRM, "fields"), mapOf(newRM, "fields"))...)
+	return changes
+}
+
+// compareFields diffs two field maps key by key, in key order.
+func compareFields(path string, old, new map[string]any) []Change {
+	var changes []Change
+	keys := slices.Sorted(maps.Keys(old))
+	for _, k := range slices.Sorted(maps.Keys(new)) {
+		if _, ok := old[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	for _, k := range keys {
+		o, inOld := old[k]
+		n, inNew := new[k]
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Path: path + "." + k, Kind: KindAdded, New: n})
+		case !inNew:
+			changes = append(changes, Change{Path: path + "." + k, Kind: KindRemoved, Old: o})
+		case !reflect.DeepEqual(o, n):
+			changes = append(changes, Change{Path: path + "." + k, Kind: KindChanged, Old: o, New: n})
+		}
+	}
+	return changes
+}
+
+// compareNames diffs two name lists as sets.
+func compareNames(path string, old, new []string) []Change {
+	var changes []Change
+	for _, n := range old {
+		if !slices.Contains(new, n) {
+			changes = append(changes, Change{Path: path, Kind: KindRemoved, Old: n})
+		}
+	}
+	for _, n := range new {
+		if !slices.Contains(old, n) {
+			changes = append(changes, Change{Path: path, Kind: KindAdded, New: n})
+		}
+	}
+	return changes
+}
+
+// Render renders the result as boxes: a summary of added and removed
+// slices, then one box per changed slice.
+func Render(r Result, width int) string {
+	if r.Empty() {
+		return "No changes\n"
+	}
+	var sb strings.Builder
+	if len(r.Added) > 0 || len(r.Removed) > 0 {
+		box := render.NewBoxWrapped(width)
+		box.AddLine(" SLICES")
+		box.AddSection()
+		for _, name := range r.Added {
+			box.AddLine(" + " + name)
+		}
+		for _, name := range r.Removed {
+			box.AddLine(" - " + name)
+		}
+		sb.WriteString(box.Render())
+	}
+	for _, sc := range r.Changed {
+		box := render.NewBoxWrapped(width)
+		title := " ~ " + sc.Slice
+		if sc.OldName != "" {
+			title += " (was " + sc.OldName + ")"
+		}
+		box.AddLine(title)
+		if len(sc.Changes) > 0 {
+			box.AddSection()
+		}
+		for _, c := range sc.Changes {
+			switch c.Kind {
+			case KindAdded:
+				box.AddLine(fmt.Sprintf("   + %s: %s", c.Path, format(c.New)))
+			case KindRemoved:
+				box.AddLine(fmt.Sprintf("   - %s: %s", c.Path, format(c.Old)))
+			default:
+				box.AddLine(fmt.Sprintf("   ~ %s: %s → %s", c.Path, format(c.Old), format(c.New)))
+			}
+		}
+		sb.WriteString(box.Render())
+	}
+	return sb.String()
+}
+
+// format prints a field type or name compactly.
+func format(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func str(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func mapOf(m map[string]any, key string) map[string]any {
+	r, _ := m[key].(map[string]any)
+	return r
+}
@@ -0,0 +1,122 @@
+// Package dot exports a board's event-flow graph as GraphViz DOT.
+package dot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+)
+
+// Graph renders the manifest's graph section (see board.ReifyOptions.Graph)
+// as a DOT digraph: slices are boxes, events ellipses, with an edge from each
+// consumed event to the slice and from the slice to each event it emits.
+// Slices are clustered by context, events in the context of the first slice
+// that mentions them. rankdir is the layout direction (TB, LR, BT or RL);
+// empty means LR.
+func Graph(manifest board.BoardManifest, rankdir string) (string, error) {
+	switch rankdir {
+	case "":
+		rankdir = "LR"
+	case "TB", "LR", "BT", "RL":
+	default:
+		return "", fmt.Errorf("unknown rankdir %q (want TB, LR, BT or RL)", rankdir)
+	}
+
+	contextOf := map[int]int{} // flow index → context index
+	for ci, c := range manifest.Contexts {
+		for _, ch := range c.Chapters {
+			for _, idx := range ch.FlowIndices {
+				if _, ok := contextOf[idx]; !ok {
+					contextOf[idx] = ci
+				}
+			}
+		}
+	}
+
+	g := &graph{nodes: map[int][]string{}, declared: map[string]bool{}, linked: map[string]bool{}}
+	for _, entry := range manifest.Graph {
+		ci, ok := contextOf[entry.Index]
+		if !ok {
+			ci = -1
+		}
+		slice := g.node(ci, "slice:"+entry.Slice, fmt.Sprintf("label=%s, shape=box, style=filled, fillcolor=%q", quote(entry.Slice+"\n("+entry.Type+")"), sliceColor(entry.Type)))
+		for _, evt := range entry.Consumes {
+			g.link(g.event(ci, evt), slice)
+		}
+		for _, evt := range entry.Emits {
+			g.link(slice, g.event(ci, evt))
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %s {\n", quote(manifest.Name))
+	fmt.Fprintf(&sb, "    rankdir=%s;\n", rankdir)
+	sb.WriteString("    node [fontname=\"Helvetica\"];\n")
+	for ci, c := range manifest.Contexts {
+		nodes := g.nodes[ci]
+		if len(nodes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "    subgraph cluster_%d {\n", ci)
+		fmt.Fprintf(&sb, "        label=%s;\n", quote(c.Name))
+		for _, n := range nodes {
+			fmt.Fprintf(&sb, "        %s\n", n)
+		}
+		sb.WriteString("    }\n")
+	}
+	for _, n := range g.nodes[-1] {
+		fmt.Fprintf(&sb, "    %s\n", n)
+	}
+	for _, l := range g.links {
+		fmt.Fprintf(&sb, "    %s\n", l)
+	}
+	sb.WriteString("}\n")
+	return sb.String(), nil
+}
+
+type graph struct {
+	nodes    map[int][]string // context index (-1: none) → node statements
+	declared map[string]bool  // node IDs
+	links    []string
+	linked   map[string]bool
+}
+
+// node declares a node once, in context ci, and returns its quoted ID.
+func (g *graph) node(ci int, id, attrs string) string {
+	q := quote(id)
+	if !g.declared[id] {
+		g.declared[id] = true
+		g.nodes[ci] = append(g.nodes[ci], fmt.Sprintf("%s [%s];", q, attrs))
+	}
+	return q
+}
+
+func (g *graph) event(ci int, eventType string) string {
+	return g.node(ci, "event:"+eventType, fmt.Sprintf("label=%s, shape=ellipse, style=filled, fillcolor=\"#ffb347\"", quote(eventType)))
+}
+
+// link adds an edge once.
+func (g *graph) link(from, to string) {
+	l := from + " -> " + to + ";"
+	if !g.linked[l] {
+		g.linked[l] = true
+		g.links = append(g.links, l)
+	}
+}
+
+// sliceColor matches the Mermaid export: blue commands, green read models.
+func sliceColor(sliceType string) string {
+	if sliceType == "view" {
+		return "#b5e7a0"
+	}
+	return "#a8d8ff"
+}
+
+// quote returns s as a DOT quoted string.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,85 @@
+// Package jsonschema exports board events as JSON Schema (draft 2020-12) documents.
+package jsonschema
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+)
+
+// Draft is the JSON Schema dialect of the generated documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// GenerateEventSchemas returns one JSON Schema document per event declared
+// in board.events, keyed by event type. Each validates an event payload: an
+// object with the event fields, all required. The $id is "<EventType>.schema.json".
+func GenerateEventSchemas(b *board.Board) (map[string][]byte, error) {
+	out := map[string][]byte{}
+	for eventType, fields := range board.EventFields(b) {
+		schema := FieldSchema(fields)
+		schema["$schema"] = Draft
+		schema["$id"] = FileName(eventType)
+		schema["title"] = eventType
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		out[eventType] = append(data, '\n')
+	}
+	return out, nil
+}
+
+// FileName is the file (and $id) of an event's schema.
+func FileName(eventType string) string {
+	return eventType + ".schema.json"
+}
+
+// FieldSchema maps a reified field type to a JSON Schema: kinds to types,
+// structs to objects with every field required, lists to arrays, and kind
+// unions ("int|string") to anyOf.
+func FieldSchema(t any) map[string]any {
+	switch v := t.(type) {
+	case string:
+		if kinds := strings.Split(strings.Trim(v, "()"), "|"); len(kinds) > 1 {
+			var anyOf []any
+			for _, k := range kinds {
+				anyOf = append(anyOf, FieldSchema(strings.TrimSpace(k)))
+			}
+			return map[string]any{"anyOf": anyOf}
+		}
+		switch v {
+		case "string":
+			return map[string]any{"type": "string"}
+		case "int":
+			return map[string]any{"type": "integer"}
+		case "float", "number":
+			return map[string]any{"type": "number"}
+		case "bool":
+			return map[string]any{"type": "boolean"}
+		case "null":
+			return map[string]any{"type": "null"}
+		case "bytes":
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{}
+	case map[string]any:
+		props := map[string]any{}
+		for k, f := range v {
+			props[k] = FieldSchema(f)
+		}
+		s := map[string]any{"type": "object", "properties": props}
+		if len(v) > 0 {
+			s["required"] = slices.Sorted(maps.Keys(v))
+		}
+		return s
+	case []any:
+		if len(v) == 0 {
+			return map[string]any{"type": "array"}
+		}
+		return map[string]any{"type": "array", "items": FieldSchema(v[0])}
+	}
+	return map[string]any{}
+}
@@ -0,0 +1,301 @@
+// Package markdown exports a reified board as a single Markdown document.
+package markdown
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+)
+
+// Document renders the board as Markdown: a heading per context, a
+// subheading per chapter and a section per slice with its trigger, command
+// or read model fields as tables, emitted and queried events, and GWT
+// scenarios as bullet lists. Story steps become block quotes under the
+// slice they reference.
+func Document(manifest board.BoardManifest, slices map[string]map[string]any) string {
+	stories := map[string][]board.FlowEntry{}
+	for _, entry := range manifest.Flow {
+		if entry.Kind == "story" && entry.SliceRef != "" {
+			stories[entry.SliceRef] = append(stories[entry.SliceRef], entry)
+		}
+	}
+
+	d := &document{}
+	d.printf("# %s\n", manifest.Name)
+	if len(manifest.Actors) > 0 {
+		d.printf("\nActors: %s\n", strings.Join(manifest.Actors, ", "))
+	}
+	for _, c := range manifest.Contexts {
+		d.printf("\n## %s\n", c.Name)
+		if c.Description != "" {
+			d.printf("\n%s\n", c.Description)
+		}
+		for _, ch := range c.Chapters {
+			d.printf("\n### %s\n", ch.Name)
+			if ch.Description != "" {
+				d.printf("\n%s\n", ch.Description)
+			}
+			for _, idx := range ch.FlowIndices {
+				if idx < 0 || idx >= len(manifest.Flow) {
+					continue
+				}
+				entry := manifest.Flow[idx]
+				if data, ok := slices[entry.File]; ok && entry.Kind == "slice" {
+					d.slice(data, stories[entry.Name])
+				}
+			}
+		}
+	}
+	return d.sb.String()
+}
+
+type document struct {
+	sb strings.Builder
+}
+
+func (d *document) printf(format string, args ...any) {
+	fmt.Fprintf(&d.sb, format, args...)
+}
+
+// slice renders one slice section.
+func (d *document) slice(data map[string]any, stories []board.FlowEntry) {
+	sliceType := str(data, "type")
+	d.printf("\n#### %s (%s)\n\n", str(data, "name"), sliceType)
+
+	var meta []string
+	if actor := str(data, "actor"); actor != "" {
+		meta = append(meta, "**Actor:** "+actor)
+	}
+	if status := str(data, "devstatus"); status != "" {
+		meta = append(meta, "**Status:** "+status)
+	}
+	if t := trigger(data); t != "" {
+		meta = append(meta, "**Trigger:** "+t)
+	}
+	if len(meta) > 0 {
+		d.printf("%s\n", strings.Join(meta, "  \n"))
+	}
+	if desc := str(data, "description"); desc != "" {
+		d.printf("\n%s\n", desc)
+	}
+
+	for _, s := range stories {
+		d.story(s)
+	}
+
+	if sliceType == "view" {
+		rm := mapOf(data, "readModel")
+		d.printf("\n**Read model:** %s", code(str(rm, "name")))
+		if card := str(rm, "cardinality"); card != "" {
+			d.printf(" (%s)", card)
+		}
+		d.printf("\n")
+		fields := mapOf(rm, "fields")
+		if len(fields) == 0 {
+			fields = mapOf(rm, "columns")
+		}
+		d.fieldTable(fields)
+		d.events("Queries", board.SliceConsumes(data))
+		d.viewScenarios(list(data, "scenarios"))
+		return
+	}
+
+	cmd := mapOf(data, "command")
+	d.printf("\n**Command:** %s\n", code(str(data, "name")))
+	d.fieldTable(mapOf(cmd, "fields"))
+	d.events("Queries", board.SliceConsumes(data))
+	d.events("Emits", board.SliceEmits(data))
+	d.changeScenarios(list(data, "scenarios"))
+}
+
+// story renders a story step as a block quote.
+func (d *document) story(s board.FlowEntry) {
+	d.printf("\n> **Story:** %s", s.Name)
+	if s.Actor != "" {
+		d.printf(" — %s", s.Actor)
+	}
+	d.printf("\n")
+	if s.Description != "" {
+		d.printf(">\n> %s\n", strings.ReplaceAll(s.Description, "\n", "\n> "))
+	}
+	if len(s.Instance) > 0 {
+		d.printf(">\n> Shows %s\n", code(compact(s.Instance)))
+	}
+	for _, e := range s.Emits {
+		d.printf(">\n> Emits %s\n", eventInstance(e))
+	}
+}
+
+// fieldTable renders fields as a Field | Type table, nested structs inline.
+func (d *document) fieldTable(fields map[string]any) {
+	if len(fields) == 0 {
+		return
+	}
+	d.printf("\n| Field | Type |\n| --- | --- |\n")
+	for _, k := range slices.Sorted(maps.Keys(fields)) {
+		d.printf("| %s | %s |\n", code(k), code(fieldType(fields[k])))
+	}
+}
+
+// events renders a labelled list of event types.
+func (d *document) events(label string, types []string) {
+	if len(types) == 0 {
+		return
+	}
+	d.printf("\n**%s:**\n\n", label)
+	for _, t := range types {
+		d.printf("- %s\n", code(t))
+	}
+}
+
+func (d *document) changeScenarios(scenarios []any) {
+	if len(scenarios) == 0 {
+		return
+	}
+	d.printf("\n**Scenarios:**\n")
+	for _, s := range scenarios {
+		sc, _ := s.(map[string]any)
+		d.printf("\n- %s\n", str(sc, "name"))
+		d.printf("  - Given %s\n", eventInstances(list(sc, "given")))
+		when := mapOf(sc, "when")
+		d.printf("  - When %s", code(str(when, "command")))
+		if values := mapOf(when, "values"); len(values) > 0 {
+			d.printf(" with %s", code(compact(values)))
+		}
+		d.printf("\n")
+		then := mapOf(sc, "then")
+		if success, _ := then["success"].(bool); success {
+			d.printf("  - Then %s\n", eventInstances(list(then, "events")))
+		} else {
+			d.printf("  - Then fails: %s\n", str(then, "error"))
+		}
+	}
+}
+
+func (d *document) viewScenarios(scenarios []any) {
+	if len(scenarios) == 0 {
+		return
+	}
+	d.printf("\n**Scenarios:**\n")
+	for _, s := range scenarios {
+		sc, _ := s.(map[string]any)
+		d.printf("\n- %s\n", str(sc, "name"))
+		d.printf("  - Given %s\n", eventInstances(list(sc, "given")))
+		if q, ok := sc["query"]; ok && q != nil {
+			d.printf("  - Query %s\n", code(compact(q)))
+		}
+		d.printf("  - Expect %s\n", code(compact(sc["expect"])))
+	}
+}
+
+// trigger describes a slice's trigger, or a view's endpoint.
+func trigger(data map[string]any) string {
+	if ep := mapOf(data, "endpoint"); str(ep, "path") != "" {
+		return code(str(ep, "verb") + " " + str(ep, "path"))
+	}
+	t := mapOf(data, "trigger")
+	switch str(t, "kind") {
+	case "endpoint":
+		ep := mapOf(t, "endpoint")
+		return code(str(ep, "verb") + " " + str(ep, "path"))
+	case "externalEvent":
+		ext := mapOf(t, "externalEvent")
+		s := "external event " + code(str(ext, "name"))
+		if src := str(ext, "source"); src != "" {
+			s += " from " + src
+		}
+		return s
+	case "internalEvent":
+		return "event " + code(str(mapOf(t, "internalEvent"), "eventType"))
+	case "ui":
+		return "UI action " + code(str(mapOf(t, "ui"), "name"))
+	}
+	return ""
+}
+
+// eventInstances lists given or then events, "nothing" when empty.
+func eventInstances(items []any) string {
+	if len(items) == 0 {
+		return "nothing"
+	}
+	out := make([]string, len(items))
+	for i, e := range items {
+		out[i] = eventInstance(e)
+	}
+	return strings.Join(out, ", ")
+}
+
+// eventInstance prints an event instance: its type, then its values.
+func eventInstance(e any) string {
+	switch v := e.(type) {
+	case string:
+		return code(v)
+	case map[string]any:
+		s := code(str(v, "type"))
+		if values := mapOf(v, "values"); len(values) > 0 {
+			s += " " + code(compact(values))
+		}
+		if future, _ := v["fromFuture"].(bool); future {
+			s += " (from the future)"
+		}
+		return s
+	}
+	return code(compact(e))
+}
+
+// fieldType prints a reified field type on one line.
+func fieldType(t any) string {
+	switch v := t.(type) {
+	case string:
+		return v
+	case map[string]any:
+		var fields []string
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			fields = append(fields, k+": "+fieldType(v[k]))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	case []any:
+		if len(v) > 0 {
+			return "[" + fieldType(v[0]) + "]"
+		}
+		return "[]"
+	}
+	return fmt.Sprint(t)
+}
+
+// compact prints a value as one-line JSON.
+func compact(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// code wraps s in a code span, escaping the pipes that would split a table
+// cell.
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+}
+
+func str(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func mapOf(m map[string]any, key string) map[string]any {
+	r, _ := m[key].(map[string]any)
+	return r
+}
+
+func list(m map[string]any, key string) []any {
+	l, _ := m[key].([]any)
+	return l
+}
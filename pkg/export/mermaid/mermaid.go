@@ -0,0 +1,159 @@
+// Package mermaid exports a reified board as a Mermaid flowchart.
+package mermaid
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+)
+
+// Flowchart renders the board as a Mermaid `flowchart LR`: commands, events
+// and read models are nodes; change and automation slices link their command
+// to the events it emits, queried events to the command (dotted) and trigger
+// events to automations; views link their queried events to the read model.
+// Nodes are grouped in a subgraph per context, in the context of the first
+// slice that mentions them.
+func Flowchart(manifest board.BoardManifest, slices map[string]map[string]any) string {
+	contextOf := map[int]string{}
+	for _, c := range manifest.Contexts {
+		for _, ch := range c.Chapters {
+			for _, idx := range ch.FlowIndices {
+				if _, ok := contextOf[idx]; !ok {
+					contextOf[idx] = c.Name
+				}
+			}
+		}
+	}
+
+	g := &graph{nodes: map[string][]string{}, declared: map[string]bool{}, linked: map[string]bool{}}
+	for _, entry := range manifest.Flow {
+		data, ok := slices[entry.File]
+		if !ok || entry.File == "" {
+			continue
+		}
+		ctx := contextOf[entry.Index]
+		name, _ := data["name"].(string)
+
+		switch data["type"] {
+		case "change", "automation":
+			cmd := g.node(ctx, "cmd", name, `["%s"]:::command`)
+			for _, evt := range board.SliceConsumes(data) {
+				g.link(g.event(ctx, evt), "-.->", cmd)
+			}
+			trigger, _ := data["trigger"].(map[string]any)
+			if internal, _ := trigger["internalEvent"].(map[string]any); internal != nil {
+				if evt, _ := internal["eventType"].(string); evt != "" {
+					g.link(g.event(ctx, evt), "-->", cmd)
+				}
+			}
+			for _, c := range consumedReadModels(data) {
+				g.link(g.node(ctx, "rm", c, `("%s"):::readModel`), "-.->", cmd)
+			}
+			for _, evt := range board.SliceEmits(data) {
+				g.link(cmd, "-->", g.event(ctx, evt))
+			}
+		case "view":
+			rmName := name
+			if rm, _ := data["readModel"].(map[string]any); rm != nil {
+				if n, _ := rm["name"].(string); n != "" {
+					rmName = n
+				}
+			}
+			rm := g.node(ctx, "rm", rmName, `("%s"):::readModel`)
+			for _, evt := range board.SliceConsumes(data) {
+				g.link(g.event(ctx, evt), "-->", rm)
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for _, c := range manifest.Contexts {
+		nodes := g.nodes[c.Name]
+		if c.Name == "" || len(nodes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "    subgraph %s[\"%s\"]\n", nodeID("ctx", c.Name), label(c.Name))
+		for _, n := range nodes {
+			fmt.Fprintf(&sb, "        %s\n", n)
+		}
+		sb.WriteString("    end\n")
+	}
+	for _, n := range g.nodes[""] {
+		fmt.Fprintf(&sb, "    %s\n", n)
+	}
+	for _, l := range g.links {
+		fmt.Fprintf(&sb, "    %s\n", l)
+	}
+	sb.WriteString("    classDef command fill:#a8d8ff,stroke:#333\n")
+	sb.WriteString("    classDef event fill:#ffb347,stroke:#333\n")
+	sb.WriteString("    classDef readModel fill:#b5e7a0,stroke:#333\n")
+	return sb.String()
+}
+
+type graph struct {
+	nodes    map[string][]string // context name → node declarations
+	declared map[string]bool     // node IDs
+	links    []string
+	linked   map[string]bool
+}
+
+// node declares a node once, in ctx, and returns its ID. shape is a format
+// with one %s for the label.
+func (g *graph) node(ctx, kind, name, shape string) string {
+	id := nodeID(kind, name)
+	if !g.declared[id] {
+		g.declared[id] = true
+		g.nodes[ctx] = append(g.nodes[ctx], id+fmt.Sprintf(shape, label(name)))
+	}
+	return id
+}
+
+func (g *graph) event(ctx, eventType string) string {
+	return g.node(ctx, "evt", eventType, `(["%s"]):::event`)
+}
+
+// link adds an edge once.
+func (g *graph) link(from, arrow, to string) {
+	l := from + " " + arrow + " " + to
+	if !g.linked[l] {
+		g.linked[l] = true
+		g.links = append(g.links, l)
+	}
+}
+
+// consumedReadModels returns the read models an automation slice consumes.
+func consumedReadModels(data map[string]any) []string {
+	var out []string
+	switch l := data["consumes"].(type) {
+	case []map[string]any:
+		for _, c := range l {
+			if n, _ := c["name"].(string); n != "" {
+				out = append(out, n)
+			}
+		}
+	case []any:
+		for _, c := range l {
+			m, _ := c.(map[string]any)
+			if n, _ := m["name"].(string); n != "" {
+				out = append(out, n)
+			}
+		}
+	}
+	return out
+}
+
+var nonIDChars = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// nodeID builds a Mermaid node ID; kinds keep a command and an event of the
+// same name apart.
+func nodeID(kind, name string) string {
+	return kind + "_" + nonIDChars.ReplaceAllString(name, "_")
+}
+
+// label escapes a name for a quoted Mermaid label.
+func label(name string) string {
+	return strings.ReplaceAll(name, `"`, "#quot;")
+}
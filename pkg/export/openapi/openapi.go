@@ -0,0 +1,235 @@
+// Package openapi exports the HTTP surface of a board as an OpenAPI 3.1 document.
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/export/jsonschema"
+)
+
+// GenerateOpenAPI returns the OpenAPI 3.1 document (YAML) of the board's
+// endpoints: one operation per change slice triggered by an endpoint, with
+// the endpoint body as request body, and one per view endpoint, returning
+// the read model. Path placeholders become path parameters, the other
+// params query parameters. Slices without an endpoint are left out.
+func GenerateOpenAPI(b *board.Board) ([]byte, error) {
+	manifest, sliceFiles, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+
+	doc := document{
+		OpenAPI: "3.1.0",
+		Info:    info{Title: manifest.Name, Version: "0.0.0"},
+		Paths:   map[string]map[string]*operation{},
+	}
+	schemas := map[string]any{}
+	secured := false
+
+	for _, data := range board.FlowSlices(manifest, sliceFiles) {
+		var op *operation
+		var ep map[string]any
+		switch str(data, "type") {
+		case "change":
+			trigger := mapOf(data, "trigger")
+			if str(trigger, "kind") != "endpoint" {
+				continue
+			}
+			ep = mapOf(trigger, "endpoint")
+			op = changeOperation(data, ep)
+		case "view":
+			ep = mapOf(data, "endpoint")
+			if ep == nil {
+				continue
+			}
+			op = viewOperation(data, schemas)
+		default:
+			continue
+		}
+		path := str(ep, "path")
+		op.Parameters = parameters(path, mapOf(ep, "params"))
+		if len(mapOf(ep, "auth")) > 0 {
+			op.Security = []map[string][]string{{"bearerAuth": {}}}
+			secured = true
+		}
+
+		verb := strings.ToLower(str(ep, "verb"))
+		if verb == "" {
+			verb = "get"
+		}
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*operation{}
+		}
+		if prev, ok := doc.Paths[path][verb]; ok {
+			return nil, fmt.Errorf("slices %q and %q both serve %s %s", prev.OperationID, op.OperationID, strings.ToUpper(verb), path)
+		}
+		doc.Paths[path][verb] = op
+	}
+
+	if len(schemas) > 0 || secured {
+		doc.Components = &components{}
+		if len(schemas) > 0 {
+			doc.Components.Schemas = schemas
+		}
+		if secured {
+			doc.Components.SecuritySchemes = map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"},
+			}
+		}
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// document is the subset of the OpenAPI 3.1 object model the export fills.
+// Structs keep the conventional key order; maps are sorted by the encoder.
+type document struct {
+	OpenAPI    string                           `yaml:"openapi"`
+	Info       info                             `yaml:"info"`
+	Paths      map[string]map[string]*operation `yaml:"paths"` // path → lowercase verb → operation
+	Components *components                      `yaml:"components,omitempty"`
+}
+
+type info struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+type components struct {
+	Schemas         map[string]any `yaml:"schemas,omitempty"`
+	SecuritySchemes map[string]any `yaml:"securitySchemes,omitempty"`
+}
+
+type operation struct {
+	OperationID string                `yaml:"operationId"`
+	Summary     string                `yaml:"summary,omitempty"`
+	Parameters  []parameter           `yaml:"parameters,omitempty"`
+	RequestBody *requestBody          `yaml:"requestBody,omitempty"`
+	Responses   map[string]response   `yaml:"responses"`
+	Security    []map[string][]string `yaml:"security,omitempty"`
+}
+
+type parameter struct {
+	Name     string `yaml:"name"`
+	In       string `yaml:"in"` // "path" or "query"
+	Required bool   `yaml:"required,omitempty"`
+	Schema   any    `yaml:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `yaml:"required"`
+	Content  map[string]mediaType `yaml:"content"`
+}
+
+type response struct {
+	Description string               `yaml:"description"`
+	Content     map[string]mediaType `yaml:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema any `yaml:"schema"`
+}
+
+// changeOperation builds the operation of a change slice: the endpoint body
+// as JSON request body, 204 once the events are appended.
+func changeOperation(data, ep map[string]any) *operation {
+	op := &operation{
+		OperationID: str(data, "name"),
+		Responses:   map[string]response{"204": {Description: "Command accepted"}},
+	}
+	var emitted []string
+	for _, e := range listOf(data, "emits") {
+		evt, _ := e.(map[string]any)
+		emitted = append(emitted, str(evt, "type"))
+	}
+	if len(emitted) > 0 {
+		op.Summary = "Emits " + strings.Join(emitted, ", ")
+	}
+	if body := mapOf(ep, "body"); len(body) > 0 {
+		op.RequestBody = &requestBody{
+			Required: true,
+			Content:  map[string]mediaType{"application/json": {Schema: jsonschema.FieldSchema(body)}},
+		}
+	}
+	return op
+}
+
+// viewOperation builds the operation of a view slice, declaring its read
+// model under components.schemas; table read models are returned as arrays.
+func viewOperation(data map[string]any, schemas map[string]any) *operation {
+	rm := mapOf(data, "readModel")
+	name := componentName(str(rm, "name"))
+	if name == "" {
+		name = componentName(str(data, "name"))
+	}
+	fields := mapOf(rm, "fields")
+	if fields == nil {
+		fields = mapOf(rm, "columns")
+	}
+	schemas[name] = jsonschema.FieldSchema(fields)
+
+	var result any = map[string]any{"$ref": "#/components/schemas/" + name}
+	if str(rm, "cardinality") == "table" {
+		result = map[string]any{"type": "array", "items": result}
+	}
+	return &operation{
+		OperationID: str(data, "name"),
+		Summary:     "Read " + str(rm, "name"),
+		Responses: map[string]response{"200": {
+			Description: str(rm, "name"),
+			Content:     map[string]mediaType{"application/json": {Schema: result}},
+		}},
+	}
+}
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// parameters turns endpoint params into path parameters (those named by a
+// {placeholder} of the path) and query parameters (the others), by name.
+func parameters(path string, params map[string]any) []parameter {
+	inPath := map[string]bool{}
+	for _, m := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		inPath[m[1]] = true
+	}
+	var out []parameter
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		p := parameter{Name: name, In: "query", Schema: jsonschema.FieldSchema(params[name])}
+		if inPath[name] {
+			p.In, p.Required = "path", true
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+var componentNamePattern = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// componentName makes a board name usable as a components.schemas key.
+func componentName(name string) string {
+	return componentNamePattern.ReplaceAllString(name, "_")
+}
+
+func str(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func mapOf(m map[string]any, key string) map[string]any {
+	r, _ := m[key].(map[string]any)
+	return r
+}
+
+func listOf(m map[string]any, key string) []any {
+	r, _ := m[key].([]any)
+	return r
+}
@@ -1,7 +1,10 @@
 package render
 
 import (
+	"regexp"
 	"strings"
+
+	"github.com/mattn/go-runewidth"
 )
 
 // Box drawing characters
@@ -23,6 +26,8 @@ const (
 type Box struct {
 	Width int
 	Lines []string
+	Wrap  bool // wrap over-long lines instead of truncating them
+	Plain bool // no borders: indented text, section dividers as blank lines
 }
 
 // NewBox creates a new box with specified width
@@ -30,6 +35,12 @@ func NewBox(width int) *Box {
 	return &Box{Width: width}
 }
 
+// NewBoxWrapped creates a box that wraps over-long lines onto
+// continuation lines indented like the original.
+func NewBoxWrapped(width int) *Box {
+	return &Box{Width: width, Wrap: true}
+}
+
 // AddLine adds a line of content to the box
 func (b *Box) AddLine(content string) {
 	b.Lines = append(b.Lines, content)
@@ -42,6 +53,10 @@ func (b *Box) AddSection() {
 
 // Render outputs the box as a string
 func (b *Box) Render() string {
+	if b.Plain {
+		return b.renderPlain()
+	}
+
 	var sb strings.Builder
 
 	innerWidth := b.Width - 2 // Account for borders
@@ -61,11 +76,17 @@ func (b *Box) Render() string {
 			sb.WriteString(RightT)
 			sb.WriteString("\n")
 		} else {
-			// Content line
-			sb.WriteString(Vertical)
-			sb.WriteString(padRight(line, innerWidth))
-			sb.WriteString(Vertical)
-			sb.WriteString("\n")
+			// Content line(s)
+			parts := []string{line}
+			if b.Wrap {
+				parts = wrapLine(line, innerWidth)
+			}
+			for _, part := range parts {
+				sb.WriteString(Vertical)
+				sb.WriteString(padRight(part, innerWidth))
+				sb.WriteString(Vertical)
+				sb.WriteString("\n")
+			}
 		}
 	}
 
@@ -78,13 +99,117 @@ func (b *Box) Render() string {
 	return sb.String()
 }
 
-// padRight pads a string to the specified width
+// renderPlain outputs the lines without borders or padding, for pipes and
+// CI logs. Wrapping still honors the width; nothing is truncated.
+func (b *Box) renderPlain() string {
+	var sb strings.Builder
+	for _, line := range b.Lines {
+		if line == "---SECTION---" {
+			sb.WriteString("\n")
+			continue
+		}
+		parts := []string{line}
+		if b.Wrap {
+			parts = wrapLine(line, b.Width)
+		}
+		for _, part := range parts {
+			sb.WriteString(strings.TrimRight(part, " "))
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// padRight pads a string to the specified display width, truncating it
+// at a grapheme boundary when too wide. Emoji and CJK count as two cells,
+// escape sequences none; a styled line is reset before the padding so the
+// style doesn't leak into the border.
 func padRight(s string, width int) string {
-	runeCount := len([]rune(s))
-	if runeCount >= width {
-		return string([]rune(s)[:width])
+	if displayWidth(s) > width {
+		s = truncateWidth(s, width)
+	}
+	pad := strings.Repeat(" ", max(width-displayWidth(s), 0))
+	if strings.Contains(s, "\x1b") {
+		return s + ansiReset + pad
+	}
+	return s + pad
+}
+
+// wrapLine splits a line wider than width, breaking at spaces when it can.
+// Continuation lines repeat the line's indentation, and the style in effect
+// where the line was broken.
+func wrapLine(line string, width int) []string {
+	indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+	if runewidth.StringWidth(indent) >= width/2 {
+		indent = ""
+	}
+
+	var lines []string
+	rest := line
+	for displayWidth(rest) > width {
+		head := truncateWidth(rest, width)
+		if i := strings.LastIndex(head, " "); i > len(indent) {
+			head = head[:i]
+		}
+		if len(head) <= len(indent) {
+			break // nothing fits after the indentation
+		}
+		tail := strings.TrimLeft(rest[len(head):], " ")
+		if style := activeStyle(head); style != "" {
+			head += ansiReset
+			tail = style + tail
+		}
+		lines = append(lines, head)
+		rest = indent + tail
+	}
+	return append(lines, rest)
+}
+
+// activeStyle returns the SGR sequences still in effect at the end of s:
+// those after its last reset.
+func activeStyle(s string) string {
+	var style strings.Builder
+	for _, seq := range ansiSeq.FindAllString(s, -1) {
+		if seq == ansiReset || seq == "\x1b[m" {
+			style.Reset()
+			continue
+		}
+		style.WriteString(seq)
+	}
+	return style.String()
+}
+
+// ansiSeq matches the SGR escape sequences (colors, bold) a styling hook
+// adds to a line.
+var ansiSeq = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+const ansiReset = "\x1b[0m"
+
+// displayWidth is the number of cells s takes on screen.
+func displayWidth(s string) int {
+	if !strings.Contains(s, "\x1b") {
+		return runewidth.StringWidth(s)
+	}
+	return runewidth.StringWidth(ansiSeq.ReplaceAllString(s, ""))
+}
+
+// truncateWidth returns the longest prefix of s at most width cells wide.
+// Escape sequences are kept whole, so the result is always a prefix of s.
+func truncateWidth(s string, width int) string {
+	if !strings.Contains(s, "\x1b") {
+		return runewidth.Truncate(s, width, "")
+	}
+	n := 0 // bytes kept
+	for _, loc := range ansiSeq.FindAllStringIndex(s, -1) {
+		text := s[n:loc[0]]
+		w := runewidth.StringWidth(text)
+		if w > width {
+			return s[:n+len(runewidth.Truncate(text, width, ""))]
+		}
+		width -= w
+		n = loc[1]
 	}
-	return s + strings.Repeat(" ", width-runeCount)
+	return s[:n+len(runewidth.Truncate(s[n:], width, ""))]
 }
 
 // FormatKeyValue formats a key-value pair with proper spacing
@@ -0,0 +1,120 @@
+package render
+
+import "regexp"
+
+// Severity levels for diagnostics
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// ErrorCodeDoc documents a single diagnostic code.
+type ErrorCodeDoc struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+}
+
+// errorCodes lists every diagnostic code in code order.
+// Keep in sync with the constants in validate.go (TestErrorCodeCatalog
+// fails on any code missing here).
+var errorCodes = []ErrorCodeDoc{
+	// Command errors
+	{ErrCmdFieldSource, "ErrCmdFieldSource", SeverityError, "command field must come from trigger, mapping or computed"},
+	{ErrCmdFieldType, "ErrCmdFieldType", SeverityError, "command field type must match its source"},
+	{ErrEmitFieldSource, "ErrEmitFieldSource", SeverityError, "emitted event field must come from command, mapping or computed"},
+	{ErrEmitFieldType, "ErrEmitFieldType", SeverityError, "emitted event field type must match its source"},
+	{ErrCmdPathParam, "ErrCmdPathParam", SeverityError, "endpoint path param must be declared in params"},
+	{ErrDuplicateSlice, "ErrDuplicateSlice", SeverityError, "slice names must be unique across the board"},
+	{ErrEndpointVerb, "ErrEndpointVerb", SeverityError, "endpoint verb must be GET, POST, PUT, PATCH or DELETE"},
+	{ErrEndpointPath, "ErrEndpointPath", SeverityError, "endpoint path must start with / and have balanced, non-empty {param} placeholders"},
+	{ErrCmdComputed, "ErrCmdComputed", SeverityError, "computed command field must not shadow a trigger field and must have a concrete type"},
+	{ErrAutomationShape, "ErrAutomationShape", SeverityError, "automation slice must be triggered by an event and have no actor"},
+	{ErrDevStatus, "ErrDevStatus", SeverityError, "slice devstatus must be specifying, todo, doing or done"},
+	{ErrCmdUnusedParam, "ErrCmdUnusedParam", SeverityWarning, "endpoint path param should be a command field or used by the command mapping"},
+	{ErrCmdComputedDesc, "ErrCmdComputedDesc", SeverityWarning, "computed command field should have a description"},
+	{ErrEndpointRoute, "ErrEndpointRoute", SeverityError, "endpoint verb and path must be unique across slices"},
+
+	// View errors
+	{ErrEventOrdering, "ErrEventOrdering", SeverityError, "event must be emitted by an earlier slice"},
+	{ErrViewFieldSource, "ErrViewFieldSource", SeverityError, "read model field must come from queried events, mapping or computed"},
+	{ErrComputedEvent, "ErrComputedEvent", SeverityError, "computed field source event must be in query"},
+	{ErrComputedField, "ErrComputedField", SeverityError, "computed field must exist in source event"},
+	{ErrMappingEvent, "ErrMappingEvent", SeverityError, "mapping source event must be in query"},
+	{ErrMappingField, "ErrMappingField", SeverityError, "mapping field must exist in source event"},
+	{ErrMappingType, "ErrMappingType", SeverityError, "mapped read model field type must match event field type"},
+	{ErrDottedPath, "ErrDottedPath", SeverityError, "dotted path must resolve to a read model field"},
+	{ErrDottedType, "ErrDottedType", SeverityError, "dotted path field type must match event field type"},
+	{ErrViewPathParam, "ErrViewPathParam", SeverityError, "endpoint path param must be declared in params"},
+	{ErrExpectShape, "ErrExpectShape", SeverityError, "view scenario expect must be a struct for single cardinality, a list for table"},
+	{ErrViewUnusedParam, "ErrViewUnusedParam", SeverityWarning, "endpoint path param should be a read model field or bound to a query tag"},
+	{ErrReadModelOpen, "ErrReadModelOpen", SeverityError, "read model field type must be concrete (scalar, list or closed struct)"},
+	{ErrReadModelRef, "ErrReadModelRef", SeverityError, "read model references must name a read model of the board through one of its fields"},
+
+	// DCB errors
+	{ErrEventMissingTag, "ErrEventMissingTag", SeverityError, "queried event must carry every tag of the query item"},
+	{ErrTagRequiresValue, "ErrTagRequiresValue", SeverityError, "parameterized tag requires a value in queries"},
+	{ErrEventTagField, "ErrEventTagField", SeverityError, "event carrying a parameterized tag must have a field named after its param, of the tag's type"},
+	{ErrEventShapeConflict, "ErrEventShapeConflict", SeverityError, "event type must have the same fields everywhere it is declared"},
+	{ErrSharedEventConflict, "ErrSharedEventConflict", SeverityError, "board event must match the shared events file definition of the same name"},
+
+	// Dependent query errors
+	{ErrDepExtractEventNotInQuery, "ErrDepExtractEventNotInQuery", SeverityError, "extract event must be in primary query"},
+	{ErrDepExtractFieldNotInEvent, "ErrDepExtractFieldNotInEvent", SeverityError, "extract field must exist in event"},
+	{ErrDepFromExtractAndValue, "ErrDepFromExtractAndValue", SeverityError, "tag cannot have both fromExtract and value"},
+	{ErrDepFromExtractInPrimary, "ErrDepFromExtractInPrimary", SeverityError, "fromExtract only allowed in dependent query"},
+	{ErrDepExtractCycle, "ErrDepExtractCycle", SeverityError, "dependent query extractions must not form a cycle across slices"},
+	{ErrDepFromExtractTagNotOnEvent, "ErrDepFromExtractTagNotOnEvent", SeverityError, "fromExtract tag must be carried by a dependent query event"},
+	{ErrDepFromExtractUnknown, "ErrDepFromExtractUnknown", SeverityError, "fromExtract must name an extract of the dependent query"},
+
+	// Event graph warnings
+	{ErrQueriedEventNeverEmitted, "ErrQueriedEventNeverEmitted", SeverityWarning, "queried event should be emitted by some slice"},
+	{ErrUnusedEvent, "ErrUnusedEvent", SeverityWarning, "declared event should be emitted, queried or used in a scenario"},
+	{ErrUnusedTag, "ErrUnusedTag", SeverityWarning, "declared tag should be carried by an event or used in a query"},
+
+	// Scenario errors
+	{ErrScenarioGiven, "ErrScenarioGiven", SeverityError, "scenario given event must be in query"},
+	{ErrScenarioThen, "ErrScenarioThen", SeverityError, "scenario then event must be in emits"},
+	{ErrScenarioType, "ErrScenarioType", SeverityError, "scenario event value must match field type"},
+	{ErrViewScenarioGiven, "ErrViewScenarioGiven", SeverityError, "view scenario given event must be in query"},
+	{ErrScenarioWhen, "ErrScenarioWhen", SeverityError, "scenario when values must be command fields of the declared type"},
+	{ErrScenarioThenType, "ErrScenarioThenType", SeverityError, "scenario then event values must match the event field types"},
+	{ErrScenarioTagConflict, "ErrScenarioTagConflict", SeverityWarning, "given event tag value must match the query tag binding (-check-scenario-consistency)"},
+
+	// Actor errors
+	{ErrActorUndefined, "ErrActorUndefined", SeverityError, "actor must be defined in board.actors"},
+	{ErrActorMissing, "ErrActorMissing", SeverityError, "slice must have an actor"},
+
+	// Naming errors
+	{ErrNamingConvention, "ErrNamingConvention", SeverityWarning, "name must match the configured naming pattern (-check-naming)"},
+}
+
+// ErrorCodeCatalog returns documentation for every diagnostic code, in code order.
+func ErrorCodeCatalog() []ErrorCodeDoc {
+	out := make([]ErrorCodeDoc, len(errorCodes))
+	copy(out, errorCodes)
+	return out
+}
+
+// diagnosticCodePattern matches the code prefix of a formatted diagnostic ("E102: ...").
+var diagnosticCodePattern = regexp.MustCompile(`^(E\d{3}):`)
+
+// DiagnosticCode returns the code of a formatted diagnostic, or "" if it has none.
+func DiagnosticCode(diag string) string {
+	if m := diagnosticCodePattern.FindStringSubmatch(diag); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// CodeSeverity returns the severity of a diagnostic code.
+// Unknown codes (and uncoded diagnostics) are errors.
+func CodeSeverity(code string) string {
+	for _, c := range errorCodes {
+		if c.Code == code {
+			return c.Severity
+		}
+	}
+	return SeverityError
+}
@@ -0,0 +1,163 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+// columnHeaders are the event-modeling lanes, left to right.
+var columnHeaders = []string{"TRIGGER", "COMMAND", "EVENTS", "READ MODEL", "QUERY"}
+
+// RenderBoardASCIIColumns renders the board in the canonical event-modeling
+// layout: one row per slice, with its parts sorted into trigger, command,
+// events, read model and query columns aligned across width.
+//
+// flow is the reified slice data in flow order (see board.FlowSlices).
+func RenderBoardASCIIColumns(name string, flow []map[string]any, width int) string {
+	colWidth := max((width-len(columnHeaders)-1)/len(columnHeaders), 8)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "BOARD: %s\n", name)
+	sb.WriteString(columnsRule(TopLeft, TopT, TopRight, colWidth))
+	sb.WriteString(columnsRow(columnHeaders, colWidth))
+
+	for _, data := range flow {
+		if getStr(data, "kind") != "slice" {
+			continue
+		}
+		sb.WriteString(columnsRule(LeftT, Cross, RightT, colWidth))
+
+		cells := sliceColumns(data)
+		for c, cell := range cells {
+			var wrapped []string
+			for _, line := range cell {
+				wrapped = append(wrapped, wrapCell(line, colWidth-1)...)
+			}
+			cells[c] = wrapped
+		}
+		height := 0
+		for _, cell := range cells {
+			height = max(height, len(cell))
+		}
+		for i := range height {
+			row := make([]string, len(cells))
+			for c, cell := range cells {
+				if i < len(cell) {
+					row[c] = cell[i]
+				}
+			}
+			sb.WriteString(columnsRow(row, colWidth))
+		}
+	}
+
+	sb.WriteString(columnsRule(BottomLeft, BottomT, BottomRight, colWidth))
+	return sb.String()
+}
+
+// sliceColumns classifies a slice's parts into the event-modeling columns,
+// one line per entry.
+func sliceColumns(data map[string]any) [][]string {
+	cells := make([][]string, len(columnHeaders))
+	const (
+		trigger = iota
+		command
+		events
+		readModel
+		query
+	)
+
+	if actor := getStr(data, "actor"); actor != "" {
+		cells[trigger] = append(cells[trigger], "["+actor+"]")
+	}
+
+	switch getStr(data, "type") {
+	case "change", "automation":
+		if t := columnsTrigger(getMap(data, "trigger")); t != "" {
+			cells[trigger] = append(cells[trigger], t)
+		}
+		label := getStr(data, "name")
+		if getStr(data, "type") == "automation" {
+			label = "⚙ " + label
+		}
+		cells[command] = append(cells[command], label)
+		for _, e := range getSlice(data, "emits") {
+			evt, _ := e.(map[string]any)
+			cells[events] = append(cells[events], "■ "+getStr(evt, "type"))
+		}
+		for _, rm := range getMaps(data, "consumes") {
+			cells[readModel] = append(cells[readModel], "▭ "+getStr(rm, "name"))
+		}
+		cells[query] = append(cells[query], columnsQuery(getSlice(getMap(data, "command"), "query"))...)
+	case "view":
+		if ep := getMap(data, "endpoint"); getStr(ep, "path") != "" {
+			cells[trigger] = append(cells[trigger], fmt.Sprintf("%s %s", getStr(ep, "verb"), getStr(ep, "path")))
+		}
+		cells[readModel] = append(cells[readModel], "▭ "+getStr(getMap(data, "readModel"), "name"))
+		cells[query] = append(cells[query], columnsQuery(getSlice(data, "query"))...)
+	}
+	return cells
+}
+
+// columnsTrigger labels a change or automation trigger.
+func columnsTrigger(trigger map[string]any) string {
+	switch getStr(trigger, "kind") {
+	case "endpoint":
+		ep := getMap(trigger, "endpoint")
+		return fmt.Sprintf("%s %s", getStr(ep, "verb"), getStr(ep, "path"))
+	case "externalEvent":
+		return "⚡ " + getStr(getMap(trigger, "externalEvent"), "name")
+	case "internalEvent":
+		return "⟲ " + getStr(getMap(trigger, "internalEvent"), "eventType")
+	case "ui":
+		return "UI " + getStr(getMap(trigger, "ui"), "name")
+	}
+	return ""
+}
+
+// columnsQuery lists the event types of each query stream, one stream per line.
+func columnsQuery(items []any) []string {
+	var lines []string
+	for _, q := range items {
+		item, _ := q.(map[string]any)
+		if types := getStrings(item, "types"); len(types) > 0 {
+			lines = append(lines, strings.Join(types, ", "))
+		}
+	}
+	return lines
+}
+
+// wrapCell splits a cell line to fit width, breaking between list items when it can.
+func wrapCell(s string, width int) []string {
+	var lines []string
+	r := []rune(s)
+	for len(r) > width {
+		cut := width
+		for i := width; i > 1; i-- {
+			if r[i-2] == ',' && r[i-1] == ' ' {
+				cut = i
+				break
+			}
+		}
+		lines = append(lines, strings.TrimRight(string(r[:cut]), " "))
+		r = r[cut:]
+	}
+	return append(lines, string(r))
+}
+
+// columnsRule draws a horizontal rule across all columns.
+func columnsRule(left, mid, right string, colWidth int) string {
+	segs := make([]string, len(columnHeaders))
+	for i := range segs {
+		segs[i] = strings.Repeat(Horizontal, colWidth)
+	}
+	return left + strings.Join(segs, mid) + right + "\n"
+}
+
+// columnsRow draws one line of cells, truncating each to its column.
+func columnsRow(cells []string, colWidth int) string {
+	padded := make([]string, len(cells))
+	for i, c := range cells {
+		padded[i] = padRight(" "+c, colWidth)
+	}
+	return Vertical + strings.Join(padded, Vertical) + Vertical + "\n"
+}
@@ -0,0 +1,68 @@
+package render
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ValidationError is a diagnostic broken into its parts, for tooling
+// (JSON output, the web view, editor integrations).
+type ValidationError struct {
+	Code    string `json:"code,omitempty"`  // e.g. "E102", empty for uncoded CUE errors
+	Message string `json:"message"`         // without code and position
+	Slice   string `json:"slice,omitempty"` // slice the diagnostic is about, if any
+	Field   string `json:"field,omitempty"` // field the diagnostic is about, if any
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Col     int    `json:"col,omitempty"`
+}
+
+// String formats the diagnostic as ValidateBoard reports it ("E102: msg [file:1:2]").
+func (e ValidationError) String() string {
+	loc := ""
+	if e.File != "" {
+		loc = e.File + ":" + strconv.Itoa(e.Line) + ":" + strconv.Itoa(e.Col)
+	}
+	msg := e.Message
+	if e.Code != "" {
+		msg = fmt.Sprintf("%s: %s", e.Code, msg)
+	}
+	if loc != "" {
+		return fmt.Sprintf("%s [%s]", msg, loc)
+	}
+	return msg
+}
+
+// FormatDiagnostics formats each diagnostic with String.
+func FormatDiagnostics(diags []ValidationError) []string {
+	if len(diags) == 0 {
+		return nil
+	}
+	out := make([]string, len(diags))
+	for i, d := range diags {
+		out[i] = d.String()
+	}
+	return out
+}
+
+// Trailing position added by String: " [file.cue:12:3]"
+var diagnosticPosPattern = regexp.MustCompile(`^(?s)(.*) \[(.+):(\d+):(\d+)\]$`)
+
+// ParseValidationError splits a diagnostic known only in its formatted form
+// (a build error, the errors of an IR manifest) into its code, message and
+// position. The slice and field it is about can't be told from the text:
+// use ValidateBoardStructured, or a loaded board's Diagnostics, for those.
+// String() of the result gives back diag.
+func ParseValidationError(diag string) ValidationError {
+	e := ValidationError{Code: DiagnosticCode(diag), Message: diag}
+	if e.Code != "" {
+		e.Message = diag[len(e.Code)+len(": "):]
+	}
+	if m := diagnosticPosPattern.FindStringSubmatch(e.Message); m != nil {
+		e.Message, e.File = m[1], m[2]
+		e.Line, _ = strconv.Atoi(m[3])
+		e.Col, _ = strconv.Atoi(m[4])
+	}
+	return e
+}
@@ -0,0 +1,76 @@
+package render
+
+import (
+	"fmt"
+	"regexp"
+
+	"cuelang.org/go/cue"
+)
+
+// PascalCase is the default naming pattern for events and commands.
+const PascalCase = `^[A-Z][a-zA-Z0-9]*$`
+
+// NamingConfig holds one regular expression per name category.
+// An empty pattern disables the check for that category.
+type NamingConfig struct {
+	Events   string `json:"events"`
+	Commands string `json:"commands"`
+	Actors   string `json:"actors"`
+}
+
+// DefaultNamingConfig checks events and commands for PascalCase.
+func DefaultNamingConfig() NamingConfig {
+	return NamingConfig{Events: PascalCase, Commands: PascalCase}
+}
+
+// ValidateNaming reports event types, command names and actor names that don't
+// match the configured patterns. It returns an error if a pattern doesn't compile.
+func ValidateNaming(board cue.Value, cfg NamingConfig) ([]ValidationError, error) {
+	var errs []ValidationError
+
+	check := func(category, pattern string, names []string) error {
+		if pattern == "" {
+			return nil
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("naming pattern for %s: %w", category, err)
+		}
+		for _, name := range names {
+			if !re.MatchString(name) {
+				errs = append(errs, ValidationError{Code: ErrNamingConvention, Message: fmt.Sprintf("%s %q doesn't match %s", category, name, pattern)})
+			}
+		}
+		return nil
+	}
+
+	var events, commands, actors []string
+	if iter, err := board.LookupPath(cue.ParsePath("events")).Fields(); err == nil {
+		for iter.Next() {
+			events = append(events, getString(iter.Value(), "eventType"))
+		}
+	}
+	if iter, err := board.LookupPath(cue.ParsePath("actors")).Fields(); err == nil {
+		for iter.Next() {
+			actors = append(actors, iter.Selector().Unquoted())
+		}
+	}
+	if iter, err := board.LookupPath(cue.ParsePath("flow")).List(); err == nil {
+		for iter.Next() {
+			if name := getString(iter.Value(), "command.name"); name != "" {
+				commands = append(commands, name)
+			}
+		}
+	}
+
+	if err := check("event", cfg.Events, events); err != nil {
+		return nil, err
+	}
+	if err := check("command", cfg.Commands, commands); err != nil {
+		return nil, err
+	}
+	if err := check("actor", cfg.Actors, actors); err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
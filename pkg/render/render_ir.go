@@ -2,37 +2,100 @@ package render
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 )
 
+// RenderOptions configures slice rendering.
+type RenderOptions struct {
+	Width int  // box width
+	Wrap  bool // wrap over-long lines instead of truncating them
+	Plain bool // indented text without box borders or section rules
+
+	// Style, when set, styles the text of a role (e.g. with ANSI colors,
+	// which the box width math ignores). Nil renders plain text.
+	Style func(role Role, s string) string
+}
+
+// Role is what a segment of a rendered slice shows, for RenderOptions.Style.
+type Role int
+
+const (
+	RoleChangeSlice     Role = iota // header of a change slice
+	RoleAutomationSlice             // header of an automation slice
+	RoleViewSlice                   // header of a view slice
+	RoleTrigger                     // endpoint, event or UI action triggering the slice
+	RoleEmit                        // emitted event type
+	RoleQuery                       // queried event stream
+	RoleReadModel                   // read model name
+	RoleSuccess                     // successful scenario outcome
+	RoleError                       // failing scenario outcome
+)
+
+// style applies the Style hook, if any.
+func (o RenderOptions) style(role Role, s string) string {
+	if o.Style == nil || s == "" {
+		return s
+	}
+	return o.Style(role, s)
+}
+
+// newBox creates a box for the options.
+func (o RenderOptions) newBox() *Box {
+	return &Box{Width: o.Width, Wrap: o.Wrap, Plain: o.Plain}
+}
+
+// separator separates the parts of a header line.
+func (o RenderOptions) separator() string {
+	if o.Plain {
+		return "|"
+	}
+	return Vertical
+}
+
 // RenderSliceIR renders a slice from its IR (map[string]any) as ASCII box art.
 func RenderSliceIR(data map[string]any, width int) (string, error) {
+	return RenderSliceIRWithOptions(data, RenderOptions{Width: width})
+}
+
+// RenderSliceIRPlain renders a slice from its IR as indented text, without
+// box-drawing characters. Lines are wrapped at width, if positive.
+func RenderSliceIRPlain(data map[string]any, width int) (string, error) {
+	return RenderSliceIRWithOptions(data, RenderOptions{Width: width, Wrap: width > 0, Plain: true})
+}
+
+// RenderSliceIRWithOptions renders a slice from its IR with the given options.
+func RenderSliceIRWithOptions(data map[string]any, opts RenderOptions) (string, error) {
 	kind := getStr(data, "kind")
 	switch kind {
 	case "slice":
 		sliceType := getStr(data, "type")
 		if sliceType == "view" {
-			return renderViewSliceIR(data, width)
+			return renderViewSliceIR(data, opts)
 		}
-		return renderChangeSliceIR(data, width)
+		return renderChangeSliceIR(data, opts)
 	case "story":
-		return renderStoryIR(data, width)
+		return renderStoryIR(data, opts)
 	default:
 		return "", fmt.Errorf("unknown kind: %s", kind)
 	}
 }
 
-func renderChangeSliceIR(data map[string]any, width int) (string, error) {
-	box := NewBox(width)
+func renderChangeSliceIR(data map[string]any, opts RenderOptions) (string, error) {
+	box := opts.newBox()
 
 	name := getStr(data, "name")
 	sliceType := getStr(data, "type")
-	actor := getStr(data, "actor")
-	box.AddLine(fmt.Sprintf("  SLICE: %s (%s)  │  Actor: %s", name, sliceType, actor))
+	if sliceType == "automation" {
+		box.AddLine("  " + opts.style(RoleAutomationSlice, fmt.Sprintf("SLICE: %s (%s)", name, sliceType)))
+	} else {
+		box.AddLine(fmt.Sprintf("  %s  %s  Actor: %s", opts.style(RoleChangeSlice, fmt.Sprintf("SLICE: %s (%s)", name, sliceType)), opts.separator(), getStr(data, "actor")))
+	}
 
 	if img := getStr(data, "image"); img != "" {
 		box.AddLine(fmt.Sprintf("  📷 %s", img))
-		if ascii := renderImageASCII(img, width); ascii != "" {
+		if ascii := renderImageASCII(img, opts.Width); ascii != "" {
 			box.AddLine("")
 			for line := range strings.SplitSeq(ascii, "\n") {
 				box.AddLine("  " + line)
@@ -48,27 +111,52 @@ func renderChangeSliceIR(data map[string]any, width int) (string, error) {
 	triggerKind := getStr(trigger, "kind")
 	if triggerKind == "endpoint" {
 		ep := getMap(trigger, "endpoint")
-		box.AddLine(fmt.Sprintf("  %s %s", getStr(ep, "verb"), getStr(ep, "path")))
+		box.AddLine("  " + opts.style(RoleTrigger, getStr(ep, "verb")+" "+getStr(ep, "path")))
 
 		if body := getMap(ep, "body"); len(body) > 0 {
 			box.AddLine("    body:")
-			for k, v := range body {
+			for _, k := range slices.Sorted(maps.Keys(body)) {
+				v := body[k]
 				box.AddLine(fmt.Sprintf("      - %s: %s", k, irTypeStr(v)))
 			}
 		}
 		if auth := getMap(ep, "auth"); len(auth) > 0 {
 			box.AddLine("    auth:")
-			for k, v := range auth {
+			for _, k := range slices.Sorted(maps.Keys(auth)) {
+				v := auth[k]
 				box.AddLine(fmt.Sprintf("      - %s: %s", k, irTypeStr(v)))
 			}
 		}
 	} else if triggerKind == "externalEvent" {
 		ext := getMap(trigger, "externalEvent")
-		box.AddLine(fmt.Sprintf("  External Event: %s", getStr(ext, "name")))
+		box.AddLine("  External Event: " + opts.style(RoleTrigger, getStr(ext, "name")))
 
 		if fields := getMap(ext, "fields"); len(fields) > 0 {
 			box.AddLine("    fields:")
-			for k, v := range fields {
+			for _, k := range slices.Sorted(maps.Keys(fields)) {
+				v := fields[k]
+				box.AddLine(fmt.Sprintf("      - %s: %s", k, irTypeStr(v)))
+			}
+		}
+	} else if triggerKind == "internalEvent" {
+		in := getMap(trigger, "internalEvent")
+		box.AddLine("  Internal Event: " + opts.style(RoleTrigger, getStr(in, "eventType")))
+
+		if fields := getMap(in, "fields"); len(fields) > 0 {
+			box.AddLine("    fields:")
+			for _, k := range slices.Sorted(maps.Keys(fields)) {
+				v := fields[k]
+				box.AddLine(fmt.Sprintf("      - %s: %s", k, irTypeStr(v)))
+			}
+		}
+	} else if triggerKind == "ui" {
+		ui := getMap(trigger, "ui")
+		box.AddLine("  UI Action: " + opts.style(RoleTrigger, getStr(ui, "name")))
+
+		if fields := getMap(ui, "fields"); len(fields) > 0 {
+			box.AddLine("    fields:")
+			for _, k := range slices.Sorted(maps.Keys(fields)) {
+				v := fields[k]
 				box.AddLine(fmt.Sprintf("      - %s: %s", k, irTypeStr(v)))
 			}
 		}
@@ -80,7 +168,8 @@ func renderChangeSliceIR(data map[string]any, width int) (string, error) {
 	box.AddLine(fmt.Sprintf("  Command: %s", name))
 	box.AddLine("    fields:")
 	if fields := getMap(cmd, "fields"); len(fields) > 0 {
-		for k, v := range fields {
+		for _, k := range slices.Sorted(maps.Keys(fields)) {
+			v := fields[k]
 			box.AddLine(fmt.Sprintf("      - %s: %s", k, irTypeStr(v)))
 		}
 	}
@@ -88,7 +177,8 @@ func renderChangeSliceIR(data map[string]any, width int) (string, error) {
 	// Command mapping
 	if mapping := getMapStr(cmd, "mapping"); len(mapping) > 0 {
 		box.AddLine("    mapping:")
-		for k, v := range mapping {
+		for _, k := range slices.Sorted(maps.Keys(mapping)) {
+			v := mapping[k]
 			box.AddLine(fmt.Sprintf("      - %s ← %s", k, v))
 		}
 	}
@@ -96,14 +186,19 @@ func renderChangeSliceIR(data map[string]any, width int) (string, error) {
 	// Command computed
 	if computed := getMap(cmd, "computed"); len(computed) > 0 {
 		box.AddLine("    computed:")
-		for k, v := range computed {
+		for _, k := range slices.Sorted(maps.Keys(computed)) {
+			v := computed[k]
 			cm, _ := v.(map[string]any)
-			typ := getStr(cm, "type")
-			desc := getStr(cm, "description")
-			if desc != "" {
-				box.AddLine(fmt.Sprintf("      - %s: %s (%s)", k, typ, desc))
-			} else {
-				box.AddLine(fmt.Sprintf("      - %s: %s", k, typ))
+			line := k
+			if typ, ok := cm["type"]; ok {
+				line += ": " + irTypeStr(typ)
+			}
+			if expr := getStr(cm, "expr"); expr != "" {
+				line += " ← " + expr
+			}
+			box.AddLine("      - " + line)
+			if desc := getStr(cm, "description"); desc != "" {
+				box.AddLine("          " + desc)
 			}
 		}
 	}
@@ -111,10 +206,16 @@ func renderChangeSliceIR(data map[string]any, width int) (string, error) {
 	// DCB Query
 	if query := getSlice(cmd, "query"); len(query) > 0 {
 		box.AddLine("    Query:")
-		for _, qi := range query {
-			for _, line := range formatQueryItemIR(qi) {
-				box.AddLine(fmt.Sprintf("      - %s", line))
-			}
+		for _, line := range formatQueryIR(query) {
+			box.AddLine("      - " + opts.style(RoleQuery, line))
+		}
+	}
+
+	// Dependent query
+	if dep := getMap(cmd, "dependentQuery"); len(dep) > 0 {
+		box.AddLine("    Dependent Query:")
+		for _, line := range formatDependentQueryIR(dep, opts) {
+			box.AddLine("      - " + line)
 		}
 	}
 
@@ -124,9 +225,10 @@ func renderChangeSliceIR(data map[string]any, width int) (string, error) {
 	if emits := getSlice(data, "emits"); len(emits) > 0 {
 		for _, e := range emits {
 			em, _ := e.(map[string]any)
-			box.AddLine(fmt.Sprintf("    %s", getStr(em, "type")))
+			box.AddLine("    " + opts.style(RoleEmit, getStr(em, "type")))
 			if fields := getMap(em, "fields"); len(fields) > 0 {
-				for k, v := range fields {
+				for _, k := range slices.Sorted(maps.Keys(fields)) {
+					v := fields[k]
 					box.AddLine(fmt.Sprintf("      - %s: %s", k, irTypeStr(v)))
 				}
 			}
@@ -145,9 +247,15 @@ func renderChangeSliceIR(data map[string]any, width int) (string, error) {
 			box.AddLine(fmt.Sprintf("      When:  %s %s", getStr(when, "command"), formatValuesIR(getMap(when, "values"))))
 			then := getMap(sm, "then")
 			if getBool(then, "success") {
-				box.AddLine(fmt.Sprintf("      Then:  ✓ %s", formatGivenIR(getSlice(then, "events"))))
+				for i, line := range formatThenIR(getSlice(then, "events")) {
+					if i == 0 {
+						box.AddLine("      Then:  " + opts.style(RoleSuccess, "✓ "+line))
+					} else {
+						box.AddLine("               " + opts.style(RoleSuccess, line))
+					}
+				}
 			} else {
-				box.AddLine(fmt.Sprintf("      Then:  ✗ %s", getStr(then, "error")))
+				box.AddLine("      Then:  " + opts.style(RoleError, "✗ "+getStr(then, "error")))
 			}
 		}
 	}
@@ -155,17 +263,17 @@ func renderChangeSliceIR(data map[string]any, width int) (string, error) {
 	return box.Render(), nil
 }
 
-func renderViewSliceIR(data map[string]any, width int) (string, error) {
-	box := NewBox(width)
+func renderViewSliceIR(data map[string]any, opts RenderOptions) (string, error) {
+	box := opts.newBox()
 
 	name := getStr(data, "name")
 	actor := getStr(data, "actor")
-	box.AddLine(fmt.Sprintf("  VIEW: %s  │  Actor: %s", name, actor))
+	box.AddLine(fmt.Sprintf("  %s  %s  Actor: %s", opts.style(RoleViewSlice, "VIEW: "+name), opts.separator(), actor))
 
 	// Image (optional)
 	if img := getStr(data, "image"); img != "" {
 		box.AddLine(fmt.Sprintf("  📷 %s", img))
-		if ascii := renderImageASCII(img, width); ascii != "" {
+		if ascii := renderImageASCII(img, opts.Width); ascii != "" {
 			box.AddSection()
 			for _, line := range normalizeIndent(ascii) {
 				box.AddLine("  " + line)
@@ -176,16 +284,18 @@ func renderViewSliceIR(data map[string]any, width int) (string, error) {
 	// Endpoint
 	ep := getMap(data, "endpoint")
 	box.AddSection()
-	box.AddLine(fmt.Sprintf("  %s %s", getStr(ep, "verb"), getStr(ep, "path")))
+	box.AddLine("  " + opts.style(RoleTrigger, getStr(ep, "verb")+" "+getStr(ep, "path")))
 	if params := getMap(ep, "params"); len(params) > 0 {
 		box.AddLine("    params:")
-		for k, v := range params {
+		for _, k := range slices.Sorted(maps.Keys(params)) {
+			v := params[k]
 			box.AddLine(fmt.Sprintf("      - %s: %s", k, irTypeStr(v)))
 		}
 	}
 	if auth := getMap(ep, "auth"); len(auth) > 0 {
 		box.AddLine("    auth:")
-		for k, v := range auth {
+		for _, k := range slices.Sorted(maps.Keys(auth)) {
+			v := auth[k]
 			box.AddLine(fmt.Sprintf("      - %s: %s", k, irTypeStr(v)))
 		}
 	}
@@ -193,7 +303,7 @@ func renderViewSliceIR(data map[string]any, width int) (string, error) {
 	// ReadModel
 	rm := getMap(data, "readModel")
 	box.AddSection()
-	box.AddLine(fmt.Sprintf("  ReadModel: %s (%s)", getStr(rm, "name"), getStr(rm, "cardinality")))
+	box.AddLine(fmt.Sprintf("  ReadModel: %s (%s)", opts.style(RoleReadModel, getStr(rm, "name")), getStr(rm, "cardinality")))
 	box.AddLine("    fields:")
 	if fields := getMap(rm, "fields"); len(fields) > 0 {
 		renderFieldsIR(fields, "      ", box)
@@ -202,7 +312,8 @@ func renderViewSliceIR(data map[string]any, width int) (string, error) {
 	// Mapping
 	if mapping := getMap(rm, "mapping"); len(mapping) > 0 {
 		box.AddLine("    mapping:")
-		for k, v := range mapping {
+		for _, k := range slices.Sorted(maps.Keys(mapping)) {
+			v := mapping[k]
 			box.AddLine(fmt.Sprintf("      - %s ← %s", k, irTypeStr(v)))
 		}
 	}
@@ -210,7 +321,8 @@ func renderViewSliceIR(data map[string]any, width int) (string, error) {
 	// Computed
 	if computed := getMap(rm, "computed"); len(computed) > 0 {
 		box.AddLine("    computed:")
-		for k, v := range computed {
+		for _, k := range slices.Sorted(maps.Keys(computed)) {
+			v := computed[k]
 			cm, _ := v.(map[string]any)
 			event := getStr(cm, "event")
 			fields := getSlice(cm, "fields")
@@ -224,14 +336,26 @@ func renderViewSliceIR(data map[string]any, width int) (string, error) {
 		}
 	}
 
+	// References to other read models
+	if refs := getMapStr(rm, "references"); len(refs) > 0 {
+		box.AddLine("    references:")
+		for _, k := range slices.Sorted(maps.Keys(refs)) {
+			box.AddLine(fmt.Sprintf("      - %s → %s", k, refs[k]))
+		}
+	}
+
 	// Query
 	box.AddSection()
 	box.AddLine("  Query:")
 	if query := getSlice(data, "query"); len(query) > 0 {
-		for _, qi := range query {
-			for _, line := range formatQueryItemIR(qi) {
-				box.AddLine(fmt.Sprintf("    - %s", line))
-			}
+		for _, line := range formatQueryIR(query) {
+			box.AddLine("    - " + opts.style(RoleQuery, line))
+		}
+	}
+	if dep := getMap(data, "dependentQuery"); len(dep) > 0 {
+		box.AddLine("  Dependent Query:")
+		for _, line := range formatDependentQueryIR(dep, opts) {
+			box.AddLine("    - " + line)
 		}
 	}
 
@@ -248,7 +372,8 @@ func renderViewSliceIR(data map[string]any, width int) (string, error) {
 			}
 			box.AddLine("      Expect: {")
 			if expect := getMap(sm, "expect"); len(expect) > 0 {
-				for k, v := range expect {
+				for _, k := range slices.Sorted(maps.Keys(expect)) {
+					v := expect[k]
 					box.AddLine(fmt.Sprintf("        %s: %s", k, formatAnyIR(v)))
 				}
 			}
@@ -259,8 +384,8 @@ func renderViewSliceIR(data map[string]any, width int) (string, error) {
 	return box.Render(), nil
 }
 
-func renderStoryIR(data map[string]any, width int) (string, error) {
-	box := NewBox(width)
+func renderStoryIR(data map[string]any, opts RenderOptions) (string, error) {
+	box := opts.newBox()
 	sliceRef := getStr(data, "sliceRef")
 	desc := getStr(data, "description")
 	box.AddLine(fmt.Sprintf("  STORY: refs %s", sliceRef))
@@ -304,12 +429,16 @@ func getMapStr(m map[string]any, key string) map[string]string {
 	if !ok {
 		return nil
 	}
+	if typed, ok := v.(map[string]string); ok {
+		return typed
+	}
 	raw, ok := v.(map[string]any)
 	if !ok {
 		return nil
 	}
 	out := make(map[string]string)
-	for k, val := range raw {
+	for _, k := range slices.Sorted(maps.Keys(raw)) {
+		val := raw[k]
 		if s, ok := val.(string); ok {
 			out[k] = s
 		}
@@ -329,6 +458,48 @@ func getSlice(m map[string]any, key string) []any {
 	return r
 }
 
+// getStrings returns a string list, accepting both in-memory ([]string) and
+// JSON-decoded ([]any) IR.
+func getStrings(m map[string]any, key string) []string {
+	if m == nil {
+		return nil
+	}
+	switch v := m[key].(type) {
+	case []string:
+		return v
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, s := range v {
+			if str, ok := s.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// getMaps returns a list of objects, accepting both in-memory
+// ([]map[string]any) and JSON-decoded ([]any) IR.
+func getMaps(m map[string]any, key string) []map[string]any {
+	if m == nil {
+		return nil
+	}
+	switch v := m[key].(type) {
+	case []map[string]any:
+		return v
+	case []any:
+		out := make([]map[string]any, 0, len(v))
+		for _, item := range v {
+			if obj, ok := item.(map[string]any); ok {
+				out = append(out, obj)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
 func getBool(m map[string]any, key string) bool {
 	if m == nil {
 		return false
@@ -358,7 +529,8 @@ func irTypeStr(v any) string {
 }
 
 func renderFieldsIR(fields map[string]any, indent string, box *Box) {
-	for k, v := range fields {
+	for _, k := range slices.Sorted(maps.Keys(fields)) {
+		v := fields[k]
 		switch t := v.(type) {
 		case map[string]any:
 			box.AddLine(fmt.Sprintf("%s- %s:", indent, k))
@@ -367,7 +539,8 @@ func renderFieldsIR(fields map[string]any, indent string, box *Box) {
 			if len(t) == 1 {
 				if inner, ok := t[0].(map[string]any); ok {
 					box.AddLine(fmt.Sprintf("%s- %s: [", indent, k))
-					for ik, iv := range inner {
+					for _, ik := range slices.Sorted(maps.Keys(inner)) {
+						iv := inner[ik]
 						box.AddLine(fmt.Sprintf("%s    %s: %s", indent, ik, irTypeStr(iv)))
 					}
 					box.AddLine(fmt.Sprintf("%s  ]", indent))
@@ -383,38 +556,70 @@ func renderFieldsIR(fields map[string]any, indent string, box *Box) {
 	}
 }
 
-func formatQueryItemIR(qi any) []string {
+// formatQueryIR renders a DCB query one stream (query item) per line, keeping
+// together the types and tags that select the same stream.
+func formatQueryIR(items []any) []string {
+	var lines []string
+	for i, qi := range items {
+		lines = append(lines, fmt.Sprintf("Stream %d: %s", i+1, formatQueryItemIR(qi)))
+	}
+	return lines
+}
+
+// formatDependentQueryIR renders a dependent query: each value extracted
+// from the primary query's events, then the streams bound to them.
+func formatDependentQueryIR(dep map[string]any, opts RenderOptions) []string {
+	var lines []string
+	extract := getMap(dep, "extract")
+	for _, k := range slices.Sorted(maps.Keys(extract)) {
+		ex, _ := extract[k].(map[string]any)
+		line := fmt.Sprintf("extract.%s ← %s.%s", k, getStr(ex, "event"), getStr(ex, "field"))
+		if many, _ := ex["many"].(bool); many {
+			line += " (many)"
+		}
+		lines = append(lines, line)
+	}
+	for _, line := range formatQueryIR(getSlice(dep, "items")) {
+		lines = append(lines, opts.style(RoleQuery, line))
+	}
+	return lines
+}
+
+func formatQueryItemIR(qi any) string {
 	m, ok := qi.(map[string]any)
 	if !ok {
-		return nil
+		return ""
 	}
-	typesRaw := getSlice(m, "types")
-	tagsRaw := getSlice(m, "tags")
 
 	var tags []string
-	for _, t := range tagsRaw {
+	for _, t := range getSlice(m, "tags") {
 		tm, ok := t.(map[string]any)
 		if !ok {
 			continue
 		}
 		tagName := getStr(tm, "tag")
-		if param := getStr(tm, "param"); param != "" {
+		switch {
+		case getStr(tm, "fromExtract") != "":
+			tags = append(tags, fmt.Sprintf("%s←extract.%s", tagName, getStr(tm, "fromExtract")))
+		case getStr(tm, "value") != "":
+			tags = append(tags, fmt.Sprintf("%s=%s", tagName, getStr(tm, "value")))
+		case getStr(tm, "param") != "":
 			tags = append(tags, fmt.Sprintf("%s=<binding>", tagName))
-		} else {
+		default:
 			tags = append(tags, tagName)
 		}
 	}
 
-	var lines []string
-	for _, et := range typesRaw {
-		s, _ := et.(string)
-		if len(tags) > 0 {
-			lines = append(lines, fmt.Sprintf("[%s, tagged: %s]", s, strings.Join(tags, " AND ")))
-		} else {
-			lines = append(lines, fmt.Sprintf("[%s]", s))
-		}
+	// No types: the item selects every event type carrying the tags
+	types := getStrings(m, "types")
+	if len(types) == 0 {
+		types = []string{"*"}
 	}
-	return lines
+	line := fmt.Sprintf("[%s]", strings.Join(types, ", "))
+	if len(tags) > 0 {
+		line += fmt.Sprintf(" tagged %s", strings.Join(tags, " AND "))
+	}
+	return line
 }
 
 func formatGivenIR(items []any) string {
@@ -437,13 +642,35 @@ func formatGivenIR(items []any) string {
 	return strings.Join(parts, ", ")
 }
 
+// formatThenIR formats the expected events of a success outcome, one per line,
+// each with the field values it asserts (e.g. `OrderPlaced {amount: 100}`).
+func formatThenIR(items []any) []string {
+	if len(items) == 0 {
+		return []string{"(no events)"}
+	}
+	var lines []string
+	for _, item := range items {
+		switch t := item.(type) {
+		case string:
+			lines = append(lines, t)
+		case map[string]any:
+			et := getStr(t, "type")
+			if vals := getMap(t, "values"); len(vals) > 0 {
+				et += " " + formatValuesIR(vals)
+			}
+			lines = append(lines, et)
+		}
+	}
+	return lines
+}
+
 func formatValuesIR(m map[string]any) string {
 	if len(m) == 0 {
 		return "{}"
 	}
 	var parts []string
-	for k, v := range m {
-		parts = append(parts, fmt.Sprintf("%s: %s", k, formatAnyIR(v)))
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		parts = append(parts, fmt.Sprintf("%s: %s", k, formatAnyIR(m[k])))
 	}
 	return "{" + strings.Join(parts, ", ") + "}"
 }
@@ -0,0 +1,42 @@
+package render
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RenderStateMachine renders the lifecycle of the aggregate keyed by tagName
+// as a Mermaid stateDiagram. Every emitted event carrying the tag is a
+// transition, in the order the flow first emits it.
+//
+// flow is the reified slice data in flow order (see board.FlowSlices).
+func RenderStateMachine(flow []map[string]any, tagName string) (string, error) {
+	var events []string
+	for _, data := range flow {
+		for _, e := range getSlice(data, "emits") {
+			evt, _ := e.(map[string]any)
+			eventType := getStr(evt, "type")
+			if slices.Contains(getStrings(evt, "tags"), tagName) && !slices.Contains(events, eventType) {
+				events = append(events, eventType)
+			}
+		}
+	}
+	if len(events) == 0 {
+		return "", fmt.Errorf("no emitted event is tagged %q", tagName)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("stateDiagram-v2\n")
+	fmt.Fprintf(&sb, "    %%%% lifecycle of %s\n", tagName)
+	for i, evt := range events {
+		fmt.Fprintf(&sb, "    state \"after %s\" as s%d\n", evt, i+1)
+	}
+	from := "[*]"
+	for i, evt := range events {
+		to := fmt.Sprintf("s%d", i+1)
+		fmt.Fprintf(&sb, "    %s --> %s : %s\n", from, to, evt)
+		from = to
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,80 @@
+package render
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RenderTimeline renders the board as an event-storming style timeline: each
+// domain event (■) in flow order, preceded by the command (or automation ⚙)
+// that produces it. Views appear as read models (▭) at their position in the
+// flow with the events they read.
+//
+// flow is the reified slice data in flow order (see board.FlowSlices).
+func RenderTimeline(name string, flow []map[string]any) string {
+	// Align the events column on the longest command label
+	labelWidth := 0
+	for _, data := range flow {
+		if getStr(data, "type") == "view" {
+			continue
+		}
+		labelWidth = max(labelWidth, len([]rune(timelineLabel(data))))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "TIMELINE: %s\n\n", name)
+
+	for _, data := range flow {
+		if getStr(data, "kind") != "slice" {
+			continue
+		}
+
+		switch getStr(data, "type") {
+		case "change", "automation":
+			label := padRight(timelineLabel(data), labelWidth)
+			emits := getSlice(data, "emits")
+			if len(emits) == 0 {
+				fmt.Fprintf(&sb, "  %s ──▶ (no events)\n", label)
+				continue
+			}
+			for i, e := range emits {
+				evt, _ := e.(map[string]any)
+				if i == 0 {
+					fmt.Fprintf(&sb, "  %s ──▶ ■ %s\n", label, getStr(evt, "type"))
+				} else {
+					fmt.Fprintf(&sb, "  %s     ■ %s\n", strings.Repeat(" ", labelWidth), getStr(evt, "type"))
+				}
+			}
+		case "view":
+			var reads []string
+			for _, q := range getSlice(data, "query") {
+				item, _ := q.(map[string]any)
+				for _, t := range getStrings(item, "types") {
+					if !slices.Contains(reads, t) {
+						reads = append(reads, t)
+					}
+				}
+			}
+			line := fmt.Sprintf("  %s     ▭ %s", strings.Repeat(" ", labelWidth), getStr(data, "name"))
+			if len(reads) > 0 {
+				line += fmt.Sprintf(" (reads %s)", strings.Join(reads, ", "))
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// timelineLabel returns the command sticky label for a change or automation slice.
+func timelineLabel(data map[string]any) string {
+	label := getStr(data, "name")
+	if getStr(data, "type") == "automation" {
+		label = "⚙ " + label
+	}
+	if actor := getStr(data, "actor"); actor != "" {
+		label = fmt.Sprintf("[%s] %s", actor, label)
+	}
+	return label
+}
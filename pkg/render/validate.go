@@ -8,6 +8,7 @@ import (
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
 )
 
 // Error codes by category:
@@ -16,6 +17,8 @@ import (
 //	E2xx - View slice
 //	E3xx - DCB query
 //	E4xx - GWT scenarios
+//	E5xx - Actors
+//	E6xx - Naming conventions (opt-in)
 const (
 	// Command errors
 	ErrCmdFieldSource  = "E101" // field must come from trigger
@@ -23,6 +26,15 @@ const (
 	ErrEmitFieldSource = "E103" // emit field must come from command
 	ErrEmitFieldType   = "E104" // emit field type mismatch
 	ErrCmdPathParam    = "E105" // path param not in params
+	ErrDuplicateSlice  = "E106" // slice name used by several slices
+	ErrEndpointVerb    = "E107" // endpoint verb not a known HTTP method
+	ErrEndpointPath    = "E108" // endpoint path malformed
+	ErrCmdComputed     = "E109" // computed command field shadows trigger or has no concrete type
+	ErrAutomationShape = "E110" // automation slice not event-triggered or declares an actor
+	ErrDevStatus       = "E111" // devstatus not a known status
+	ErrCmdUnusedParam  = "E112" // path param not used by the command
+	ErrCmdComputedDesc = "E113" // computed command field has no description
+	ErrEndpointRoute   = "E114" // endpoint route declared by several slices
 
 	// View errors
 	ErrEventOrdering   = "E201" // event must be emitted before
@@ -35,26 +47,47 @@ const (
 	ErrDottedPath      = "E208" // dotted path doesn't resolve
 	ErrDottedType      = "E209" // dotted path type mismatch
 	ErrViewPathParam   = "E210" // path param not in params
+	ErrExpectShape     = "E211" // scenario expect shape doesn't match cardinality
+	ErrViewUnusedParam = "E212" // path param not used by the read model or query
+	ErrReadModelOpen   = "E213" // read model field type not concrete
+	ErrReadModelRef    = "E214" // read model reference dangling
 
 	// DCB errors
-	ErrEventMissingTag  = "E301" // event missing required tag
-	ErrTagRequiresValue = "E302" // parameterized tag requires value
+	ErrEventMissingTag     = "E301" // event missing required tag
+	ErrTagRequiresValue    = "E302" // parameterized tag requires value
+	ErrEventTagField       = "E303" // event carries a parameterized tag without its field
+	ErrEventShapeConflict  = "E305" // same event type declared with different fields
+	ErrSharedEventConflict = "E306" // board event conflicts with shared events file
 
 	// Dependent query errors
-	ErrDepExtractEventNotInQuery = "E311" // extract event not in primary query
-	ErrDepExtractFieldNotInEvent = "E312" // extract field not in event
-	ErrDepFromExtractAndValue    = "E313" // cannot have both fromExtract and value
-	ErrDepFromExtractInPrimary   = "E314" // fromExtract only allowed in dependent query
+	ErrDepExtractEventNotInQuery   = "E311" // extract event not in primary query
+	ErrDepExtractFieldNotInEvent   = "E312" // extract field not in event
+	ErrDepFromExtractAndValue      = "E313" // cannot have both fromExtract and value
+	ErrDepFromExtractInPrimary     = "E314" // fromExtract only allowed in dependent query
+	ErrDepExtractCycle             = "E315" // dependent query extractions form a cycle
+	ErrDepFromExtractTagNotOnEvent = "E316" // fromExtract tag not carried by any dependent event
+	ErrDepFromExtractUnknown       = "E317" // fromExtract names no extract of the dependent query
+
+	// Event graph warnings
+	ErrQueriedEventNeverEmitted = "E320" // queried event not emitted by any slice
+	ErrUnusedEvent              = "E321" // declared event never referenced
+	ErrUnusedTag                = "E322" // declared tag never referenced
 
 	// Scenario errors
-	ErrScenarioGiven     = "E401" // given event not in query
-	ErrScenarioThen      = "E402" // then event not in emits
-	ErrScenarioType      = "E403" // event value type mismatch
-	ErrViewScenarioGiven = "E404" // view scenario given not in query
+	ErrScenarioGiven       = "E401" // given event not in query
+	ErrScenarioThen        = "E402" // then event not in emits
+	ErrScenarioType        = "E403" // event value type mismatch
+	ErrViewScenarioGiven   = "E404" // view scenario given not in query
+	ErrScenarioWhen        = "E405" // when value not a command field or wrong type
+	ErrScenarioThenType    = "E406" // then event value doesn't match event field type
+	ErrScenarioTagConflict = "E407" // given event contradicts query tag binding
 
 	// Actor errors
 	ErrActorUndefined = "E501" // actor not defined in board.actors
 	ErrActorMissing   = "E502" // actor field missing from slice
+
+	// Naming errors
+	ErrNamingConvention = "E601" // name doesn't match configured pattern
 )
 
 var (
@@ -70,6 +103,7 @@ var (
 	cmdTypeIncompatiblePattern = regexp.MustCompile(`slice_(\w+)_field_(\w+)_type`)
 	// Pattern: slice_CreateCart_emit_CartCreated_field_cartId_source
 	emitFieldSourcePattern = regexp.MustCompile(`slice_(\w+)_emit_(\w+)_field_(\w+)_source`)
+	autoEmitSourcePattern  = regexp.MustCompile(`automation_(\w+)_emit_(\w+)_field_(\w+)_source`)
 	// Pattern: slice_CreateCart_emit_CartCreated_field_cartId_type
 	emitTypeIncompatiblePattern = regexp.MustCompile(`slice_(\w+)_emit_(\w+)_field_(\w+)_type`)
 	// Pattern: view_ReadA_computed_total_event_must_be_queried
@@ -94,6 +128,26 @@ var (
 	scenarioThenPattern = regexp.MustCompile(`slice_(\w+)_scenario(\d+)_then_(\w+)_must_be_in_emits`)
 	// Pattern: slice_AddItem_endpoint_path_param_cartId_must_be_in_params (or view_)
 	pathParamPattern = regexp.MustCompile(`(slice|view)_(\w+)_endpoint_path_param_(\w+)_must_be_in_params`)
+	// Pattern: board.flow.1.endpoint.verb: conflicting values "GET" and "POSTT" (one per #HTTPVerb)
+	verbConflictPattern = regexp.MustCompile(`^(\S*\bflow\.(\d+))\.(?:trigger\.)?endpoint\.verb: conflicting values "(?:GET|POST|PUT|PATCH|DELETE)" and "([^"]*)"`)
+	// Pattern: board.flow.0.scenarios.1.when.qty: conflicting values "x" and int (mismatched types string and int)
+	whenTypePattern = regexp.MustCompile(`^(\S*\bflow\.(\d+))\.scenarios\.(\d+)\.when\.(\w+): conflicting values (.+) and (\S+) \(mismatched types`)
+	// Pattern: board.flow.1.dependentQuery._validateRefs.0: conflicting values "oid" and "orderRef" (one per extract name)
+	fromExtractPattern = regexp.MustCompile(`^(\S*\bflow\.(\d+))\.(?:command\.)?dependentQuery\._validateRefs\.\d+: conflicting values "[^"]*" and "([^"]*)"`)
+	// Pattern: board.flow.1.scenarios.0.expect: conflicting values [...readModel.columns] and {id:"c1"} (mismatched types list and struct)
+	expectShapePattern = regexp.MustCompile(`^(\S*\bflow\.(\d+))\.scenarios\.(\d+)\.expect: conflicting values (.+) \(mismatched types (\w+) and (\w+)\)`)
+	// Pattern: board.flow.1.devstatus: conflicting values "todo" and "blocked" (one per #DevStatus)
+	devStatusPattern = regexp.MustCompile(`^(\S*\bflow\.(\d+))\.devstatus: conflicting values "(?:specifying|todo|doing|done)" and "([^"]*)"`)
+	// Pattern: board.flow.1.type: conflicting values "change" and "automation" (the element is an automation)
+	automationTypePattern = regexp.MustCompile(`^(\S*\bflow\.\d+)\.type: conflicting values "\w+" and "automation"`)
+	// Pattern: board.flow.1.trigger.kind: conflicting values "externalEvent" and "endpoint" (one per #AutomationTrigger)
+	automationTriggerPattern = regexp.MustCompile(`^(\S*\bflow\.(\d+))\.trigger\.kind: conflicting values "(?:externalEvent|internalEvent)" and "([^"]*)"`)
+	// Pattern: board.flow.1.actor: conflicting values null and {name:"User"} (mismatched types null and struct)
+	automationActorPattern = regexp.MustCompile(`^(\S*\bflow\.(\d+))\.actor: conflicting values null and`)
+	// Pattern: board.flow.1.type: conflicting values "change" and "view" (a failed #Instant or #Trigger branch)
+	instantBranchPattern = regexp.MustCompile(`^(\S*\bflow\.\d+)\.(?:trigger\.)?(?:kind|type): conflicting values`)
+	// Pattern: board.contexts.0.chapters.0.flow.1, a flow element seen through its chapter
+	contextFlowPattern = regexp.MustCompile(`\bcontexts\.\d+\.chapters\.\d+\.flow\.\d+$`)
 	// Pattern: _actorValid
 	actorValidPattern = regexp.MustCompile(`_actorValid`)
 	// Pattern: board.flow.N.actor: field is required but not present
@@ -102,111 +156,167 @@ var (
 
 var (
 	// Type mismatch path patterns for friendly formatting
-	cmdFieldTypeRe       = regexp.MustCompile(`slice_(\w+)_field_(\w+)_type`)
-	emitFieldTypeRe      = regexp.MustCompile(`slice_(\w+)_emit_(\w+)_field_(\w+)_type`)
-	mappingTypeRe        = regexp.MustCompile(`view_(\w+)_mapping_(\w+)_type`)
-	scenarioTypeRe       = regexp.MustCompile(`_validValues\.(\w+)`)
-	autoFieldTypeRe      = regexp.MustCompile(`automation_(\w+)_field_(\w+)_type`)
-	autoEmitFieldTypeRe  = regexp.MustCompile(`automation_(\w+)_emit_(\w+)_field_(\w+)_type`)
+	cmdFieldTypeRe      = regexp.MustCompile(`slice_(\w+)_field_(\w+)_type`)
+	emitFieldTypeRe     = regexp.MustCompile(`slice_(\w+)_emit_(\w+)_field_(\w+)_type`)
+	mappingTypeRe       = regexp.MustCompile(`view_(\w+)_mapping_(\w+)_type`)
+	scenarioTypeRe      = regexp.MustCompile(`_validValues\.(\w+)`)
+	autoFieldTypeRe     = regexp.MustCompile(`automation_(\w+)_field_(\w+)_type`)
+	autoEmitFieldTypeRe = regexp.MustCompile(`automation_(\w+)_emit_(\w+)_field_(\w+)_type`)
 )
 
-// formatTypeMismatch returns (code, friendly message) for a type mismatch path
-func formatTypeMismatch(path, expectedType, gotType, gotValue string) (string, string) {
+// formatTypeMismatch returns the diagnostic for a type mismatch path
+func formatTypeMismatch(path, expectedType, gotType, gotValue string) ValidationError {
 	if m := emitFieldTypeRe.FindStringSubmatch(path); m != nil {
-		return ErrEmitFieldType, fmt.Sprintf("slice %q emits %q: field %q type mismatch -> event expects <%s> command declares <%s>", m[1], m[2], m[3], gotType, expectedType)
+		return ValidationError{Code: ErrEmitFieldType, Message: fmt.Sprintf("slice %q emits %q: field %q type mismatch -> event expects <%s> command declares <%s>", m[1], m[2], m[3], gotType, expectedType), Slice: m[1], Field: m[3]}
 	}
 	if m := cmdFieldTypeRe.FindStringSubmatch(path); m != nil {
-		return ErrCmdFieldType, fmt.Sprintf("slice %q field %q: command expects %s, trigger provides %s", m[1], m[2], gotType, expectedType)
+		return ValidationError{Code: ErrCmdFieldType, Message: fmt.Sprintf("slice %q field %q: command expects %s, trigger provides %s", m[1], m[2], gotType, expectedType), Slice: m[1], Field: m[2]}
 	}
 	if m := mappingTypeRe.FindStringSubmatch(path); m != nil {
-		return ErrMappingType, fmt.Sprintf("view %q mapping %q: type mismatch (expected %s, got %s)", m[1], m[2], expectedType, gotType)
+		return ValidationError{Code: ErrMappingType, Message: fmt.Sprintf("view %q mapping %q: type mismatch (expected %s, got %s)", m[1], m[2], expectedType, gotType), Slice: m[1]}
 	}
 	if m := scenarioTypeRe.FindStringSubmatch(path); m != nil {
-		return ErrScenarioType, fmt.Sprintf("scenario field %s: expected %s, got %s (%s)", m[1], expectedType, gotType, gotValue)
+		return ValidationError{Code: ErrScenarioType, Message: fmt.Sprintf("scenario field %s: expected %s, got %s (%s)", m[1], expectedType, gotType, gotValue), Field: m[1]}
 	}
-	return ErrScenarioType, fmt.Sprintf("%s: expected %s, got %s (%s)", path, expectedType, gotType, gotValue)
+	return ValidationError{Code: ErrScenarioType, Message: fmt.Sprintf("%s: expected %s, got %s (%s)", path, expectedType, gotType, gotValue)}
 }
 
-// fmtErr formats an error with code, message, and optional location
-func fmtErr(code, msg, loc string) string {
-	if loc != "" {
-		return fmt.Sprintf("%s: %s [%s]", code, msg, loc)
-	}
-	return fmt.Sprintf("%s: %s", code, msg)
-}
+var typeMismatchRe = regexp.MustCompile(`(\w+(?:\.\w+)*): conflicting values (\S+) and (\w+) \(mismatched types (\w+) and (\w+)\)`)
 
 // FormatCUEError takes a CUE error and returns a user-friendly message with position info
 func FormatCUEError(err error) string {
 	if err == nil {
 		return ""
 	}
+	results := formatCUEErrors(err)
+	if len(results) == 0 {
+		return err.Error()
+	}
+	return strings.Join(FormatDiagnostics(results), "\n")
+}
 
-	fullErrStr := err.Error()
-
-	// Look for type mismatch pattern in full error string
-	typeMismatchRe := regexp.MustCompile(`(\w+(?:\.\w+)*): conflicting values (\S+) and (\w+) \(mismatched types (\w+) and (\w+)\)`)
-	if matches := typeMismatchRe.FindAllStringSubmatch(fullErrStr, -1); len(matches) > 0 {
-		var results []string
-		seen := make(map[string]bool)
-		for _, match := range matches {
-			path := match[1]
-			gotValue := match[2]
-			expectedType := match[3]
-			gotType := match[4]
-			code, msg := formatTypeMismatch(path, expectedType, gotType, gotValue)
-			formatted := fmtErr(code, msg, "")
-			if !seen[formatted] {
-				seen[formatted] = true
-				results = append(results, formatted)
-			}
+// formatCUEErrors formats each error of a CUE error list, deduplicated,
+// with the position of the error it comes from. Structural noise is
+// dropped.
+func formatCUEErrors(err error) []ValidationError {
+	errs := errors.Errors(err)
+
+	// A flow element failing the #Instant disjunction reports why each
+	// branch failed. When the reason is a known one (its verb, a scenario
+	// when value, a fromExtract name, a view expect shape, a devstatus, an
+	// automation's trigger or actor), the kind/type mismatches of the other
+	// branches are noise.
+	automations := make(map[string]bool)
+	for _, e := range errs {
+		if match := automationTypePattern.FindStringSubmatch(e.Error()); match != nil {
+			automations[match[1]] = true
+		}
+	}
+	explained := make(map[string]bool)
+	for _, e := range errs {
+		if elem, _, ok := formatFlowElementError(e.Error(), automations); ok {
+			explained[elem] = true
 		}
-		return strings.Join(results, "\n")
 	}
 
-	// Collect all errors from the error list
-	allErrs := errors.Errors(err)
 	seen := make(map[string]bool)
-	var results []string
-
-	for _, e := range allErrs {
-		code, msg := formatSingleError(e)
-		if code == "" {
+	var results []ValidationError
+	for _, e := range errs {
+		var d ValidationError
+		pos := firstPosition(e)
+		if elem, elemDiag, ok := formatFlowElementError(e.Error(), automations); ok {
+			if contextFlowPattern.MatchString(elem) {
+				continue // the same element is reported at its board.flow index
+			}
+			d = elemDiag
+			pos = lastPosition(e) // the board's value, not the schema
+		} else if match := instantBranchPattern.FindStringSubmatch(e.Error()); match != nil && explained[match[1]] {
+			continue
+		} else if match := typeMismatchRe.FindStringSubmatch(e.Error()); match != nil {
+			d = formatTypeMismatch(match[1], match[3], match[4], match[2])
+		} else if d = formatSingleError(e); d.Code == "" {
 			continue // Skip noise
 		}
-		pos := extractPosition(e)
-		formatted := fmtErr(code, msg, pos)
-		if !seen[formatted] {
+		if pos.Filename() != "" {
+			d.File, d.Line, d.Col = pos.Filename(), pos.Line(), pos.Column()
+		}
+		if formatted := d.String(); !seen[formatted] {
 			seen[formatted] = true
-			results = append(results, formatted)
+			results = append(results, d)
 		}
 	}
+	return results
+}
 
-	if len(results) == 0 {
-		return err.Error()
+// formatFlowElementError formats the errors that make a flow element fail
+// the #Instant disjunction, returning the element's path and its diagnostic. automations holds the paths of the elements declared as
+// automations, whose non-event trigger kind is the reason they fail rather
+// than branch noise. ok is false for other errors.
+func formatFlowElementError(msg string, automations map[string]bool) (elem string, d ValidationError, ok bool) {
+	if match := verbConflictPattern.FindStringSubmatch(msg); match != nil {
+		return match[1], ValidationError{Code: ErrEndpointVerb, Message: fmt.Sprintf("slice at flow index %s: endpoint verb %q must be one of %s", match[2], match[3], strings.Join(httpVerbs, ", "))}, true
+	}
+	if match := whenTypePattern.FindStringSubmatch(msg); match != nil {
+		return match[1], ValidationError{Code: ErrScenarioWhen, Message: fmt.Sprintf("slice at flow index %s scenario %s when: field %q must be %s, got %s", match[2], match[3], match[4], match[6], match[5]), Field: match[4]}, true
+	}
+	if match := fromExtractPattern.FindStringSubmatch(msg); match != nil {
+		return match[1], ValidationError{Code: ErrDepFromExtractUnknown, Message: fmt.Sprintf("slice at flow index %s dependentQuery: fromExtract %q is not declared in extract", match[2], match[3])}, true
+	}
+	if match := expectShapePattern.FindStringSubmatch(msg); match != nil {
+		// A table read model expects [...readModel.columns], a single one
+		// its fields struct: the other side of the mismatch is the board's.
+		cardinality, want := "single", "struct"
+		if strings.Contains(match[4], "[...readModel.columns]") {
+			cardinality, want = "table", "list"
+		}
+		got := match[5]
+		if got == want {
+			got = match[6]
+		}
+		article := "a"
+		if strings.ContainsRune("aeiou", rune(got[0])) {
+			article = "an"
+		}
+		return match[1], ValidationError{Code: ErrExpectShape, Message: fmt.Sprintf("view at flow index %s scenario %s: expect is %s %s but read model cardinality is %q", match[2], match[3], article, got, cardinality)}, true
 	}
-	return strings.Join(results, "\n")
+	if match := devStatusPattern.FindStringSubmatch(msg); match != nil {
+		return match[1], ValidationError{Code: ErrDevStatus, Message: fmt.Sprintf("slice at flow index %s devstatus %q is not one of %s", match[2], match[3], strings.Join(DevStatuses, ", "))}, true
+	}
+	if match := automationTriggerPattern.FindStringSubmatch(msg); match != nil && automations[match[1]] && !strings.HasSuffix(match[3], "Event") {
+		return match[1], ValidationError{Code: ErrAutomationShape, Message: fmt.Sprintf("automation at flow index %s is triggered by %s, want externalEvent or internalEvent", match[2], match[3])}, true
+	}
+	if match := automationActorPattern.FindStringSubmatch(msg); match != nil {
+		return match[1], ValidationError{Code: ErrAutomationShape, Message: fmt.Sprintf("automation at flow index %s declares an actor; automations run without one", match[2])}, true
+	}
+	return "", ValidationError{}, false
 }
 
-// extractPosition gets file:line:col from a CUE error
-func extractPosition(err errors.Error) string {
+// lastPosition gets the last position of a CUE error.
+func lastPosition(err errors.Error) token.Pos {
 	positions := errors.Positions(err)
 	if len(positions) == 0 {
-		return ""
+		return token.NoPos
 	}
-	p := positions[0]
-	if p.Filename() == "" {
-		return ""
+	return positions[len(positions)-1]
+}
+
+// firstPosition gets the first position of a CUE error.
+func firstPosition(err errors.Error) token.Pos {
+	positions := errors.Positions(err)
+	if len(positions) == 0 {
+		return token.NoPos
 	}
-	return fmt.Sprintf("%s:%d:%d", p.Filename(), p.Line(), p.Column())
+	return positions[0]
 }
 
-// formatSingleError formats a single error message, returns (code, message)
-func formatSingleError(err errors.Error) (string, string) {
+// formatSingleError formats a single error message; the diagnostic has no
+// code when the error is noise.
+func formatSingleError(err errors.Error) ValidationError {
 	msg := err.Error()
 
 	// Skip structural noise
 	if strings.Contains(msg, "field not allowed") || strings.Contains(msg, "incompatible list lengths") {
-		return "", ""
+		return ValidationError{}
 	}
 
 	// Empty disjunction means all branches of a union type failed to unify.
@@ -214,106 +324,109 @@ func formatSingleError(err errors.Error) (string, string) {
 	// don't silently drop it.
 	if strings.Contains(msg, "empty disjunction") {
 		if m := autoEmitFieldTypeRe.FindStringSubmatch(msg); m != nil {
-			return ErrEmitFieldType, fmt.Sprintf("automation %q emits %q: field %q type mismatch (union type incompatible)", m[1], m[2], m[3])
+			return ValidationError{Code: ErrEmitFieldType, Message: fmt.Sprintf("automation %q emits %q: field %q type mismatch (union type incompatible)", m[1], m[2], m[3]), Slice: m[1], Field: m[3]}
 		}
 		if m := autoFieldTypeRe.FindStringSubmatch(msg); m != nil {
-			return ErrCmdFieldType, fmt.Sprintf("automation %q command: field %q type mismatch (union type incompatible)", m[1], m[2])
+			return ValidationError{Code: ErrCmdFieldType, Message: fmt.Sprintf("automation %q command: field %q type mismatch (union type incompatible)", m[1], m[2]), Slice: m[1], Field: m[2]}
 		}
 		if m := emitFieldTypeRe.FindStringSubmatch(msg); m != nil {
-			return ErrEmitFieldType, fmt.Sprintf("slice %q emits %q: field %q type mismatch (union type incompatible)", m[1], m[2], m[3])
+			return ValidationError{Code: ErrEmitFieldType, Message: fmt.Sprintf("slice %q emits %q: field %q type mismatch (union type incompatible)", m[1], m[2], m[3]), Slice: m[1], Field: m[3]}
 		}
 		if m := cmdFieldTypeRe.FindStringSubmatch(msg); m != nil {
-			return ErrCmdFieldType, fmt.Sprintf("slice %q command: field %q type mismatch (union type incompatible)", m[1], m[2])
+			return ValidationError{Code: ErrCmdFieldType, Message: fmt.Sprintf("slice %q command: field %q type mismatch (union type incompatible)", m[1], m[2]), Slice: m[1], Field: m[2]}
 		}
 		if m := mappingTypeRe.FindStringSubmatch(msg); m != nil {
-			return ErrMappingType, fmt.Sprintf("view %q mapping %q: type mismatch (union type incompatible)", m[1], m[2])
+			return ValidationError{Code: ErrMappingType, Message: fmt.Sprintf("view %q mapping %q: type mismatch (union type incompatible)", m[1], m[2]), Slice: m[1]}
 		}
-		return "", "" // Not a type-validation key — skip as disjunction noise
+		return ValidationError{} // Not a type-validation key — skip as disjunction noise
 	}
 
 	// DCB: event missing tag
 	if match := tagErrorPattern.FindStringSubmatch(msg); match != nil {
-		return ErrEventMissingTag, fmt.Sprintf("slice %q query: event %q must have tag %q", match[1], match[2], match[3])
+		return ValidationError{Code: ErrEventMissingTag, Message: fmt.Sprintf("slice %q query: event %q must have tag %q", match[1], match[2], match[3]), Slice: match[1]}
 	}
 
 	// View: event ordering
 	if match := orderErrorPattern.FindStringSubmatch(msg); match != nil {
-		return ErrEventOrdering, fmt.Sprintf("slice %q query: event %q must be emitted by an earlier slice", match[1], match[2])
+		return ValidationError{Code: ErrEventOrdering, Message: fmt.Sprintf("slice %q query: event %q must be emitted by an earlier slice", match[1], match[2]), Slice: match[1]}
 	}
 
 	// DCB: tag requires value
 	if match := tagValuePattern.FindStringSubmatch(msg); match != nil {
-		return ErrTagRequiresValue, fmt.Sprintf("slice %q query: tag %q must have a value (parameterized tag)", match[1], match[2])
+		return ValidationError{Code: ErrTagRequiresValue, Message: fmt.Sprintf("slice %q query: tag %q must have a value (parameterized tag)", match[1], match[2]), Slice: match[1]}
 	}
 
 	// Command: emit field source (check before general field patterns)
 	if match := emitFieldSourcePattern.FindStringSubmatch(msg); match != nil {
-		return ErrEmitFieldSource, fmt.Sprintf("slice %q emit %q: field %q must come from command or computed", match[1], match[2], match[3])
+		return ValidationError{Code: ErrEmitFieldSource, Message: fmt.Sprintf("slice %q emit %q: field %q is not a command field, nor in the emit mapping or computed", match[1], match[2], match[3]), Slice: match[1], Field: match[3]}
+	}
+	if match := autoEmitSourcePattern.FindStringSubmatch(msg); match != nil {
+		return ValidationError{Code: ErrEmitFieldSource, Message: fmt.Sprintf("automation %q emit %q: field %q is not a command field, nor in a consumed read model, the emit mapping or computed", match[1], match[2], match[3]), Slice: match[1], Field: match[3]}
 	}
 
 	// Command: emit field type (check before general type patterns)
 	if match := emitTypeIncompatiblePattern.FindStringSubmatch(msg); match != nil {
-		return ErrEmitFieldType, fmt.Sprintf("slice %q emit %q: field %q must match command field type", match[1], match[2], match[3])
+		return ValidationError{Code: ErrEmitFieldType, Message: fmt.Sprintf("slice %q emit %q: field %q must match command field type", match[1], match[2], match[3]), Slice: match[1], Field: match[3]}
 	}
 
 	// Command: field source
 	if match := fieldSourcePattern.FindStringSubmatch(msg); match != nil {
-		return ErrCmdFieldSource, fmt.Sprintf("slice %q command: field %q must come from trigger, needs a mapping or be computed", match[1], match[2])
+		return ValidationError{Code: ErrCmdFieldSource, Message: fmt.Sprintf("slice %q command: field %q must come from trigger, needs a mapping or be computed", match[1], match[2]), Slice: match[1], Field: match[2]}
 	}
 
 	// Command: field type
 	if match := cmdTypeIncompatiblePattern.FindStringSubmatch(msg); match != nil {
-		return ErrCmdFieldType, fmt.Sprintf("slice %q command: field %q must match trigger field type", match[1], match[2])
+		return ValidationError{Code: ErrCmdFieldType, Message: fmt.Sprintf("slice %q command: field %q must match trigger field type", match[1], match[2]), Slice: match[1], Field: match[2]}
 	}
 
 	// View: field source
 	if match := viewFieldSourcePattern.FindStringSubmatch(msg); match != nil {
-		return ErrViewFieldSource, fmt.Sprintf("view %q readModel: field %q must come from queried events or computed", match[1], match[2])
+		return ValidationError{Code: ErrViewFieldSource, Message: fmt.Sprintf("view %q readModel: field %q must come from queried events or computed", match[1], match[2]), Slice: match[1], Field: match[2]}
 	}
 
 	// View: computed event not queried
 	if match := computedEventPattern.FindStringSubmatch(msg); match != nil {
-		return ErrComputedEvent, fmt.Sprintf("view %q computed %q: source event must be in query", match[1], match[2])
+		return ValidationError{Code: ErrComputedEvent, Message: fmt.Sprintf("view %q computed %q: source event must be in query", match[1], match[2]), Slice: match[1]}
 	}
 
 	// View: computed field not in event
 	if match := computedFieldPattern.FindStringSubmatch(msg); match != nil {
-		return ErrComputedField, fmt.Sprintf("view %q computed %q: field %q must exist in source event", match[1], match[2], match[3])
+		return ValidationError{Code: ErrComputedField, Message: fmt.Sprintf("view %q computed %q: field %q must exist in source event", match[1], match[2], match[3]), Slice: match[1], Field: match[3]}
 	}
 
 	// View: mapping event not queried
 	if match := mappingEventPattern.FindStringSubmatch(msg); match != nil {
-		return ErrMappingEvent, fmt.Sprintf("view %q mapping %q: source event must be in query", match[1], match[2])
+		return ValidationError{Code: ErrMappingEvent, Message: fmt.Sprintf("view %q mapping %q: source event must be in query", match[1], match[2]), Slice: match[1]}
 	}
 
 	// View: mapping field not in event
 	if match := mappingFieldPattern.FindStringSubmatch(msg); match != nil {
-		return ErrMappingField, fmt.Sprintf("view %q mapping %q: field %q must exist in source event", match[1], match[2], match[3])
+		return ValidationError{Code: ErrMappingField, Message: fmt.Sprintf("view %q mapping %q: field %q must exist in source event", match[1], match[2], match[3]), Slice: match[1], Field: match[3]}
 	}
 
 	// View: mapping type mismatch
 	if match := mappingTypePattern.FindStringSubmatch(msg); match != nil {
-		return ErrMappingType, fmt.Sprintf("view %q mapping %q: must match source event field type", match[1], match[2])
+		return ValidationError{Code: ErrMappingType, Message: fmt.Sprintf("view %q mapping %q: must match source event field type", match[1], match[2]), Slice: match[1]}
 	}
 
 	// Scenario: event value type mismatch
 	if match := scenarioTypeMismatchPattern.FindStringSubmatch(msg); match != nil {
-		return ErrScenarioType, fmt.Sprintf("scenario field %s: must be %s, got %s (%s)", match[1], match[3], match[4], match[2])
+		return ValidationError{Code: ErrScenarioType, Message: fmt.Sprintf("scenario field %s: must be %s, got %s (%s)", match[1], match[3], match[4], match[2]), Field: match[1]}
 	}
 
 	// Scenario: view given event not in query
 	if match := viewScenarioGivenPattern.FindStringSubmatch(msg); match != nil {
-		return ErrViewScenarioGiven, fmt.Sprintf("view %q scenario %s: given event %q must be in query", match[1], match[2], match[3])
+		return ValidationError{Code: ErrViewScenarioGiven, Message: fmt.Sprintf("view %q scenario %s: given event %q must be in query", match[1], match[2], match[3]), Slice: match[1]}
 	}
 
 	// Scenario: slice given event not in query
 	if match := sliceScenarioGivenPattern.FindStringSubmatch(msg); match != nil {
-		return ErrScenarioGiven, fmt.Sprintf("slice %q scenario %s: given event %q must be in query", match[1], match[2], match[3])
+		return ValidationError{Code: ErrScenarioGiven, Message: fmt.Sprintf("slice %q scenario %s: given event %q must be in query", match[1], match[2], match[3]), Slice: match[1]}
 	}
 
 	// Scenario: then event not in emits
 	if match := scenarioThenPattern.FindStringSubmatch(msg); match != nil {
-		return ErrScenarioThen, fmt.Sprintf("slice %q scenario %s: then event %q must be in emits", match[1], match[2], match[3])
+		return ValidationError{Code: ErrScenarioThen, Message: fmt.Sprintf("slice %q scenario %s: then event %q must be in emits", match[1], match[2], match[3]), Slice: match[1]}
 	}
 
 	// Endpoint: path param missing
@@ -323,38 +436,37 @@ func formatSingleError(err errors.Error) (string, string) {
 		if kind == "view" {
 			code = ErrViewPathParam
 		}
-		return code, fmt.Sprintf("%s %q endpoint: path param {%s} must be in params", kind, match[2], match[3])
+		return ValidationError{Code: code, Message: fmt.Sprintf("%s %q endpoint: path param {%s} must be in params", kind, match[2], match[3]), Slice: match[2]}
 	}
 
 	// Actor: not defined in board.actors
 	if actorValidPattern.MatchString(msg) {
-		return ErrActorUndefined, "slice actor must be defined in board.actors"
+		return ValidationError{Code: ErrActorUndefined, Message: "slice actor must be defined in board.actors"}
 	}
 
 	// Actor: missing from slice
 	if actorMissingPattern.MatchString(msg) {
-		return ErrActorMissing, "slice must have an actor field"
+		return ValidationError{Code: ErrActorMissing, Message: "slice must have an actor field"}
 	}
 
 	// Return raw error if no pattern matches (avoid hiding errors)
-	return "E000", msg
+	return ValidationError{Code: "E000", Message: msg}
 }
 
 // ValidateBoard validates a board and returns formatted error messages
+// (see ValidateBoardStructured for the parts of each message).
 func ValidateBoard(board cue.Value) []string {
-	var errs []string
+	return FormatDiagnostics(ValidateBoardStructured(board))
+}
+
+// ValidateBoardStructured runs the CUE and Go validation passes and returns
+// the diagnostics broken into their parts.
+func ValidateBoardStructured(board cue.Value) []ValidationError {
+	var errs []ValidationError
 
 	// CUE validation happens automatically - we just need to check for errors
 	if err := board.Validate(); err != nil {
-		for line := range strings.SplitSeq(err.Error(), "\n") {
-			if line == "" {
-				continue
-			}
-			formatted := FormatCUEError(fmt.Errorf("%s", line))
-			if formatted != "" && !slices.Contains(errs, formatted) {
-				errs = append(errs, formatted)
-			}
-		}
+		errs = append(errs, formatCUEErrors(err)...)
 	}
 
 	// Additional Go validation: actor must be present and defined
@@ -363,9 +475,15 @@ func ValidateBoard(board cue.Value) []string {
 	// Additional Go validation: parameterized tags must have values
 	errs = append(errs, validateParameterizedTags(board)...)
 
+	// Additional Go validation: events supply the value of their parameterized tags
+	errs = append(errs, validateEventTagFields(board)...)
+
 	// Additional Go validation: dotted paths in mapping/computed must resolve
 	errs = append(errs, validateDottedPaths(board)...)
 
+	// Additional Go validation: views query events emitted by earlier slices
+	errs = append(errs, validateViewEventOrdering(board)...)
+
 	// Additional Go validation: dependent query constraints
 	errs = append(errs, validateDependentQueries(board)...)
 
@@ -373,12 +491,53 @@ func ValidateBoard(board cue.Value) []string {
 	// source field types — catches union-type narrowing that CUE's & operator allows.
 	errs = append(errs, validateCommandFieldTypeSubsumption(board)...)
 
+	// Additional Go validation: scenario when keys must be command fields
+	errs = append(errs, validateScenarioWhen(board)...)
+
+	// Additional Go validation: scenario then events must fit the event fields
+	errs = append(errs, validateScenarioThenValues(board)...)
+
+	// Additional Go validation: computed command fields must be genuinely computed
+	// (not trigger fields) with a concrete type, and should say what they are
+	errs = append(errs, validateCommandComputed(board)...)
+
+	// Additional Go validation: read model field types must be concrete
+	errs = append(errs, validateReadModelFieldTypes(board)...)
+
+	// Additional Go validation: inline event literals must agree on their fields
+	errs = append(errs, validateEventShapes(board)...)
+
+	// Additional Go validation: read model references must resolve
+	errs = append(errs, validateReadModelReferences(board)...)
+
+	// Additional Go validation: endpoint paths must be well-formed
+	errs = append(errs, validateEndpointPaths(board)...)
+
+	// Additional Go validation: each endpoint route is served by one slice
+	errs = append(errs, validateEndpointRoutes(board)...)
+
+	// Additional Go validation: path params should be used by the slice
+	errs = append(errs, validateUnusedPathParams(board)...)
+
+	// Additional Go validation: slice names identify slices (files, story refs)
+	errs = append(errs, validateUniqueSliceNames(board)...)
+
+	// Additional Go validation: queried events should be emitted somewhere
+	errs = append(errs, validateOrphanQueriedEvents(board)...)
+
+	// Additional Go validation: declared events should be used
+	errs = append(errs, validateUnusedEvents(board)...)
+
+	// Additional Go validation: declared tags should be used
+	errs = append(errs, validateUnusedTags(board)...)
+
 	return errs
 }
 
-// validateActors checks that each slice has an actor and it's defined in board.actors
-func validateActors(board cue.Value) []string {
-	var errs []string
+// validateActors checks that each slice has an actor and it's defined in board.actors.
+// Story steps may name an actor too; when they do it must be defined as well.
+func validateActors(board cue.Value) []ValidationError {
+	var errs []ValidationError
 
 	// Build list of defined actors
 	actorNames := make(map[string]bool)
@@ -398,6 +557,12 @@ func validateActors(board cue.Value) []string {
 	for flowIter.Next() {
 		inst := flowIter.Value()
 		kind := getString(inst, "kind")
+		if kind == "story" {
+			if actorName := getString(inst, "actor.name"); actorName != "" && !actorNames[actorName] {
+				errs = append(errs, ValidationError{Code: ErrActorUndefined, Message: fmt.Sprintf("story %q actor %q not defined in board.actors", getString(inst, "name"), actorName)})
+			}
+			continue
+		}
 		if kind != "slice" {
 			continue
 		}
@@ -412,22 +577,26 @@ func validateActors(board cue.Value) []string {
 		actorVal := inst.LookupPath(cue.ParsePath("actor"))
 
 		if !actorVal.Exists() || actorVal.Err() != nil {
-			errs = append(errs, fmtErr(ErrActorMissing, fmt.Sprintf("slice %q must have an actor", sliceName), ""))
+			errs = append(errs, ValidationError{Code: ErrActorMissing, Message: fmt.Sprintf("slice %q must have an actor", sliceName), Slice: sliceName})
 			continue
 		}
 
 		actorName := getString(actorVal, "name")
 		if actorName == "" || !actorNames[actorName] {
-			errs = append(errs, fmtErr(ErrActorUndefined, fmt.Sprintf("slice %q actor %q not defined in board.actors", sliceName, actorName), ""))
+			errs = append(errs, ValidationError{Code: ErrActorUndefined, Message: fmt.Sprintf("slice %q actor %q not defined in board.actors", sliceName, actorName), Slice: sliceName})
 		}
 	}
 
 	return errs
 }
 
+// DevStatuses are the delivery statuses a slice's devstatus may take, in
+// progress order (em #DevStatus).
+var DevStatuses = []string{"specifying", "todo", "doing", "done"}
+
 // validateDottedPaths checks that dotted paths in mapping/computed resolve to actual fields
-func validateDottedPaths(board cue.Value) []string {
-	var errs []string
+func validateDottedPaths(board cue.Value) []ValidationError {
+	var errs []ValidationError
 
 	eventsVal := board.LookupPath(cue.ParsePath("events"))
 	flowVal := board.LookupPath(cue.ParsePath("flow"))
@@ -462,7 +631,7 @@ func validateDottedPaths(board cue.Value) []string {
 
 				fieldType, ok := resolveDottedPathType(fieldsVal, pathKey)
 				if !ok {
-					errs = append(errs, fmtErr(ErrDottedPath, fmt.Sprintf("view %q mapping %q: path must resolve to a field in readModel", sliceName, pathKey), ""))
+					errs = append(errs, ValidationError{Code: ErrDottedPath, Message: fmt.Sprintf("view %q mapping %q: path must resolve to a field in readModel", sliceName, pathKey), Slice: sliceName})
 					continue
 				}
 
@@ -474,7 +643,7 @@ func validateDottedPaths(board cue.Value) []string {
 				if eventFieldType.Exists() && eventFieldType.Err() == nil {
 					unified := fieldType.Unify(eventFieldType)
 					if unified.Err() != nil {
-						errs = append(errs, fmtErr(ErrDottedType, fmt.Sprintf("view %q mapping %q: must match source event field type", sliceName, pathKey), ""))
+						errs = append(errs, ValidationError{Code: ErrDottedType, Message: fmt.Sprintf("view %q mapping %q: must match source event field type", sliceName, pathKey), Slice: sliceName})
 					}
 				}
 			}
@@ -490,8 +659,57 @@ func validateDottedPaths(board cue.Value) []string {
 				}
 				_, ok := resolveDottedPathType(fieldsVal, pathKey)
 				if !ok {
-					errs = append(errs, fmtErr(ErrDottedPath, fmt.Sprintf("view %q computed %q: path must resolve to a field in readModel", sliceName, pathKey), ""))
+					errs = append(errs, ValidationError{Code: ErrDottedPath, Message: fmt.Sprintf("view %q computed %q: path must resolve to a field in readModel", sliceName, pathKey), Slice: sliceName})
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateViewEventOrdering checks that each event a view queries is emitted
+// by a change or automation slice earlier in the flow: a read model can only
+// project events that have happened. Events no slice emits are left to
+// validateOrphanQueriedEvents.
+func validateViewEventOrdering(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	flowIter, err := board.LookupPath(cue.ParsePath("flow")).List()
+	if err != nil {
+		return errs
+	}
+
+	var insts []cue.Value
+	firstEmitter := make(map[string]int) // event type -> index in insts
+	for flowIter.Next() {
+		inst := flowIter.Value()
+		if getString(inst, "kind") != "slice" {
+			continue
+		}
+		if getString(inst, "type") != "view" {
+			for _, t := range listEventTypes(inst, "emits") {
+				if _, ok := firstEmitter[t]; !ok {
+					firstEmitter[t] = len(insts)
+				}
+			}
+		}
+		insts = append(insts, inst)
+	}
+
+	for i, inst := range insts {
+		if getString(inst, "type") != "view" {
+			continue
+		}
+		reported := make(map[string]bool)
+		for _, item := range queryItems(inst) {
+			for _, eventType := range listEventTypes(item, "types") {
+				emitter, ok := firstEmitter[eventType]
+				if !ok || emitter < i || reported[eventType] {
+					continue
 				}
+				reported[eventType] = true
+				errs = append(errs, ValidationError{Code: ErrEventOrdering, Message: fmt.Sprintf("view %q queries event %q, first emitted by slice %q after it in the flow", getString(inst, "name"), eventType, getString(insts[emitter], "name")), Slice: getString(inst, "name")})
 			}
 		}
 	}
@@ -538,8 +756,8 @@ func resolveDottedPathType(fields cue.Value, path string) (cue.Value, bool) {
 }
 
 // validateParameterizedTags checks that parameterized tags have values in queries
-func validateParameterizedTags(board cue.Value) []string {
-	var errs []string
+func validateParameterizedTags(board cue.Value) []ValidationError {
+	var errs []ValidationError
 
 	paramTags := make(map[string]bool)
 	tagsVal := board.LookupPath(cue.ParsePath("tags"))
@@ -588,7 +806,7 @@ func validateParameterizedTags(board cue.Value) []string {
 						if paramTags[tagName] {
 							valueVal := tagRef.LookupPath(cue.ParsePath("value"))
 							if !valueVal.Exists() || valueVal.Err() != nil {
-								errs = append(errs, fmtErr(ErrTagRequiresValue, fmt.Sprintf("slice %q query: tag %q must have a value (parameterized tag)", sliceName, tagName), ""))
+								errs = append(errs, ValidationError{Code: ErrTagRequiresValue, Message: fmt.Sprintf("slice %q query: tag %q must have a value (parameterized tag)", sliceName, tagName), Slice: sliceName})
 							}
 						}
 					}
@@ -605,8 +823,10 @@ func validateParameterizedTags(board cue.Value) []string {
 // 2. extract.field must exist in that event
 // 3. TagRef cannot have both fromExtract and value
 // 4. primary query cannot use fromExtract
-func validateDependentQueries(board cue.Value) []string {
-	var errs []string
+// 5. a fromExtract tag must be carried by at least one of the item's event types
+func validateDependentQueries(board cue.Value) []ValidationError {
+	var errs []ValidationError
+	var edges []extractEdge
 
 	eventsVal := board.LookupPath(cue.ParsePath("events"))
 	flowVal := board.LookupPath(cue.ParsePath("flow"))
@@ -648,7 +868,7 @@ func validateDependentQueries(board cue.Value) []string {
 						tagRef := tIter.Value()
 						fromExtract := tagRef.LookupPath(cue.ParsePath("fromExtract"))
 						if fromExtract.Exists() && fromExtract.Err() == nil {
-							errs = append(errs, fmtErr(ErrDepFromExtractInPrimary, fmt.Sprintf("slice %q: fromExtract only allowed in dependentQuery, not primary query", sliceName), ""))
+							errs = append(errs, ValidationError{Code: ErrDepFromExtractInPrimary, Message: fmt.Sprintf("slice %q: fromExtract only allowed in dependentQuery, not primary query", sliceName), Slice: sliceName})
 						}
 					}
 				}
@@ -677,6 +897,7 @@ func validateDependentQueries(board cue.Value) []string {
 		}
 
 		// Validate extract: event in primary, field exists
+		extractEvents := make(map[string]string)
 		extractVal := depQueryVal.LookupPath(cue.ParsePath("extract"))
 		if iter, err := extractVal.Fields(); err == nil {
 			for iter.Next() {
@@ -685,26 +906,44 @@ func validateDependentQueries(board cue.Value) []string {
 
 				evtType := getString(ext, "event.eventType")
 				fieldName := getString(ext, "field")
+				extractEvents[extractName] = evtType
 
 				// Check event is in primary query
 				if !primaryEventTypes[evtType] {
-					errs = append(errs, fmtErr(ErrDepExtractEventNotInQuery, fmt.Sprintf("slice %q dependentQuery.extract.%s: event %q must be in primary query", sliceName, extractName, evtType), ""))
+					errs = append(errs, ValidationError{Code: ErrDepExtractEventNotInQuery, Message: fmt.Sprintf("slice %q dependentQuery.extract.%s: event %q must be in primary query", sliceName, extractName, evtType), Slice: sliceName})
 				}
 
 				// Check field exists in event
 				eventFieldsVal := eventsVal.LookupPath(cue.ParsePath(evtType + ".fields"))
 				fieldVal := eventFieldsVal.LookupPath(cue.ParsePath(fieldName))
 				if !fieldVal.Exists() || fieldVal.Err() != nil {
-					errs = append(errs, fmtErr(ErrDepExtractFieldNotInEvent, fmt.Sprintf("slice %q dependentQuery.extract.%s: field %q not in event %q", sliceName, extractName, fieldName, evtType), ""))
+					errs = append(errs, ValidationError{Code: ErrDepExtractFieldNotInEvent, Message: fmt.Sprintf("slice %q dependentQuery.extract.%s: field %q not in event %q", sliceName, extractName, fieldName, evtType), Slice: sliceName, Field: fieldName})
 				}
 			}
 		}
 
-		// Validate dependent query items: tags cannot have both value and fromExtract
+		// Validate dependent query items: tags cannot have both value and fromExtract,
+		// and fromExtract tags must be carried by the item's events
 		depItemsVal := depQueryVal.LookupPath(cue.ParsePath("items"))
 		if dIter, err := depItemsVal.List(); err == nil {
 			for dIter.Next() {
 				item := dIter.Value()
+
+				// Tags carried by any of this item's event types
+				var itemTypes []string
+				itemTags := make(map[string]bool)
+				if tIter, err := item.LookupPath(cue.ParsePath("types")).List(); err == nil {
+					for tIter.Next() {
+						evtType := getString(tIter.Value(), "eventType")
+						itemTypes = append(itemTypes, evtType)
+						if etIter, err := eventsVal.LookupPath(cue.ParsePath(evtType + ".tags")).List(); err == nil {
+							for etIter.Next() {
+								itemTags[getString(etIter.Value(), "name")] = true
+							}
+						}
+					}
+				}
+
 				tagsVal := item.LookupPath(cue.ParsePath("tags"))
 				if tIter, err := tagsVal.List(); err == nil {
 					for tIter.Next() {
@@ -717,7 +956,21 @@ func validateDependentQueries(board cue.Value) []string {
 
 						if hasValue && hasFromExtract {
 							tagName := getString(tagRef, "tag.name")
-							errs = append(errs, fmtErr(ErrDepFromExtractAndValue, fmt.Sprintf("slice %q dependentQuery: tag %q cannot have both value and fromExtract", sliceName, tagName), ""))
+							errs = append(errs, ValidationError{Code: ErrDepFromExtractAndValue, Message: fmt.Sprintf("slice %q dependentQuery: tag %q cannot have both value and fromExtract", sliceName, tagName), Slice: sliceName})
+						}
+
+						if hasFromExtract && len(itemTypes) > 0 {
+							tagName := getString(tagRef, "tag.name")
+							if !itemTags[tagName] {
+								errs = append(errs, ValidationError{Code: ErrDepFromExtractTagNotOnEvent, Message: fmt.Sprintf("slice %q dependentQuery: fromExtract tag %q is not carried by any of %s", sliceName, tagName, strings.Join(itemTypes, ", ")), Slice: sliceName})
+							}
+						}
+
+						if hasFromExtract {
+							fromExtract, _ := fromExtractVal.String()
+							for _, to := range itemTypes {
+								edges = append(edges, extractEdge{from: extractEvents[fromExtract], to: to, slice: sliceName})
+							}
 						}
 					}
 				}
@@ -725,9 +978,118 @@ func validateDependentQueries(board cue.Value) []string {
 		}
 	}
 
+	errs = append(errs, extractCycles(edges)...)
+
+	return errs
+}
+
+// extractEdge links the event a dependent query extracts from to an event
+// the extracted value is used to load.
+type extractEdge struct {
+	from, to string
+	slice    string
+}
+
+// extractCycles reports each cycle in the extraction graph of all slices'
+// dependent queries: events whose lookup transitively depends on themselves.
+// A slice's dependent query runs once, after its primary query, so edges of
+// a single slice never loop on their own: a cycle must span several slices.
+func extractCycles(edges []extractEdge) []ValidationError {
+	var errs []ValidationError
+
+	adj := make(map[string][]extractEdge)
+	var nodes []string
+	for _, e := range edges {
+		if e.from == "" {
+			continue // unknown extract name, reported by CUE (E317)
+		}
+		if e.from == e.to {
+			continue // loads more events of the type it extracts from
+		}
+		if _, ok := adj[e.from]; !ok {
+			nodes = append(nodes, e.from)
+		}
+		adj[e.from] = append(adj[e.from], e)
+	}
+
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+	state := make(map[string]int)
+	seen := make(map[string]bool)
+	var stack []extractEdge
+
+	var visit func(node string)
+	visit = func(node string) {
+		state[node] = onStack
+		for _, e := range adj[node] {
+			switch state[e.to] {
+			case unvisited:
+				stack = append(stack, e)
+				visit(e.to)
+				stack = stack[:len(stack)-1]
+			case onStack:
+				// Back edge: the cycle is the stack suffix leaving e.to
+				start := len(stack)
+				for start > 0 && stack[start-1].from != e.to {
+					start--
+				}
+				if start > 0 {
+					start--
+				}
+				cycle := append(slices.Clone(stack[start:]), e)
+				if !spansSlices(cycle) {
+					continue
+				}
+				if key := extractCycleKey(cycle); !seen[key] {
+					seen[key] = true
+					errs = append(errs, ValidationError{Code: ErrDepExtractCycle, Message: "dependent queries form an extraction cycle: " + formatExtractCycle(cycle)})
+				}
+			}
+		}
+		state[node] = done
+	}
+	for _, n := range nodes {
+		if state[n] == unvisited {
+			visit(n)
+		}
+	}
+
 	return errs
 }
 
+// spansSlices reports whether the edges of a cycle come from several slices.
+func spansSlices(cycle []extractEdge) bool {
+	for _, e := range cycle[1:] {
+		if e.slice != cycle[0].slice {
+			return true
+		}
+	}
+	return false
+}
+
+// extractCycleKey identifies a cycle regardless of its starting event.
+func extractCycleKey(cycle []extractEdge) string {
+	var parts []string
+	for _, e := range cycle {
+		parts = append(parts, e.from+">"+e.to+"@"+e.slice)
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, ",")
+}
+
+// formatExtractCycle renders a cycle as `A -(slice "S")-> B -(slice "T")-> A`.
+func formatExtractCycle(cycle []extractEdge) string {
+	var sb strings.Builder
+	sb.WriteString(cycle[0].from)
+	for _, e := range cycle {
+		fmt.Fprintf(&sb, " -(slice %q)-> %s", e.slice, e.to)
+	}
+	return sb.String()
+}
+
 // validateCommandFieldTypeSubsumption checks that command field types are at least as broad
 // as the source (endpoint/event) field types. This catches the case where an endpoint
 // declares a union type (e.g. int | string) but the command only handles a subset (e.g. string).
@@ -737,8 +1099,8 @@ func validateDependentQueries(board cue.Value) []string {
 // This function only fires when the types ARE compatible (non-empty intersection) but the
 // command type is narrower than the source type. Truly incompatible types are already caught
 // by the CUE-side validation in board.cue.
-func validateCommandFieldTypeSubsumption(board cue.Value) []string {
-	var errs []string
+func validateCommandFieldTypeSubsumption(board cue.Value) []ValidationError {
+	var errs []ValidationError
 
 	flowVal := board.LookupPath(cue.ParsePath("flow"))
 	flowIter, err := flowVal.List()
@@ -756,7 +1118,6 @@ func validateCommandFieldTypeSubsumption(board cue.Value) []string {
 		}
 
 		sliceName := getString(inst, "name")
-		triggerKind := getString(inst, "trigger.kind")
 
 		// Build sets of fields excluded from direct type checking
 		computed := make(map[string]bool)
@@ -773,19 +1134,8 @@ func validateCommandFieldTypeSubsumption(board cue.Value) []string {
 		}
 
 		// Collect source field structs in priority order
-		var srcVals []cue.Value
-		switch triggerKind {
-		case "endpoint":
-			srcVals = []cue.Value{
-				inst.LookupPath(cue.ParsePath("trigger.endpoint.params")),
-				inst.LookupPath(cue.ParsePath("trigger.endpoint.body")),
-				inst.LookupPath(cue.ParsePath("trigger.endpoint.auth")),
-			}
-		case "externalEvent":
-			srcVals = []cue.Value{inst.LookupPath(cue.ParsePath("trigger.externalEvent.fields"))}
-		case "internalEvent":
-			srcVals = []cue.Value{inst.LookupPath(cue.ParsePath("trigger.internalEvent.fields"))}
-		default:
+		srcVals := triggerFieldSources(inst)
+		if srcVals == nil {
 			continue
 		}
 
@@ -817,9 +1167,9 @@ func validateCommandFieldTypeSubsumption(board cue.Value) []string {
 				// Check subsumption: commandFieldType must subsume srcFieldType, meaning
 				// the command must accept every value the source can provide.
 				if err := commandFieldType.Subsume(srcFieldType, cue.Raw()); err != nil {
-					errs = append(errs, fmtErr(ErrCmdFieldType, fmt.Sprintf(
+					errs = append(errs, ValidationError{Code: ErrCmdFieldType, Message: fmt.Sprintf(
 						"slice %q command: field %q type too narrow — source provides wider union type than command declares",
-						sliceName, fieldName), ""))
+						sliceName, fieldName), Slice: sliceName, Field: fieldName})
 				}
 				break
 			}
@@ -828,3 +1178,964 @@ func validateCommandFieldTypeSubsumption(board cue.Value) []string {
 
 	return errs
 }
+
+// validateEventTagFields checks that each event of board.events carrying a
+// parameterized tag has a field named after the tag's param, of a type
+// compatible with the tag's: queries bind the tag value from that field.
+func validateEventTagFields(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	iter, err := board.LookupPath(cue.ParsePath("events")).Fields()
+	if err != nil {
+		return errs
+	}
+	for iter.Next() {
+		evt := iter.Value()
+		eventType := iter.Selector().Unquoted()
+		tagIter, err := evt.LookupPath(cue.ParsePath("tags")).List()
+		if err != nil {
+			continue
+		}
+		for tagIter.Next() {
+			tag := tagIter.Value()
+			param := getString(tag, "param")
+			if param == "" {
+				continue
+			}
+			tagName := getString(tag, "name")
+			field := evt.LookupPath(cue.MakePath(cue.Str("fields"), cue.Str(param)))
+			if !field.Exists() {
+				errs = append(errs, ValidationError{Code: ErrEventTagField, Message: fmt.Sprintf("event %q carries tag %q but has no field %q to supply its value", eventType, tagName, param), Field: param})
+				continue
+			}
+			tagType := tag.LookupPath(cue.ParsePath("type"))
+			if !tagType.Exists() {
+				continue
+			}
+			if err := field.Unify(tagType).Validate(); err != nil {
+				errs = append(errs, ValidationError{Code: ErrEventTagField, Message: fmt.Sprintf("event %q field %q is %v, but tag %q has type %v", eventType, param, field, tagName, tagType), Field: param})
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateEventShapes checks that every occurrence of an event type (board.events,
+// slice emits and query items) declares the same field set. References to
+// events.X always agree; only divergent inline literals are reported.
+func validateEventShapes(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	type declared struct {
+		shape string
+		where string
+	}
+	shapes := make(map[string]declared)
+	reported := make(map[string]bool)
+
+	check := func(evt cue.Value, where string) {
+		eventType := getString(evt, "eventType")
+		if eventType == "" {
+			return
+		}
+		shape := eventShape(evt.LookupPath(cue.ParsePath("fields")))
+		first, ok := shapes[eventType]
+		if !ok {
+			shapes[eventType] = declared{shape, where}
+			return
+		}
+		if first.shape == shape || reported[eventType+"|"+where] {
+			return
+		}
+		reported[eventType+"|"+where] = true
+		errs = append(errs, ValidationError{Code: ErrEventShapeConflict, Message: fmt.Sprintf("event %q in %s declares fields {%s}, but %s declares {%s}", eventType, where, shape, first.where, first.shape)})
+	}
+
+	// Board-level definitions come first so they are the reference shape
+	if iter, err := board.LookupPath(cue.ParsePath("events")).Fields(); err == nil {
+		for iter.Next() {
+			check(iter.Value(), "board events")
+		}
+	}
+
+	flowIter, err := board.LookupPath(cue.ParsePath("flow")).List()
+	if err != nil {
+		return errs
+	}
+	for flowIter.Next() {
+		inst := flowIter.Value()
+		if getString(inst, "kind") != "slice" {
+			continue
+		}
+		where := fmt.Sprintf("slice %q", getString(inst, "name"))
+
+		if iter, err := inst.LookupPath(cue.ParsePath("emits")).List(); err == nil {
+			for iter.Next() {
+				check(iter.Value(), where)
+			}
+		}
+		for _, path := range []string{"query.items", "command.query.items"} {
+			items, err := inst.LookupPath(cue.ParsePath(path)).List()
+			if err != nil {
+				continue
+			}
+			for items.Next() {
+				if types, err := items.Value().LookupPath(cue.ParsePath("types")).List(); err == nil {
+					for types.Next() {
+						check(types.Value(), where)
+					}
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// ValidateSharedEvents reports events defined both by the board and by a shared
+// events file with a different field shape. The board's definition is kept.
+func ValidateSharedEvents(local, shared cue.Value) []ValidationError {
+	var errs []ValidationError
+	iter, err := shared.Fields()
+	if err != nil {
+		return errs
+	}
+	for iter.Next() {
+		name := iter.Selector().Unquoted()
+		localEvt := local.LookupPath(cue.MakePath(iter.Selector()))
+		if !localEvt.Exists() {
+			continue
+		}
+		localShape := eventShape(localEvt.LookupPath(cue.ParsePath("fields")))
+		sharedShape := eventShape(iter.Value().LookupPath(cue.ParsePath("fields")))
+		if localShape != sharedShape {
+			errs = append(errs, ValidationError{Code: ErrSharedEventConflict, Message: fmt.Sprintf("event %q declares fields {%s}, but the shared events file declares {%s}", name, localShape, sharedShape)})
+		}
+	}
+	return errs
+}
+
+// eventShape returns a canonical "name: kind" listing of event fields, sorted by name.
+func eventShape(fields cue.Value) string {
+	var parts []string
+	if iter, err := fields.Fields(cue.Optional(true)); err == nil {
+		for iter.Next() {
+			parts = append(parts, fmt.Sprintf("%s: %s", iter.Selector().Unquoted(), iter.Value().IncompleteKind()))
+		}
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, ", ")
+}
+
+// ValidateScenarioConsistency flags scenarios whose given events can never be
+// returned by the slice's query: the event's tag field holds a concrete value
+// that contradicts the value the query binds the tag to (either a literal in
+// the query or the scenario's when/query input). It is opt-in since it reasons
+// about example values rather than structure.
+func ValidateScenarioConsistency(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	type tagBinding struct {
+		tag   string
+		param string
+		value cue.Value
+	}
+	type queryItem struct {
+		types    map[string]bool
+		bindings []tagBinding
+	}
+
+	flowIter, err := board.LookupPath(cue.ParsePath("flow")).List()
+	if err != nil {
+		return errs
+	}
+
+	for flowIter.Next() {
+		inst := flowIter.Value()
+		if getString(inst, "kind") != "slice" {
+			continue
+		}
+		sliceName := getString(inst, "name")
+
+		// Views bind tags from scenario.query, commands from scenario.when
+		var queryPath, inputPath string
+		switch getString(inst, "type") {
+		case "change", "automation":
+			queryPath, inputPath = "command.query.items", "when"
+		case "view":
+			queryPath, inputPath = "query.items", "query"
+		default:
+			continue
+		}
+
+		var items []queryItem
+		if iter, err := inst.LookupPath(cue.ParsePath(queryPath)).List(); err == nil {
+			for iter.Next() {
+				item := queryItem{types: make(map[string]bool)}
+				if tIter, err := iter.Value().LookupPath(cue.ParsePath("types")).List(); err == nil {
+					for tIter.Next() {
+						item.types[getString(tIter.Value(), "eventType")] = true
+					}
+				}
+				if tIter, err := iter.Value().LookupPath(cue.ParsePath("tags")).List(); err == nil {
+					for tIter.Next() {
+						tagRef := tIter.Value()
+						param := getString(tagRef, "tag.param")
+						if param == "" {
+							continue
+						}
+						item.bindings = append(item.bindings, tagBinding{
+							tag:   getString(tagRef, "tag.name"),
+							param: param,
+							value: tagRef.LookupPath(cue.ParsePath("value")),
+						})
+					}
+				}
+				items = append(items, item)
+			}
+		}
+
+		scenIter, err := inst.LookupPath(cue.ParsePath("scenarios")).List()
+		if err != nil {
+			continue
+		}
+		for scenIter.Next() {
+			scenario := scenIter.Value()
+			scenarioName := getString(scenario, "name")
+			input := scenario.LookupPath(cue.ParsePath(inputPath))
+
+			givenIter, err := scenario.LookupPath(cue.ParsePath("given")).List()
+			if err != nil {
+				continue
+			}
+			for givenIter.Next() {
+				given := givenIter.Value()
+				eventType := getString(given, "eventType")
+
+				// The event is possible if at least one query item admits it
+				queried, admitted := false, false
+				var conflict string
+				for _, item := range items {
+					if !item.types[eventType] {
+						continue
+					}
+					queried = true
+					ok := true
+					for _, b := range item.bindings {
+						bound := b.value
+						if !isConcrete(bound) {
+							bound = input.LookupPath(cue.ParsePath(b.param))
+						}
+						got := given.LookupPath(cue.ParsePath("fields." + b.param))
+						if !isConcrete(bound) || !isConcrete(got) || got.Equals(bound) {
+							continue
+						}
+						ok = false
+						conflict = fmt.Sprintf("%s=%v but query binds tag %q to %v", b.param, got, b.tag, bound)
+						break
+					}
+					if ok {
+						admitted = true
+						break
+					}
+				}
+
+				if queried && !admitted {
+					errs = append(errs, ValidationError{Code: ErrScenarioTagConflict, Message: fmt.Sprintf("slice %q scenario %q: given %s has %s", sliceName, scenarioName, eventType, conflict), Slice: sliceName})
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// isConcrete reports whether v exists and holds a concrete value.
+func isConcrete(v cue.Value) bool {
+	return v.Exists() && v.Err() == nil && v.IsConcrete()
+}
+
+// validateReadModelFieldTypes checks that every read model field resolves to a
+// scalar, a list of such, or a closed struct. Open structs ({...}) and top (_)
+// leave the projection shape undefined.
+func validateReadModelFieldTypes(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	flowIter, err := board.LookupPath(cue.ParsePath("flow")).List()
+	if err != nil {
+		return errs
+	}
+	for flowIter.Next() {
+		inst := flowIter.Value()
+		if getString(inst, "kind") != "slice" || getString(inst, "type") != "view" {
+			continue
+		}
+		viewName := getString(inst, "name")
+
+		for _, schema := range []string{"readModel.fields", "readModel.columns"} {
+			iter, err := inst.LookupPath(cue.ParsePath(schema)).Fields(cue.Optional(true))
+			if err != nil {
+				continue
+			}
+			for iter.Next() {
+				for _, path := range openFieldTypes(iter.Value(), iter.Selector().Unquoted()) {
+					errs = append(errs, ValidationError{Code: ErrReadModelOpen, Message: fmt.Sprintf("view %q read model field %q has no concrete type", viewName, path), Slice: viewName, Field: path})
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// openFieldTypes returns the paths under v (named path) whose type is open.
+func openFieldTypes(v cue.Value, path string) []string {
+	if v.Err() != nil || v.IncompleteKind() == cue.TopKind {
+		return []string{path}
+	}
+
+	switch v.IncompleteKind() {
+	case cue.StructKind:
+		if v.Allows(cue.AnyString) {
+			return []string{path}
+		}
+		var open []string
+		if iter, err := v.Fields(cue.Optional(true)); err == nil {
+			for iter.Next() {
+				open = append(open, openFieldTypes(iter.Value(), path+"."+iter.Selector().Unquoted())...)
+			}
+		}
+		return open
+	case cue.ListKind:
+		if elem := v.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+			return openFieldTypes(elem, path+"[]")
+		}
+	}
+	return nil
+}
+
+// validateScenarioWhen checks that each change and automation scenario's when
+// keys are command fields, so scenarios don't drift from the command after a
+// refactor. Their types are checked by the schema (when: command.fields),
+// whose command fields are open to extra keys.
+func validateScenarioWhen(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	flowIter, err := board.LookupPath(cue.ParsePath("flow")).List()
+	if err != nil {
+		return errs
+	}
+	for flowIter.Next() {
+		inst := flowIter.Value()
+		if getString(inst, "kind") != "slice" || getString(inst, "type") == "view" {
+			continue
+		}
+		sliceName := getString(inst, "name")
+		fields := inst.LookupPath(cue.ParsePath("command.fields"))
+		scIter, err := inst.LookupPath(cue.ParsePath("scenarios")).List()
+		if err != nil {
+			continue
+		}
+		for si := 0; scIter.Next(); si++ {
+			iter, err := scIter.Value().LookupPath(cue.ParsePath("when")).Fields()
+			if err != nil {
+				continue
+			}
+			for iter.Next() {
+				name := iter.Selector().Unquoted()
+				if !fields.LookupPath(cue.MakePath(cue.Str(name))).Exists() {
+					errs = append(errs, ValidationError{Code: ErrScenarioWhen, Message: fmt.Sprintf("slice %q scenario %d when: %q is not a command field", sliceName, si, name), Slice: sliceName, Field: name})
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateScenarioThenValues checks that the field values of each success
+// scenario's then events unify with the field types declared in board.events.
+func validateScenarioThenValues(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	events := board.LookupPath(cue.ParsePath("events"))
+	flowIter, err := board.LookupPath(cue.ParsePath("flow")).List()
+	if err != nil {
+		return errs
+	}
+	for flowIter.Next() {
+		inst := flowIter.Value()
+		if getString(inst, "kind") != "slice" || getString(inst, "type") == "view" {
+			continue
+		}
+		sliceName := getString(inst, "name")
+		scIter, err := inst.LookupPath(cue.ParsePath("scenarios")).List()
+		if err != nil {
+			continue
+		}
+		for si := 0; scIter.Next(); si++ {
+			evtIter, err := scIter.Value().LookupPath(cue.ParsePath("then.events")).List()
+			if err != nil {
+				continue
+			}
+			for evtIter.Next() {
+				eventType := getString(evtIter.Value(), "eventType")
+				declared := events.LookupPath(cue.MakePath(cue.Str(eventType), cue.Str("fields")))
+				if !declared.Exists() {
+					continue
+				}
+				iter, err := evtIter.Value().LookupPath(cue.ParsePath("fields")).Fields()
+				if err != nil {
+					continue
+				}
+				for iter.Next() {
+					name := iter.Selector().Unquoted()
+					fieldType := declared.LookupPath(cue.MakePath(cue.Str(name)))
+					if !fieldType.Exists() {
+						continue
+					}
+					if err := fieldType.Unify(iter.Value()).Validate(); err != nil {
+						errs = append(errs, ValidationError{Code: ErrScenarioThenType, Message: fmt.Sprintf("slice %q scenario %d then: event %q field %q must be %v, got %v", sliceName, si, eventType, name, fieldType, iter.Value()), Slice: sliceName, Field: name})
+					}
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateCommandComputed checks computed command fields: a computed field
+// must not shadow a field the trigger provides (it would be silently
+// excluded from the source checks) and must be a command field of concrete
+// type. It also warns about computed fields without a description, which
+// leaves their derivation undocumented.
+func validateCommandComputed(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	flowIter, err := board.LookupPath(cue.ParsePath("flow")).List()
+	if err != nil {
+		return errs
+	}
+	for flowIter.Next() {
+		inst := flowIter.Value()
+		if getString(inst, "kind") != "slice" {
+			continue
+		}
+		sliceName := getString(inst, "name")
+		iter, err := inst.LookupPath(cue.ParsePath("command.computed")).Fields()
+		if err != nil {
+			continue
+		}
+		fields := inst.LookupPath(cue.ParsePath("command.fields"))
+		sources := triggerFieldSources(inst)
+		for iter.Next() {
+			name := iter.Selector().Unquoted()
+			for _, src := range sources {
+				if f := src.LookupPath(cue.MakePath(cue.Str(name))); f.Exists() && f.Err() == nil {
+					errs = append(errs, ValidationError{Code: ErrCmdComputed, Message: fmt.Sprintf("slice %q command: computed field %q shadows a %s trigger field", sliceName, name, getString(inst, "trigger.kind")), Slice: sliceName, Field: name})
+					break
+				}
+			}
+			if typ := fields.LookupPath(cue.MakePath(cue.Str(name))); !typ.Exists() {
+				errs = append(errs, ValidationError{Code: ErrCmdComputed, Message: fmt.Sprintf("slice %q command: computed field %q is not a command field", sliceName, name), Slice: sliceName, Field: name})
+			} else {
+				for _, path := range openFieldTypes(typ, name) {
+					errs = append(errs, ValidationError{Code: ErrCmdComputed, Message: fmt.Sprintf("slice %q command: computed field %q has no concrete type", sliceName, path), Slice: sliceName, Field: path})
+				}
+			}
+
+			desc, err := iter.Value().String()
+			if err != nil {
+				desc = getString(iter.Value(), "description")
+			}
+			if strings.TrimSpace(desc) == "" {
+				errs = append(errs, ValidationError{Code: ErrCmdComputedDesc, Message: fmt.Sprintf("slice %q command: computed field %q has no description", sliceName, name), Slice: sliceName, Field: name})
+			}
+		}
+	}
+
+	return errs
+}
+
+// triggerFieldSources returns the field structs a slice's trigger provides to
+// its command, in priority order, or nil for an unknown trigger kind.
+func triggerFieldSources(inst cue.Value) []cue.Value {
+	switch getString(inst, "trigger.kind") {
+	case "endpoint":
+		return []cue.Value{
+			inst.LookupPath(cue.ParsePath("trigger.endpoint.params")),
+			inst.LookupPath(cue.ParsePath("trigger.endpoint.body")),
+			inst.LookupPath(cue.ParsePath("trigger.endpoint.auth")),
+		}
+	case "externalEvent":
+		return []cue.Value{inst.LookupPath(cue.ParsePath("trigger.externalEvent.fields"))}
+	case "internalEvent":
+		return []cue.Value{inst.LookupPath(cue.ParsePath("trigger.internalEvent.fields"))}
+	case "ui":
+		return []cue.Value{inst.LookupPath(cue.ParsePath("trigger.ui.fields"))}
+	}
+	return nil
+}
+
+// sliceEndpoint returns the endpoint of a view, or the endpoint trigger of a
+// change or automation slice (which doesn't exist for other trigger kinds).
+func sliceEndpoint(inst cue.Value) cue.Value {
+	if getString(inst, "type") == "view" {
+		return inst.LookupPath(cue.ParsePath("endpoint"))
+	}
+	return inst.LookupPath(cue.ParsePath("trigger.endpoint"))
+}
+
+// httpVerbs are the endpoint verbs #HTTPVerb accepts.
+var httpVerbs = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
+// validateEndpointPaths checks that endpoint paths start with a slash and
+// that their {param} placeholders are balanced, not nested and not empty.
+func validateEndpointPaths(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	flowIter, err := board.LookupPath(cue.ParsePath("flow")).List()
+	if err != nil {
+		return errs
+	}
+	for flowIter.Next() {
+		inst := flowIter.Value()
+		if getString(inst, "kind") != "slice" {
+			continue
+		}
+		path := getString(sliceEndpoint(inst), "path")
+		if path == "" {
+			continue
+		}
+		if problem := endpointPathProblem(path); problem != "" {
+			errs = append(errs, ValidationError{Code: ErrEndpointPath, Message: fmt.Sprintf("slice %q endpoint path %q %s", getString(inst, "name"), path, problem), Slice: getString(inst, "name")})
+		}
+	}
+
+	return errs
+}
+
+// endpointPathProblem describes what is wrong with an endpoint path, or
+// returns "" when it is well-formed.
+func endpointPathProblem(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		return "must start with /"
+	}
+	open := -1 // index of the unclosed {, if any
+	for i, r := range path {
+		switch r {
+		case '{':
+			if open >= 0 {
+				return "has a nested {"
+			}
+			open = i
+		case '}':
+			if open < 0 {
+				return "has an unbalanced }"
+			}
+			if i == open+1 {
+				return "has an empty {} param"
+			}
+			open = -1
+		}
+	}
+	if open >= 0 {
+		return "has an unbalanced {"
+	}
+	return ""
+}
+
+// routeParamPattern matches a path template parameter such as {cartId}.
+var routeParamPattern = regexp.MustCompile(`\{\w+\}`)
+
+// validateEndpointRoutes checks that no two slices (change triggers and view
+// endpoints) declare the same verb and path. Paths differing only in their
+// parameter names (/users/{id} vs /users/{userId}) are the same route.
+func validateEndpointRoutes(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	flowIter, err := board.LookupPath(cue.ParsePath("flow")).List()
+	if err != nil {
+		return errs
+	}
+
+	type route struct{ slice, path string }
+	routes := make(map[string]route)
+	for flowIter.Next() {
+		inst := flowIter.Value()
+		if getString(inst, "kind") != "slice" {
+			continue
+		}
+		ep := sliceEndpoint(inst)
+		verb := getString(ep, "verb")
+		path := getString(ep, "path")
+		if verb == "" || path == "" {
+			continue
+		}
+
+		sliceName := getString(inst, "name")
+		key := verb + " " + routeParamPattern.ReplaceAllString(path, "{}")
+		first, ok := routes[key]
+		if !ok {
+			routes[key] = route{sliceName, path}
+			continue
+		}
+		errs = append(errs, ValidationError{Code: ErrEndpointRoute, Message: fmt.Sprintf("slice %q endpoint %s %s conflicts with slice %q endpoint %s %s", sliceName, verb, path, first.slice, verb, first.path), Slice: sliceName})
+	}
+
+	return errs
+}
+
+// validateUnusedPathParams warns about endpoint path params declared in
+// params that the slice doesn't use. A change or automation slice uses one
+// through a command field of the same name or a command mapping, a view
+// through a read model field of the same name or a query tag binding.
+// Undeclared path params are E105/E210.
+func validateUnusedPathParams(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	flowIter, err := board.LookupPath(cue.ParsePath("flow")).List()
+	if err != nil {
+		return errs
+	}
+	for flowIter.Next() {
+		inst := flowIter.Value()
+		if getString(inst, "kind") != "slice" {
+			continue
+		}
+		ep := sliceEndpoint(inst)
+		params := ep.LookupPath(cue.ParsePath("params"))
+		sliceName := getString(inst, "name")
+		isView := getString(inst, "type") == "view"
+		for _, m := range pathParamNamePattern.FindAllStringSubmatch(getString(ep, "path"), -1) {
+			param := m[1]
+			if !params.LookupPath(cue.MakePath(cue.Str(param))).Exists() {
+				continue
+			}
+			if isView && !viewUsesParam(inst, param) {
+				errs = append(errs, ValidationError{Code: ErrViewUnusedParam, Message: fmt.Sprintf("view %q endpoint: path param {%s} is neither a read model field nor bound to a query tag", sliceName, param), Slice: sliceName})
+			}
+			if !isView && !commandUsesParam(inst, param) {
+				errs = append(errs, ValidationError{Code: ErrCmdUnusedParam, Message: fmt.Sprintf("slice %q endpoint: path param {%s} is neither a command field nor used by the command mapping", sliceName, param), Slice: sliceName})
+			}
+		}
+	}
+
+	return errs
+}
+
+// pathParamNamePattern captures the name of a path template parameter.
+var pathParamNamePattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// commandUsesParam reports whether the command of inst has a field named
+// after the endpoint param, or maps a field from it.
+func commandUsesParam(inst cue.Value, param string) bool {
+	if inst.LookupPath(cue.MakePath(cue.Str("command"), cue.Str("fields"), cue.Str(param))).Exists() {
+		return true
+	}
+	iter, err := inst.LookupPath(cue.ParsePath("command.mapping")).Fields()
+	if err != nil {
+		return false
+	}
+	for iter.Next() {
+		if referencesParam(iter.Value(), param) {
+			return true
+		}
+	}
+	return false
+}
+
+// viewUsesParam reports whether the read model of view inst has a field
+// named after the endpoint param, or one of its queries binds a tag to it.
+func viewUsesParam(inst cue.Value, param string) bool {
+	if inst.LookupPath(cue.MakePath(cue.Str("readModel"), cue.Str("fields"), cue.Str(param))).Exists() {
+		return true
+	}
+	for _, queryPath := range []string{"query.items", "dependentQuery.items"} {
+		items, err := inst.LookupPath(cue.ParsePath(queryPath)).List()
+		if err != nil {
+			continue
+		}
+		for items.Next() {
+			tags, err := items.Value().LookupPath(cue.ParsePath("tags")).List()
+			if err != nil {
+				continue
+			}
+			for tags.Next() {
+				if referencesParam(tags.Value().LookupPath(cue.ParsePath("value")), param) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// referencesParam reports whether v refers to the endpoint param, directly
+// or as an operand of a unification (the schema's type & the reference).
+func referencesParam(v cue.Value, param string) bool {
+	suffix := "endpoint.params." + param
+	if _, path := v.ReferencePath(); strings.HasSuffix(path.String(), suffix) {
+		return true
+	}
+	_, args := v.Expr()
+	for _, arg := range args {
+		if _, path := arg.ReferencePath(); strings.HasSuffix(path.String(), suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateUniqueSliceNames checks that no two slices share a name. Slice
+// names become IR file names and story sliceRefs, so duplicates are ambiguous.
+func validateUniqueSliceNames(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	flowIter, err := board.LookupPath(cue.ParsePath("flow")).List()
+	if err != nil {
+		return errs
+	}
+
+	firstIndex := make(map[string]int)
+	for i := 0; flowIter.Next(); i++ {
+		inst := flowIter.Value()
+		if getString(inst, "kind") != "slice" {
+			continue
+		}
+		name := getString(inst, "name")
+		first, ok := firstIndex[name]
+		if !ok {
+			firstIndex[name] = i
+			continue
+		}
+		errs = append(errs, ValidationError{Code: ErrDuplicateSlice, Message: fmt.Sprintf("slice %q at flow index %d has the same name as the slice at flow index %d", name, i, first), Slice: name})
+	}
+
+	return errs
+}
+
+// queryItems returns the query and dependent query items of a slice.
+func queryItems(inst cue.Value) []cue.Value {
+	queries := []string{"command.query", "command.dependentQuery"}
+	if getString(inst, "type") == "view" {
+		queries = []string{"query", "dependentQuery"}
+	}
+	var items []cue.Value
+	for _, q := range queries {
+		itemIter, err := inst.LookupPath(cue.ParsePath(q + ".items")).List()
+		if err != nil {
+			continue
+		}
+		for itemIter.Next() {
+			items = append(items, itemIter.Value())
+		}
+	}
+	return items
+}
+
+// listEventTypes returns the eventType of each event in the list at path.
+func listEventTypes(v cue.Value, path string) []string {
+	var types []string
+	iter, err := v.LookupPath(cue.ParsePath(path)).List()
+	if err != nil {
+		return types
+	}
+	for iter.Next() {
+		if t := getString(iter.Value(), "eventType"); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// validateOrphanQueriedEvents warns about query types that no change or
+// automation slice emits anywhere in the flow. Unlike the ordering check,
+// the event is missing from the emit graph altogether.
+func validateOrphanQueriedEvents(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	flowIter, err := board.LookupPath(cue.ParsePath("flow")).List()
+	if err != nil {
+		return errs
+	}
+
+	var insts []cue.Value
+	emitted := make(map[string]bool)
+	for flowIter.Next() {
+		inst := flowIter.Value()
+		if getString(inst, "kind") != "slice" {
+			continue
+		}
+		insts = append(insts, inst)
+		for _, t := range listEventTypes(inst, "emits") {
+			emitted[t] = true
+		}
+	}
+
+	for _, inst := range insts {
+		reported := make(map[string]bool)
+		for _, item := range queryItems(inst) {
+			for _, eventType := range listEventTypes(item, "types") {
+				if emitted[eventType] || reported[eventType] {
+					continue
+				}
+				reported[eventType] = true
+				errs = append(errs, ValidationError{Code: ErrQueriedEventNeverEmitted, Message: fmt.Sprintf("slice %q queries event %q, which no slice emits", getString(inst, "name"), eventType), Slice: getString(inst, "name")})
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateUnusedEvents warns about events declared in board.events that no
+// slice emits, queries, reacts to or uses in a scenario.
+func validateUnusedEvents(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	flowIter, err := board.LookupPath(cue.ParsePath("flow")).List()
+	if err != nil {
+		return errs
+	}
+
+	used := make(map[string]bool)
+	for flowIter.Next() {
+		inst := flowIter.Value()
+		if getString(inst, "kind") != "slice" {
+			continue
+		}
+		types := listEventTypes(inst, "emits")
+		types = append(types, getString(inst, "trigger.internalEvent.eventType"))
+		for _, item := range queryItems(inst) {
+			types = append(types, listEventTypes(item, "types")...)
+		}
+		if scIter, err := inst.LookupPath(cue.ParsePath("scenarios")).List(); err == nil {
+			for scIter.Next() {
+				types = append(types, listEventTypes(scIter.Value(), "given")...)
+				types = append(types, listEventTypes(scIter.Value(), "then.events")...)
+			}
+		}
+		for _, t := range types {
+			used[t] = true
+		}
+	}
+
+	iter, err := board.LookupPath(cue.ParsePath("events")).Fields()
+	if err != nil {
+		return errs
+	}
+	for iter.Next() {
+		name := iter.Selector().Unquoted()
+		if !used[name] {
+			errs = append(errs, ValidationError{Code: ErrUnusedEvent, Message: fmt.Sprintf("event %q is declared but never emitted, queried or used in a scenario", name)})
+		}
+	}
+
+	return errs
+}
+
+// validateUnusedTags warns about tags declared in board.tags that no event
+// carries and no query filters on.
+func validateUnusedTags(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	used := make(map[string]bool)
+	markTags := func(v cue.Value) {
+		iter, err := v.LookupPath(cue.ParsePath("tags")).List()
+		if err != nil {
+			return
+		}
+		for iter.Next() {
+			// Bare #Tag or #TagRef with a value
+			name := getString(iter.Value(), "name")
+			if name == "" {
+				name = getString(iter.Value(), "tag.name")
+			}
+			used[name] = true
+		}
+	}
+
+	if iter, err := board.LookupPath(cue.ParsePath("events")).Fields(); err == nil {
+		for iter.Next() {
+			markTags(iter.Value())
+		}
+	}
+	if flowIter, err := board.LookupPath(cue.ParsePath("flow")).List(); err == nil {
+		for flowIter.Next() {
+			inst := flowIter.Value()
+			if getString(inst, "kind") != "slice" {
+				continue
+			}
+			if emitIter, err := inst.LookupPath(cue.ParsePath("emits")).List(); err == nil {
+				for emitIter.Next() {
+					markTags(emitIter.Value())
+				}
+			}
+			for _, item := range queryItems(inst) {
+				markTags(item)
+			}
+		}
+	}
+
+	iter, err := board.LookupPath(cue.ParsePath("tags")).Fields()
+	if err != nil {
+		return errs
+	}
+	for iter.Next() {
+		name := iter.Selector().Unquoted()
+		if !used[name] {
+			errs = append(errs, ValidationError{Code: ErrUnusedTag, Message: fmt.Sprintf("tag %q is declared but no event or query uses it", name)})
+		}
+	}
+
+	return errs
+}
+
+// validateReadModelReferences checks that each read model reference names a
+// read model of the board and sits on one of the referencing model's fields.
+func validateReadModelReferences(board cue.Value) []ValidationError {
+	var errs []ValidationError
+
+	var views []cue.Value
+	readModels := make(map[string]bool)
+	flowIter, err := board.LookupPath(cue.ParsePath("flow")).List()
+	if err != nil {
+		return errs
+	}
+	for flowIter.Next() {
+		inst := flowIter.Value()
+		if getString(inst, "kind") != "slice" || getString(inst, "type") != "view" {
+			continue
+		}
+		views = append(views, inst)
+		readModels[getString(inst, "readModel.name")] = true
+	}
+
+	for _, inst := range views {
+		rm := inst.LookupPath(cue.ParsePath("readModel"))
+		rmName := getString(rm, "name")
+		iter, err := rm.LookupPath(cue.ParsePath("references")).Fields()
+		if err != nil {
+			continue
+		}
+		schema := rm.LookupPath(cue.ParsePath("fields"))
+		if getString(rm, "cardinality") == "table" {
+			schema = rm.LookupPath(cue.ParsePath("columns"))
+		}
+		for iter.Next() {
+			field := iter.Selector().Unquoted()
+			target, _ := iter.Value().String()
+			if !readModels[target] {
+				errs = append(errs, ValidationError{Code: ErrReadModelRef, Message: fmt.Sprintf("read model %q field %q references undefined read model %q", rmName, field, target), Field: field})
+			}
+			if !schema.LookupPath(cue.MakePath(cue.Str(field))).Exists() {
+				errs = append(errs, ValidationError{Code: ErrReadModelRef, Message: fmt.Sprintf("read model %q references %q through %q, which is not one of its fields", rmName, target, field), Field: field})
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
+)
+
+// renderCatalog renders the event catalog: one box per event type with its
+// fields, emitters and consumers. Events nobody consumes (or emits) are
+// flagged so they stand out.
+func renderCatalog(entries []board.CatalogEntry, width int) string {
+	if len(entries) == 0 {
+		return "No events\n"
+	}
+	var sb strings.Builder
+	for _, e := range entries {
+		box := render.NewBoxWrapped(width)
+		box.AddLine("  EVENT: " + e.Type)
+		if len(e.Fields) > 0 {
+			box.AddSection()
+			for _, k := range slices.Sorted(maps.Keys(e.Fields)) {
+				box.AddLine(fmt.Sprintf("    - %s: %s", k, catalogFieldType(e.Fields[k])))
+			}
+		}
+		box.AddSection()
+		box.AddLine("  emitted by:  " + catalogSlices(e.Emitters, "⚠ no emitter"))
+		box.AddLine("  consumed by: " + catalogSlices(e.Consumers, "⚠ no consumer"))
+		sb.WriteString(box.Render())
+	}
+	return sb.String()
+}
+
+// catalogSlices lists slice names, or none when there are none.
+func catalogSlices(names []string, none string) string {
+	if len(names) == 0 {
+		return none
+	}
+	return strings.Join(names, ", ")
+}
+
+// catalogFieldType prints a reified field type on one line.
+func catalogFieldType(t any) string {
+	switch v := t.(type) {
+	case string:
+		return v
+	case map[string]any:
+		var fields []string
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			fields = append(fields, k+": "+catalogFieldType(v[k]))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	case []any:
+		if len(v) > 0 {
+			return "[" + catalogFieldType(v[0]) + "]"
+		}
+		return "[]"
+	}
+	return fmt.Sprint(t)
+}
@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
+)
+
+// helpKeys are the keybindings listed by the help overlay.
+var helpKeys = [][2]string{
+	{"j/k ↑/↓", "move in the tree, scroll a view"},
+	{"enter/l", "expand, open the highlighted slice"},
+	{"h", "collapse"},
+	{"space", "toggle expanded"},
+	{"/", "search (type:<change|view|automation> status:<devstatus> words)"},
+	{"esc", "back; clear the search"},
+	{"w/W", "write the open slice to <name>.txt / <name>.json"},
+	{"t", "flat list / tree"},
+	{"c", "cycle the context filter"},
+	{"C", "event catalog"},
+	{"e", "load errors"},
+	{"n/N", "next/previous error"},
+	{"?", "this help"},
+	{"q", "back; quit from the board"},
+}
+
+// helpRows explains the slice rows of the tree.
+var helpRows = [][2]string{
+	{"[CMD]", "change slice: a command emitting events"},
+	{"[VIEW]", "view slice: a read model built from events"},
+	{"[AUTO]", "automation: a command run on an event"},
+	{"(status)", "devstatus: specifying, todo, doing or done"},
+	{"← a, b", "events the slice consumes, trigger first"},
+}
+
+// renderHelp renders the help overlay: the keybindings, the meaning of the
+// tree rows and the diagnostic codes, in a box centered in width.
+func renderHelp(width int) string {
+	boxWidth := min(max(width-4, 40), 100)
+	box := render.NewBoxWrapped(boxWidth)
+	box.AddLine("  KEYS")
+	for _, k := range helpKeys {
+		box.AddLine(fmt.Sprintf("    %-10s %s", k[0], k[1]))
+	}
+	box.AddSection()
+	box.AddLine("  ROWS")
+	for _, r := range helpRows {
+		box.AddLine(fmt.Sprintf("    %-10s %s", r[0], r[1]))
+	}
+	box.AddSection()
+	box.AddLine("  DIAGNOSTICS")
+	for _, c := range render.ErrorCodeCatalog() {
+		box.AddLine(fmt.Sprintf("    %-5s %-8s %s", c.Code, c.Severity, c.Name))
+	}
+	return lipgloss.PlaceHorizontal(width, lipgloss.Center, strings.TrimSuffix(box.Render(), "\n"))
+}
@@ -1,7 +1,6 @@
 package tui
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -25,6 +24,8 @@ const (
 	searchMode
 	detailMode
 	errorMode
+	catalogMode
+	helpMode // overlays helpReturn, restored when the help closes
 )
 
 // irReloadedMsg is sent when the IR directory watcher detects a change.
@@ -49,17 +50,36 @@ type IRModel struct {
 	currentFile    string // file currently being viewed in detailMode
 	waitingForFile string // file path we're waiting to appear (empty if not waiting)
 	width          int
+	fixedWidth     int // if > 0, overrides the terminal width for rendering
 	height         int
 	viewport       viewport.Model
 	ready          bool
 	tree           *TreeState
-	reloadErr      string
+	errs           []string // load errors, one diagnostic each
+	errSlices      []string // slice each load error is about, "" if unknown
+	errCursor      int      // error selected in errorMode
+	errLines       []int    // first viewport line of each error in errorMode
+	helpReturn     viewMode // mode under the help overlay
+	notice         string   // detailMode footer message, until the next key
+	helpView       viewport.Model
 
 	searchInput textinput.Model
 }
 
+// IRModelOptions tweaks the IR TUI.
+type IRModelOptions struct {
+	// Width forces the rendering width; terminal resizes then only change the height.
+	// Zero means the terminal width.
+	Width int
+}
+
 // NewIRModel creates a TUI model from an IR directory.
 func NewIRModel(dir string) (IRModel, error) {
+	return NewIRModelWithOptions(dir, IRModelOptions{})
+}
+
+// NewIRModelWithOptions is NewIRModel with explicit options.
+func NewIRModelWithOptions(dir string, opts IRModelOptions) (IRModel, error) {
 	manifest, slices, err := loadIRDir(dir)
 	if err != nil {
 		return IRModel{}, err
@@ -76,13 +96,13 @@ func NewIRModel(dir string) (IRModel, error) {
 		manifest:    manifest,
 		slices:      slices,
 		mode:        boardMode,
+		width:       opts.Width,
+		fixedWidth:  opts.Width,
 		tree:        tree,
 		searchInput: ti,
 	}
 	// Show manifest errors on initial load
-	if len(manifest.Errors) > 0 {
-		m.reloadErr = strings.Join(manifest.Errors, "\n")
-	}
+	m.setManifestErrors(manifest)
 	return m, nil
 }
 
@@ -135,48 +155,33 @@ func (m IRModel) watchIRDirCmd() tea.Cmd {
 }
 
 func (m IRModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.mode == helpMode {
+		return m.updateHelp(msg)
+	}
+
 	switch msg := msg.(type) {
 	case irReloadedMsg:
 		if msg.err != nil {
-			m.reloadErr = msg.err.Error()
-			if m.mode != errorMode {
-				m.previousMode = m.mode
-				if m.mode == detailMode && m.currentFile != "" {
-					m.previousFile = m.currentFile
-				}
-			}
-			m.mode = errorMode
-			wrapped := lipgloss.NewStyle().Width(m.width - 2).Render(m.reloadErr)
-			m.viewport.SetContent(wrapped)
-			m.viewport.GotoTop()
+			m.setErrors(strings.Split(msg.err.Error(), "\n"))
+			m.showErrors()
 			return m, m.watchIRDirCmd()
 		}
 		m.manifest = msg.manifest
 		m.slices = msg.slices
-		m.tree = NewTreeState(m.manifest, m.slices)
+		m.resetTree()
 		// Show manifest-level errors
-		if len(m.manifest.Errors) > 0 {
-			m.reloadErr = strings.Join(m.manifest.Errors, "\n")
-			if m.mode != errorMode {
-				m.previousMode = m.mode
-				if m.mode == detailMode && m.currentFile != "" {
-					m.previousFile = m.currentFile
-				}
-			}
-			m.mode = errorMode
-			wrapped := lipgloss.NewStyle().Width(m.width - 2).Render(m.reloadErr)
-			m.viewport.SetContent(wrapped)
-			m.viewport.GotoTop()
+		m.setManifestErrors(m.manifest)
+		if len(m.errs) > 0 {
+			m.showErrors()
 		} else {
-			// No errors - clear error state and restore previous view
-			m.reloadErr = ""
+			// No errors - restore previous view
 			if m.mode == errorMode {
 				if m.previousMode == detailMode && m.previousFile != "" {
 					// Try to restore to detail view
 					if data, ok := m.slices[m.previousFile]; ok {
 						m.mode = detailMode
 						m.currentFile = m.previousFile
-						output, _ := render.RenderSliceIR(data, m.width)
+						output, _ := renderSliceDetail(data, m.width)
 						m.viewport.SetContent(output)
 					} else {
 						// File not ready yet, wait for it
@@ -195,7 +200,7 @@ func (m IRModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// File appeared, restore to detailMode
 				m.mode = detailMode
 				m.currentFile = m.waitingForFile
-				output, _ := render.RenderSliceIR(data, m.width)
+				output, _ := renderSliceDetail(data, m.width)
 				m.viewport.SetContent(output)
 				m.waitingForFile = ""
 			} else {
@@ -204,9 +209,11 @@ func (m IRModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		} else if m.mode == detailMode && m.currentFile != "" {
 			if data, ok := m.slices[m.currentFile]; ok {
-				output, _ := render.RenderSliceIR(data, m.width)
+				output, _ := renderSliceDetail(data, m.width)
 				m.viewport.SetContent(output)
 			}
+		} else if m.mode == catalogMode {
+			m.viewport.SetContent(m.renderCatalog())
 		}
 		return m, m.watchIRDirCmd()
 
@@ -222,10 +229,10 @@ func (m IRModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if err == nil && slices[m.waitingForFile] != nil {
 				m.manifest = manifest
 				m.slices = slices
-				m.tree = NewTreeState(m.manifest, m.slices)
+				m.resetTree()
 				m.mode = detailMode
 				m.currentFile = m.waitingForFile
-				output, _ := render.RenderSliceIR(slices[m.waitingForFile], m.width)
+				output, _ := renderSliceDetail(slices[m.waitingForFile], m.width)
 				m.viewport.SetContent(output)
 				m.waitingForFile = ""
 				return m, m.watchIRDirCmd()
@@ -236,35 +243,51 @@ func (m IRModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
+		if m.fixedWidth > 0 {
+			m.width = m.fixedWidth
+		}
 		m.height = msg.Height
 		if !m.ready {
-			m.viewport = viewport.New(msg.Width, msg.Height-2)
+			m.viewport = viewport.New(m.width, msg.Height-2)
 			m.ready = true
 		} else {
-			m.viewport.Width = msg.Width
+			m.viewport.Width = m.width
 			m.viewport.Height = msg.Height - 2
 		}
 		if m.mode == detailMode && m.currentFile != "" {
 			if data, ok := m.slices[m.currentFile]; ok {
-				output, _ := render.RenderSliceIR(data, m.width)
+				output, _ := renderSliceDetail(data, m.width)
 				m.viewport.SetContent(output)
 			}
+		} else if m.mode == catalogMode {
+			m.viewport.SetContent(m.renderCatalog())
+		} else if m.mode == errorMode {
+			// Re-wrap the errors to the new width, keeping the selected one in view
+			m.renderErrors()
+			if m.errCursor < len(m.errLines) {
+				m.viewport.SetYOffset(m.errLines[m.errCursor])
+			}
 		}
 		return m, nil
 
 	case tea.KeyMsg:
+		m.notice = ""
 		if m.mode == searchMode {
 			switch msg.String() {
 			case "esc":
 				m.mode = boardMode
 				m.searchInput.SetValue("")
+				m.searchInput.Blur()
+				m.tree.SetQuery("")
 				return m, nil
 			case "enter":
 				m.mode = boardMode
+				m.searchInput.Blur()
 				return m, nil
 			default:
 				var cmd tea.Cmd
 				m.searchInput, cmd = m.searchInput.Update(msg)
+				m.tree.SetQuery(m.searchInput.Value())
 				return m, cmd
 			}
 		}
@@ -276,6 +299,10 @@ func (m IRModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.currentFile = ""
 				return m, nil
 			}
+			if m.mode == catalogMode && msg.String() == "q" {
+				m.mode = boardMode
+				return m, nil
+			}
 			return m, tea.Quit
 		case "esc":
 			// Cancel waiting for file
@@ -288,8 +315,34 @@ func (m IRModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.currentFile = ""
 				return m, nil
 			}
+			if m.mode == catalogMode {
+				m.mode = boardMode
+				return m, nil
+			}
+			if m.mode == boardMode && m.tree.Query() != "" {
+				m.searchInput.SetValue("")
+				m.tree.SetQuery("")
+				return m, nil
+			}
 			if m.mode == errorMode {
 				m.mode = boardMode
+				// Highlight the slice the selected error is about
+				if m.errCursor < len(m.errs) {
+					if name := m.errSlices[m.errCursor]; name != "" {
+						m.tree.Select(name)
+					}
+				}
+				return m, nil
+			}
+		case "n", "N":
+			if m.mode == errorMode && len(m.errs) > 0 {
+				if msg.String() == "n" {
+					m.errCursor = min(m.errCursor+1, len(m.errs)-1)
+				} else {
+					m.errCursor = max(m.errCursor-1, 0)
+				}
+				m.renderErrors()
+				m.viewport.SetYOffset(m.errLines[m.errCursor])
 				return m, nil
 			}
 		case "/":
@@ -299,10 +352,9 @@ func (m IRModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, textinput.Blink
 			}
 		case "e":
-			if (m.mode == boardMode || m.mode == detailMode) && m.reloadErr != "" {
+			if (m.mode == boardMode || m.mode == detailMode) && len(m.errs) > 0 {
 				m.mode = errorMode
-				wrapped := lipgloss.NewStyle().Width(m.width - 2).Render(m.reloadErr)
-				m.viewport.SetContent(wrapped)
+				m.renderErrors()
 				m.viewport.GotoTop()
 				return m, nil
 			}
@@ -326,7 +378,7 @@ func (m IRModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if data := m.slices[file]; data != nil {
 						m.mode = detailMode
 						m.currentFile = file
-						output, err := render.RenderSliceIR(data, m.width)
+						output, err := renderSliceDetail(data, m.width)
 						if err != nil {
 							m.viewport.SetContent(fmt.Sprintf("Error rendering: %v", err))
 						} else {
@@ -347,9 +399,42 @@ func (m IRModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.tree.Toggle()
 				return m, nil
 			}
+		case "c":
+			if m.mode == boardMode {
+				m.tree.CycleContextFilter()
+				return m, nil
+			}
+		case "t":
+			if m.mode == boardMode {
+				m.tree.ToggleFlat()
+				return m, nil
+			}
+		case "w", "W":
+			if m.mode == detailMode && m.currentFile != "" {
+				path, err := m.exportSlice(msg.String() == "W")
+				if err != nil {
+					m.notice = "error: " + err.Error()
+				} else {
+					m.notice = "wrote " + path
+				}
+				return m, nil
+			}
+		case "?":
+			m.helpReturn = m.mode
+			m.mode = helpMode
+			m.helpView = viewport.New(m.width, m.viewport.Height)
+			m.helpView.SetContent(renderHelp(m.width))
+			return m, nil
+		case "C":
+			if m.mode == boardMode {
+				m.mode = catalogMode
+				m.viewport.SetContent(m.renderCatalog())
+				m.viewport.GotoTop()
+				return m, nil
+			}
 		}
 
-		if m.mode == detailMode || m.mode == errorMode {
+		if m.mode == detailMode || m.mode == errorMode || m.mode == catalogMode {
 			var cmd tea.Cmd
 			m.viewport, cmd = m.viewport.Update(msg)
 			return m, cmd
@@ -359,6 +444,160 @@ func (m IRModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateHelp handles a message under the help overlay: keys scroll or close
+// it, anything else (reloads, resizes) updates the mode underneath.
+func (m IRModel) updateHelp(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "?", "esc", "q":
+			m.mode = m.helpReturn
+			return m, nil
+		case "ctrl+c":
+			return m, tea.Quit
+		}
+		var cmd tea.Cmd
+		m.helpView, cmd = m.helpView.Update(msg)
+		return m, cmd
+	}
+
+	m.mode = m.helpReturn
+	next, cmd := m.Update(msg)
+	m = next.(IRModel)
+	m.helpReturn, m.mode = m.mode, helpMode
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.helpView.Width = m.width
+		m.helpView.Height = size.Height - 2
+		m.helpView.SetContent(renderHelp(m.width))
+	}
+	return m, cmd
+}
+
+// exportSlice writes the slice of the detail view to the current directory,
+// named after it: the rendered box as <name>.txt, or its IR file as
+// <name>.json. Returns the path written.
+func (m IRModel) exportSlice(asJSON bool) (string, error) {
+	data := m.slices[m.currentFile]
+	name, _ := data["name"].(string)
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(m.currentFile), ".json")
+	}
+
+	var content []byte
+	path := name + ".txt"
+	if asJSON {
+		path = name + ".json"
+		raw, err := os.ReadFile(filepath.Join(m.irDir, m.currentFile))
+		if err != nil {
+			return "", err
+		}
+		content = raw
+	} else {
+		// The plain box: the viewport's is styled
+		out, err := render.RenderSliceIRWithOptions(data, render.RenderOptions{Width: m.width, Wrap: true})
+		if err != nil {
+			return "", err
+		}
+		content = []byte(out)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return "", err
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return path, nil
+}
+
+// setErrors replaces the load errors, dropping blank lines.
+func (m *IRModel) setErrors(errs []string) {
+	m.errs = nil
+	for _, e := range errs {
+		if strings.TrimSpace(e) != "" {
+			m.errs = append(m.errs, e)
+		}
+	}
+	m.errSlices = make([]string, len(m.errs))
+	m.errCursor = 0
+}
+
+// setManifestErrors replaces the load errors with those of a manifest,
+// taking the slice each is about from its diagnostics.
+func (m *IRModel) setManifestErrors(manifest *board.BoardManifest) {
+	m.setErrors(manifest.Errors)
+	if len(manifest.Diagnostics) != len(m.errs) {
+		return // IR written without diagnostics
+	}
+	for i, d := range manifest.Diagnostics {
+		m.errSlices[i] = d.Slice
+	}
+}
+
+// showErrors switches to errorMode, remembering the view to restore once the
+// errors are fixed.
+func (m *IRModel) showErrors() {
+	if m.mode != errorMode {
+		m.previousMode = m.mode
+		if m.mode == detailMode && m.currentFile != "" {
+			m.previousFile = m.currentFile
+		}
+	}
+	m.mode = errorMode
+	m.renderErrors()
+	m.viewport.GotoTop()
+}
+
+// renderErrors fills the viewport with the errors, marking the selected one,
+// and records where each starts for n/N.
+func (m *IRModel) renderErrors() {
+	wrap := lipgloss.NewStyle().Width(max(m.width-4, 1))
+	var lines []string
+	m.errLines = m.errLines[:0]
+	for i, e := range m.errs {
+		m.errLines = append(m.errLines, len(lines))
+		for j, line := range strings.Split(wrap.Render(e), "\n") {
+			switch {
+			case i != m.errCursor:
+				line = "  " + line
+			case j == 0:
+				line = errorStyle.Render("▶ " + line)
+			default:
+				line = errorStyle.Render("  " + line)
+			}
+			lines = append(lines, line)
+		}
+	}
+	m.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// errorSummary is the footer line for the load errors: the first one,
+// truncated, and how many others there are.
+func (m IRModel) errorSummary() string {
+	errMsg := m.errs[0]
+	if len(m.errs) > 1 {
+		errMsg = fmt.Sprintf("%s (+%d more)", errMsg, len(m.errs)-1)
+	}
+	if len(errMsg) > m.width-20 {
+		errMsg = errMsg[:max(m.width-20, 0)] + "..."
+	}
+	return errorStyle.Render("error: " + errMsg + " [e: details]")
+}
+
+// resetTree rebuilds the tree from the current manifest, keeping the context
+// filter, the layout and the search query.
+func (m *IRModel) resetTree() {
+	filter, flat, query := m.tree.ContextFilter(), m.tree.Flat(), m.tree.Query()
+	m.tree = NewTreeState(m.manifest, m.slices)
+	if filter != "" {
+		m.tree.SetContextFilter(filter)
+	}
+	if flat {
+		m.tree.SetFlat(true)
+	}
+	if query != "" {
+		m.tree.SetQuery(query)
+	}
+}
+
 // selectedSliceFile returns the file path for the currently selected row.
 func (m IRModel) selectedSliceFile() string {
 	idx := m.tree.CurrentFlowIndex()
@@ -388,6 +627,10 @@ func (m IRModel) View() string {
 		return m.renderDetailView()
 	case errorMode:
 		return m.renderErrorView()
+	case catalogMode:
+		return m.renderCatalogView()
+	case helpMode:
+		return m.renderHelpView()
 	default:
 		return m.renderBoardView()
 	}
@@ -403,30 +646,46 @@ func (m IRModel) renderDetailView() string {
 		Width(m.width).
 		Render(fmt.Sprintf(" %s > %s ", m.manifest.Name, name))
 
+	status := fmt.Sprintf(" %d%%  |  complexity: %d  |  j/k: scroll  w/W: write text/JSON  esc: back  q: quit",
+		int(m.viewport.ScrollPercent()*100), board.SliceComplexity(m.slices[m.currentFile]))
+	if m.notice != "" {
+		status = " " + m.notice
+	}
 	footer := lipgloss.NewStyle().
 		Width(m.width).
 		Foreground(lipgloss.Color("#626262")).
-		Render(fmt.Sprintf(" %d%%  |  j/k: scroll  esc: back  q: quit",
-			int(m.viewport.ScrollPercent()*100)))
+		Render(status)
 
-	if m.reloadErr != "" {
-		errMsg := m.reloadErr
-		if len(errMsg) > m.width-20 {
-			errMsg = errMsg[:m.width-20] + "..."
-		}
-		return header + "\n" + m.viewport.View() + "\n" +
-			errorStyle.Render("error: "+errMsg+" [e: details]") + "\n" + footer
+	if len(m.errs) > 0 {
+		return header + "\n" + m.viewport.View() + "\n" + m.errorSummary() + "\n" + footer
 	}
 
 	return header + "\n" + m.viewport.View() + "\n" + footer
 }
 
 func (m IRModel) renderErrorView() string {
-	header := errorStyle.Width(m.width).Render(" Error ")
-	footer := footerStyle.Width(m.width).Render(" j/k: scroll  esc: back ")
+	header := errorStyle.Width(m.width).Render(fmt.Sprintf(" Error %d/%d ", min(m.errCursor+1, len(m.errs)), len(m.errs)))
+	footer := footerStyle.Width(m.width).Render(" j/k: scroll  n/N: next/previous error  esc: back to its slice ")
+	return header + "\n" + m.viewport.View() + "\n" + footer
+}
+
+func (m IRModel) renderCatalogView() string {
+	header := titleStyle.Width(m.width).Render(fmt.Sprintf(" %s > Event catalog ", m.manifest.Name))
+	footer := footerStyle.Width(m.width).Render(fmt.Sprintf(" %d%%  |  j/k: scroll  esc/q: back", int(m.viewport.ScrollPercent()*100)))
 	return header + "\n" + m.viewport.View() + "\n" + footer
 }
 
+func (m IRModel) renderHelpView() string {
+	header := titleStyle.Width(m.width).Render(fmt.Sprintf(" %s > Help ", m.manifest.Name))
+	footer := footerStyle.Width(m.width).Render(fmt.Sprintf(" %d%%  |  j/k: scroll  ?/esc: close", int(m.helpView.ScrollPercent()*100)))
+	return header + "\n" + m.helpView.View() + "\n" + footer
+}
+
+// renderCatalog renders the event catalog of the loaded slices.
+func (m IRModel) renderCatalog() string {
+	return renderCatalog(board.EventCatalog(board.FlowSlices(*m.manifest, m.slices)), m.width)
+}
+
 func (m IRModel) renderBoardView() string {
 	var s strings.Builder
 
@@ -434,33 +693,90 @@ func (m IRModel) renderBoardView() string {
 	header := titleStyle.Width(m.width).Render(fmt.Sprintf(" %s ", m.manifest.Name))
 	s.WriteString(header + "\n\n")
 
-	// Tree view
-	s.WriteString(m.renderTree())
+	// Tree view, with the highlighted slice alongside on wide terminals
+	if m.width >= splitMinWidth {
+		s.WriteString(m.renderSplit())
+	} else {
+		s.WriteString(m.renderTree(m.width))
+	}
 
 	// Footer with keybindings
 	s.WriteString("\n")
 	if m.waitingForFile != "" {
 		s.WriteString(footerStyle.Render("waiting for "+m.waitingForFile+"... [esc: cancel]") + "\n")
-	} else if m.reloadErr != "" {
-		errMsg := m.reloadErr
-		if len(errMsg) > m.width-20 {
-			errMsg = errMsg[:m.width-20] + "..."
-		}
-		s.WriteString(errorStyle.Render("error: "+errMsg+" [e: details]") + "\n")
+	} else if len(m.errs) > 0 {
+		s.WriteString(m.errorSummary() + "\n")
+	}
+	if m.mode == searchMode {
+		s.WriteString(m.searchInput.View() + "\n")
+	}
+	var status []string
+	if q := m.tree.Query(); q != "" && m.mode != searchMode {
+		status = append(status, "search: "+q)
+	}
+	if ctx := m.tree.ContextFilter(); ctx != "" {
+		status = append(status, "context: "+ctx)
+	}
+	if data := m.slices[m.selectedSliceFile()]; data != nil {
+		status = append(status, fmt.Sprintf("complexity: %d", board.SliceComplexity(data)))
+	}
+	if len(status) > 0 {
+		s.WriteString(footerStyle.Render(" "+strings.Join(status, "  |  ")) + "\n")
+	}
+	if m.mode == searchMode {
+		s.WriteString(footerStyle.Render(" type:<change|view|automation>  status:<devstatus>  words: name  |  enter: keep  esc: clear"))
+	} else {
+		s.WriteString(footerStyle.Render(" j/k: nav  enter/l: expand/open  h: collapse  space: toggle  /: search  c: context  C: events  t: flat/tree  ?: help  q: quit"))
 	}
-	s.WriteString(footerStyle.Render(" j/k: nav  enter/l: expand/open  h: collapse  space: toggle  q: quit"))
 
 	return s.String()
 }
 
-// renderTree renders the tree view.
-func (m IRModel) renderTree() string {
-	var lines []string
-	visibleHeight := m.height - 6 // account for header + footer
-	if visibleHeight < 5 {
-		visibleHeight = 5
+// renderSliceDetail renders a slice for the detail views, wrapping long lines
+// so nothing is cut at the right edge, in the colors of its roles.
+func renderSliceDetail(data map[string]any, width int) (string, error) {
+	return render.RenderSliceIRWithOptions(data, render.RenderOptions{Width: width, Wrap: true, Style: styleDetail})
+}
+
+// splitMinWidth is the terminal width from which the board view shows the
+// highlighted slice's detail next to the tree.
+const splitMinWidth = 160
+
+// renderSplit renders the tree on the left and the live detail of the
+// highlighted slice on the right.
+func (m IRModel) renderSplit() string {
+	treeWidth := m.width * 2 / 5
+	detailWidth := m.width - treeWidth - 1
+
+	tree := lipgloss.NewStyle().Width(treeWidth).Render(m.renderTree(treeWidth))
+
+	var detail string
+	if data := m.slices[m.selectedSliceFile()]; data != nil {
+		output, err := renderSliceDetail(data, detailWidth)
+		if err != nil {
+			output = fmt.Sprintf("Error rendering: %v", err)
+		}
+		lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+		if h := m.treeHeight(); len(lines) > h {
+			lines = lines[:h]
+		}
+		detail = strings.Join(lines, "\n")
 	}
 
+	sep := strings.TrimRight(strings.Repeat("│\n", m.treeHeight()), "\n")
+	return lipgloss.JoinHorizontal(lipgloss.Top, tree, footerStyle.Render(sep), detail)
+}
+
+// treeHeight is the number of tree rows the board view shows.
+func (m IRModel) treeHeight() int {
+	return max(m.height-6, 5) // account for header + footer
+}
+
+// renderTree renders the tree view, truncating rows to width.
+func (m IRModel) renderTree(width int) string {
+	var lines []string
+	visibleHeight := m.treeHeight()
+
 	// Calculate visible window
 	start := 0
 	if m.tree.Cursor >= visibleHeight {
@@ -478,9 +794,11 @@ func (m IRModel) renderTree() string {
 		// Build line: indent + icon + name + extras
 		var line strings.Builder
 
-		// Indent
-		for j := 0; j < node.Depth; j++ {
-			line.WriteString(treeIndent)
+		// Indent (the flat list has no hierarchy to show)
+		if !m.tree.Flat() {
+			for j := 0; j < node.Depth; j++ {
+				line.WriteString(treeIndent)
+			}
 		}
 
 		// Expand icon
@@ -499,23 +817,32 @@ func (m IRModel) renderTree() string {
 		switch node.Kind {
 		case NodeSlice:
 			prefix := ""
-			if node.SliceType == "change" {
+			switch node.SliceType {
+			case "change":
 				prefix = "[CMD] "
-			} else if node.SliceType == "view" {
+			case "view":
 				prefix = "[VIEW] "
+			case "automation":
+				prefix = "[AUTO] "
 			}
 			name = prefix + name
 			if node.DevStatus != "" {
 				name += " (" + node.DevStatus + ")"
 			}
+			if len(node.Consumes) > 0 {
+				name += " ← " + strings.Join(node.Consumes, ", ")
+			}
 		}
 		line.WriteString(name)
 
 		// Apply styling
 		lineStr := line.String()
+		if r := []rune(lineStr); len(r) > width {
+			lineStr = string(r[:width-1]) + "…"
+		}
 		var styled string
 		if isCursor {
-			styled = treeCursorStyle.Width(m.width).Render(lineStr)
+			styled = treeCursorStyle.Width(width).Render(lineStr)
 		} else {
 			switch node.Kind {
 			case NodeContext:
@@ -536,33 +863,5 @@ func (m IRModel) renderTree() string {
 // --- IR data helpers ---
 
 func loadIRDir(dir string) (*board.BoardManifest, map[string]map[string]any, error) {
-	manifestPath := filepath.Join(dir, "board.json")
-	data, err := os.ReadFile(manifestPath)
-	if err != nil {
-		return nil, nil, fmt.Errorf("read board.json: %w", err)
-	}
-
-	var manifest board.BoardManifest
-	if err := json.Unmarshal(data, &manifest); err != nil {
-		return nil, nil, fmt.Errorf("parse board.json: %w", err)
-	}
-
-	slices := make(map[string]map[string]any)
-	for _, entry := range manifest.Flow {
-		if entry.File == "" {
-			continue
-		}
-		sliceData, err := os.ReadFile(filepath.Join(dir, entry.File))
-		if err != nil {
-			continue // slice file may not exist yet
-		}
-		var m map[string]any
-		if err := json.Unmarshal(sliceData, &m); err != nil {
-			continue
-		}
-		slices[entry.File] = m
-	}
-
-	return &manifest, slices, nil
+	return board.ReadBoardFiles(dir)
 }
-
@@ -1,6 +1,10 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
+)
 
 var (
 	baseStyle = lipgloss.NewStyle().
@@ -40,3 +44,24 @@ var (
 	treeLeafIcon       = "  "
 	treeIndent         = "  "
 )
+
+// Detail view styles, by render.Role
+var detailStyles = map[render.Role]lipgloss.Style{
+	render.RoleChangeSlice:     lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00AAFF")),
+	render.RoleAutomationSlice: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFAA00")),
+	render.RoleViewSlice:       lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#44CC44")),
+	render.RoleTrigger:         lipgloss.NewStyle().Foreground(lipgloss.Color("#FAFAFA")),
+	render.RoleEmit:            lipgloss.NewStyle().Foreground(lipgloss.Color("#FF9900")),
+	render.RoleQuery:           lipgloss.NewStyle().Foreground(lipgloss.Color("#00AAFF")),
+	render.RoleReadModel:       lipgloss.NewStyle().Foreground(lipgloss.Color("#44CC44")),
+	render.RoleSuccess:         lipgloss.NewStyle().Foreground(lipgloss.Color("#44CC44")),
+	render.RoleError:           errorStyle,
+}
+
+// styleDetail is the render.RenderOptions Style hook of the detail views.
+func styleDetail(role render.Role, s string) string {
+	if st, ok := detailStyles[role]; ok {
+		return st.Render(s)
+	}
+	return s
+}
@@ -1,6 +1,11 @@
 package tui
 
-import "github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+import (
+	"slices"
+	"strings"
+
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+)
 
 // NodeKind identifies tree node types.
 type NodeKind int
@@ -22,8 +27,16 @@ type TreeNode struct {
 	Parent      *TreeNode
 
 	// For slices: extra display info
-	SliceType string // "change" or "view"
+	SliceType string // "change", "view" or "automation"
 	DevStatus string
+	Consumes  []string // triggering event, then queried event types
+
+	search sliceKey // lowercased for the search, once
+}
+
+// sliceKey is what a search matches of a slice node, lowercased.
+type sliceKey struct {
+	name, sliceType, devStatus string
 }
 
 // TreeState manages expand/collapse state and cursor position.
@@ -33,6 +46,78 @@ type TreeState struct {
 	FlatView []*TreeNode          // visible nodes based on expansion
 	Cursor   int                  // cursor in FlatView
 	nodeByFlowIndex map[int]*TreeNode // lookup slice nodes by flow index
+
+	contextFilter string      // only show this context (empty = all)
+	flat          bool        // list slices in flow order, without contexts and chapters
+	query         string      // search query, see SetQuery
+	filter        sliceFilter // parsed query
+
+	sliceNodes []*TreeNode        // every slice node, in tree order
+	matching   []*TreeNode        // slice nodes passing the filter
+	matched    map[*TreeNode]bool // matching slices and their chapters and contexts
+}
+
+// sliceFilter is a parsed search query: key:value tokens restrict the
+// slice type and dev status (several values of a key are alternatives),
+// the remaining words must all occur in the slice name.
+type sliceFilter struct {
+	types    []string
+	statuses []string
+	words    []string
+}
+
+// parseSliceFilter parses a search query like "type:view status:done cart".
+// Tokens with an unknown key are searched as words.
+func parseSliceFilter(query string) sliceFilter {
+	var f sliceFilter
+	for _, tok := range strings.Fields(strings.ToLower(query)) {
+		key, value, ok := strings.Cut(tok, ":")
+		switch {
+		case ok && key == "type" && value != "":
+			f.types = append(f.types, value)
+		case ok && (key == "status" || key == "devstatus") && value != "":
+			f.statuses = append(f.statuses, value)
+		default:
+			f.words = append(f.words, tok)
+		}
+	}
+	return f
+}
+
+// empty reports whether the filter lets every slice through.
+func (f sliceFilter) empty() bool {
+	return len(f.types) == 0 && len(f.statuses) == 0 && len(f.words) == 0
+}
+
+// matches reports whether a slice node passes the filter.
+func (f sliceFilter) matches(node *TreeNode) bool {
+	if len(f.types) > 0 && !slices.Contains(f.types, node.search.sliceType) {
+		return false
+	}
+	if len(f.statuses) > 0 && !slices.Contains(f.statuses, node.search.devStatus) {
+		return false
+	}
+	for _, w := range f.words {
+		if !strings.Contains(node.search.name, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// narrows reports whether every slice matching f also matches prev, as
+// when typing more of a query: the same types and statuses, and words
+// extending prev's.
+func (f sliceFilter) narrows(prev sliceFilter) bool {
+	if !slices.Equal(f.types, prev.types) || !slices.Equal(f.statuses, prev.statuses) || len(f.words) < len(prev.words) {
+		return false
+	}
+	for i, w := range prev.words {
+		if !strings.Contains(f.words[i], w) {
+			return false
+		}
+	}
+	return true
 }
 
 // NewTreeState creates tree state from manifest contexts.
@@ -76,15 +161,22 @@ func NewTreeState(manifest *board.BoardManifest, slices map[string]map[string]an
 					SliceType: entry.Type,
 				}
 
-				// Get devstatus from slice data
+				// Get devstatus and consumed events from slice data
 				if data, ok := slices[entry.File]; ok {
 					if ds, ok := data["devstatus"].(string); ok {
 						sliceNode.DevStatus = ds
 					}
+					sliceNode.Consumes = board.SliceInputs(data)
+				}
+				sliceNode.search = sliceKey{
+					name:      strings.ToLower(sliceNode.Name),
+					sliceType: strings.ToLower(sliceNode.SliceType),
+					devStatus: strings.ToLower(sliceNode.DevStatus),
 				}
 
 				chapNode.Children = append(chapNode.Children, sliceNode)
 				ts.nodeByFlowIndex[idx] = sliceNode
+				ts.sliceNodes = append(ts.sliceNodes, sliceNode)
 			}
 
 			ctxNode.Children = append(ctxNode.Children, chapNode)
@@ -102,17 +194,159 @@ func NewTreeState(manifest *board.BoardManifest, slices map[string]map[string]an
 	return ts
 }
 
-// rebuildFlatView updates FlatView based on current expansion state.
+// rebuildFlatView updates FlatView based on current expansion state and context filter.
+// In flat mode it lists every slice node regardless of expansion.
 func (ts *TreeState) rebuildFlatView() {
-	ts.FlatView = nil
+	ts.FlatView = ts.FlatView[:0]
 	for _, node := range ts.Nodes {
+		if ts.contextFilter != "" && node.Name != ts.contextFilter {
+			continue
+		}
+		if ts.flat {
+			for _, chap := range node.Children {
+				for _, sl := range chap.Children {
+					if ts.hasMatch(sl) {
+						ts.FlatView = append(ts.FlatView, sl)
+					}
+				}
+			}
+			continue
+		}
 		ts.addToFlatView(node)
 	}
 }
 
+// Query returns the search query, or "" when none is set.
+func (ts *TreeState) Query() string {
+	return ts.query
+}
+
+// SetQuery filters the slices shown by a search query: "type:<type>" and
+// "status:<devstatus>" tokens match those columns exactly, the other words
+// must all occur in the slice name (case-insensitively). While a query is
+// set, contexts and chapters are shown expanded, and only when they hold a
+// matching slice.
+func (ts *TreeState) SetQuery(query string) {
+	current := ts.Current()
+	ts.query = query
+	prev := ts.filter
+	ts.filter = parseSliceFilter(query)
+	ts.applyFilter(!prev.empty() && ts.filter.narrows(prev))
+	ts.rebuildFlatView()
+	ts.Cursor = 0
+	if current != nil {
+		ts.moveTo(current)
+	}
+}
+
+// applyFilter recomputes the slices matching the filter, and the nodes
+// holding them. When the filter narrows the previous one, only the slices
+// that matched it are tested again: typing a longer query filters fewer
+// slices at each keystroke.
+func (ts *TreeState) applyFilter(narrowing bool) {
+	if ts.filter.empty() {
+		ts.matching = ts.matching[:0]
+		clear(ts.matched)
+		return
+	}
+	candidates := ts.sliceNodes
+	if narrowing {
+		candidates = ts.matching
+	}
+	// Filtered in place when narrowing: each kept node moves down, if at all
+	matching := ts.matching[:0]
+	for _, node := range candidates {
+		if ts.filter.matches(node) {
+			matching = append(matching, node)
+		}
+	}
+	ts.matching = matching
+
+	if ts.matched == nil {
+		ts.matched = make(map[*TreeNode]bool)
+	}
+	clear(ts.matched)
+	for _, node := range matching {
+		for n := node; n != nil; n = n.Parent {
+			ts.matched[n] = true
+		}
+	}
+}
+
+// hasMatch reports whether node is a matching slice or holds one.
+func (ts *TreeState) hasMatch(node *TreeNode) bool {
+	return ts.filter.empty() || ts.matched[node]
+}
+
+// Flat reports whether the tree is shown as a flat slice list.
+func (ts *TreeState) Flat() bool {
+	return ts.flat
+}
+
+// SetFlat switches between the flat slice list and the hierarchy,
+// keeping the cursor on the selected slice when it is visible in both.
+func (ts *TreeState) SetFlat(flat bool) {
+	current := ts.Current()
+	ts.flat = flat
+	ts.rebuildFlatView()
+	ts.Cursor = 0
+	if current != nil && current.Kind == NodeSlice {
+		// Make the slice visible in the hierarchy
+		for p := current.Parent; p != nil && !flat; p = p.Parent {
+			ts.Expanded[p] = true
+		}
+		ts.rebuildFlatView()
+		ts.moveTo(current)
+	}
+}
+
+// ToggleFlat switches between the flat slice list and the hierarchy.
+func (ts *TreeState) ToggleFlat() {
+	ts.SetFlat(!ts.flat)
+}
+
+// ContextFilter returns the context currently shown, or "" when all are.
+func (ts *TreeState) ContextFilter() string {
+	return ts.contextFilter
+}
+
+// SetContextFilter restricts the tree to the named context ("" shows all).
+// Unknown names clear the filter.
+func (ts *TreeState) SetContextFilter(name string) {
+	ts.contextFilter = ""
+	for _, node := range ts.Nodes {
+		if node.Name == name {
+			ts.contextFilter = name
+		}
+	}
+	ts.rebuildFlatView()
+	ts.Cursor = 0
+}
+
+// CycleContextFilter moves the filter to the next context, wrapping back to all.
+func (ts *TreeState) CycleContextFilter() {
+	next := ""
+	if ts.contextFilter == "" {
+		if len(ts.Nodes) > 0 {
+			next = ts.Nodes[0].Name
+		}
+	} else {
+		for i, node := range ts.Nodes {
+			if node.Name == ts.contextFilter && i+1 < len(ts.Nodes) {
+				next = ts.Nodes[i+1].Name
+			}
+		}
+	}
+	ts.SetContextFilter(next)
+}
+
 func (ts *TreeState) addToFlatView(node *TreeNode) {
+	filtering := !ts.filter.empty()
+	if filtering && !ts.hasMatch(node) {
+		return
+	}
 	ts.FlatView = append(ts.FlatView, node)
-	if ts.Expanded[node] {
+	if ts.Expanded[node] || filtering {
 		for _, child := range node.Children {
 			ts.addToFlatView(child)
 		}
@@ -195,6 +429,27 @@ func (ts *TreeState) moveTo(target *TreeNode) {
 	}
 }
 
+// Select moves the cursor to the named slice, showing it first: its context
+// and chapter are expanded and a filter on another context is cleared.
+// It reports whether the slice exists.
+func (ts *TreeState) Select(name string) bool {
+	for _, node := range ts.nodeByFlowIndex {
+		if node.Name != name {
+			continue
+		}
+		for p := node.Parent; p != nil; p = p.Parent {
+			ts.Expanded[p] = true
+			if p.Parent == nil && ts.contextFilter != "" && ts.contextFilter != p.Name {
+				ts.contextFilter = ""
+			}
+		}
+		ts.rebuildFlatView()
+		ts.moveTo(node)
+		return true
+	}
+	return false
+}
+
 // Current returns the currently selected node, or nil.
 func (ts *TreeState) Current() *TreeNode {
 	if ts.Cursor < 0 || ts.Cursor >= len(ts.FlatView) {
@@ -0,0 +1,145 @@
+package web
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IRHandler serves the IR directory dir. The JSON files (board.json and
+// the slices) get a strong ETag, a hash of their content, so a live
+// preview reloading an unchanged file gets a 304, and are gzipped for
+// clients accepting it. Other files (images) are served as they are.
+func IRHandler(dir string) http.Handler {
+	return &irHandler{
+		root:  http.Dir(dir),
+		files: http.FileServer(http.Dir(dir)),
+		cache: make(map[string]*irFile),
+	}
+}
+
+type irHandler struct {
+	root  http.FileSystem
+	files http.Handler
+
+	mu    sync.Mutex
+	cache map[string]*irFile // by cleaned path
+}
+
+// irFile is a JSON file as served, valid while its size and mtime hold.
+type irFile struct {
+	modTime time.Time
+	size    int64
+	etag    string // of the identity encoding
+	data    []byte
+	gzipped []byte
+}
+
+func (h *irHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := path.Clean("/" + r.URL.Path)
+	if (r.Method != http.MethodGet && r.Method != http.MethodHead) || path.Ext(name) != ".json" {
+		h.files.ServeHTTP(w, r)
+		return
+	}
+	f, err := h.load(name)
+	if err != nil {
+		// Missing files and directories get the file server's answer
+		h.files.ServeHTTP(w, r)
+		return
+	}
+
+	body, etag := f.data, f.etag
+	w.Header().Set("Vary", "Accept-Encoding")
+	if acceptsGzip(r) {
+		// Each encoding is its own representation, with its own tag
+		body, etag = f.gzipped, strings.TrimSuffix(f.etag, `"`)+`-gz"`
+		w.Header().Set("Content-Encoding", "gzip")
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache") // revalidate, see ETag
+	if match := r.Header.Get("If-None-Match"); match != "" && etagMatches(match, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	http.ServeContent(w, r, name, f.modTime, bytes.NewReader(body))
+}
+
+// load returns the file at name, reading and compressing it again only
+// when it changed since the last request.
+func (h *irHandler) load(name string) (*irFile, error) {
+	file, err := h.root.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fs.ErrNotExist
+	}
+
+	h.mu.Lock()
+	cached := h.cache[name]
+	h.mu.Unlock()
+	if cached != nil && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached, nil
+	}
+
+	var data bytes.Buffer
+	if _, err := data.ReadFrom(file); err != nil {
+		return nil, err
+	}
+	var gzipped bytes.Buffer
+	zw := gzip.NewWriter(&gzipped)
+	if _, err := zw.Write(data.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data.Bytes())
+	f := &irFile{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		etag:    `"` + hex.EncodeToString(sum[:16]) + `"`,
+		data:    data.Bytes(),
+		gzipped: gzipped.Bytes(),
+	}
+
+	h.mu.Lock()
+	h.cache[name] = f
+	h.mu.Unlock()
+	return f, nil
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding allows gzip.
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.TrimSpace(coding) == "gzip" {
+			return strings.ReplaceAll(params, " ", "") != "q=0"
+		}
+	}
+	return false
+}
+
+// etagMatches reports whether an If-None-Match header lists etag (or is *).
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -1,14 +1,50 @@
 package eventmodelingspec
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/cuecontext"
 	"cuelang.org/go/cue/load"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/board"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/codegen/golang"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/codegen/gotests"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/codegen/typescript"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/diff"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/export/dot"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/export/jsonschema"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/export/markdown"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/export/mermaid"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/export/openapi"
 	"github.com/err0r500/event-modeling-dcb-spec/pkg/render"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/tui"
+	"github.com/err0r500/event-modeling-dcb-spec/pkg/web"
+	"github.com/mattn/go-runewidth"
+	"go.yaml.in/yaml/v3"
 )
 
 func TestValidBoard(t *testing.T) {
@@ -307,31 +343,53 @@ board: em.#Board & {
 	assertInvalid(t, src, "_actorValid")
 }
 
-func TestValidFutureEventInGWT(t *testing.T) {
+func TestInvalidStoryActorNotDefined(t *testing.T) {
 	src := `
 package test
 
 import "github.com/err0r500/event-modeling-dcb-spec/em"
 
-gwt: em.#GWT & {
-	name: "Valid GWT with future event"
-	given: [{
-		eventType: "SomeEvent"
-		fields: {}
-		tags: []
-		fromFuture: true  // allowed in both success and error scenarios
-	}]
-	when: {}
-	then: {
-		success: false
-		error: "Expected error"
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		TestEvent: {eventType: "TestEvent", fields: {}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
 	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				_slice,
+				{
+					kind: "story"
+					name: "admin steps in"
+					slice: _slice
+					actor: {name: "Admin"} // Admin not defined in actors
+				},
+			]
+		}]
+	}]
+}
+
+_slice: {
+	kind: "slice"
+	name: "TestSlice"
+	type: "change"
+	actor: {name: "User"}
+	trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/test"}}
+	command: {name: "TestCmd", fields: {}, query: {items: []}}
+	emits: [board.events.TestEvent]
+	scenarios: []
 }
 `
-	assertValid(t, src)
+	assertInvalidGo(t, src, "E501", `story "admin steps in" actor "Admin"`)
 }
 
-func TestValidFieldsFromEndpoint(t *testing.T) {
+func TestInvalidDuplicateEndpointRoute(t *testing.T) {
 	src := `
 package test
 
@@ -341,7 +399,7 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		PaymentMade: {eventType: "PaymentMade", fields: {userId: string, amount: int}, tags: []}
+		UserRenamed: {eventType: "UserRenamed", fields: {}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
@@ -352,34 +410,31 @@ board: em.#Board & {
 			name: "Main"
 			flow: [{
 				kind: "slice"
-				name: "TestSlice"
+				name: "RenameUser"
 				type: "change"
 				actor: {name: "User"}
-				trigger: {kind: "endpoint", endpoint: {
-					verb: "POST"
-					params: {userId: string}
-					body: {amount: int}
-					path: "/users/{userId}/pay"
-				}}
-				command: {
-					name: "Pay"
-					fields: {
-						userId: string
-						amount: int
-					}
-					query: {items: []}
-				}
-				emits: [events.PaymentMade]
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {id: string}, body: {}, path: "/users/{id}"}}
+				command: {name: "RenameUser", fields: {}, query: {items: []}}
+				emits: [events.UserRenamed]
+				scenarios: []
+			}, {
+				kind: "slice"
+				name: "RetitleUser"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/users/{userId}"}}
+				command: {name: "RetitleUser", fields: {}, query: {items: []}}
+				emits: [events.UserRenamed]
 				scenarios: []
 			}]
 		}]
 	}]
 }
 `
-	assertValid(t, src)
+	assertInvalidGo(t, src, "E114", `slice "RetitleUser" endpoint POST /users/{userId} conflicts with slice "RenameUser"`)
 }
 
-func TestValidViewReadModelFromEvents(t *testing.T) {
+func TestInvalidDuplicateSliceName(t *testing.T) {
 	src := `
 package test
 
@@ -389,7 +444,7 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		EventA: {eventType: "EventA", fields: {userId: string, amount: int}, tags: []}
+		OrderPlaced: {eventType: "OrderPlaced", fields: {}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
@@ -398,41 +453,34 @@ board: em.#Board & {
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [
-				{
-					kind: "slice"
-					name: "Emit"
-					type: "change"
-					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {amount: int}, path: "/test"}}
-					command: {name: "Cmd", fields: {userId: string, amount: int}, query: {items: []}}
-					emits: [events.EventA]
-					scenarios: []
-				},
-				{
-					kind: "slice"
-					name: "ReadA"
-					type: "view"
-					actor: {name: "User"}
-					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
-					readModel: {
-						name: "ViewA"
-						cardinality: "single"
-						fields: {userId: string, amount: int}
-					}
-					query: {items: [{types: [events.EventA], tags: []}]}
-					scenarios: []
-				},
-			]
+			flow: [{
+				kind: "slice"
+				name: "PlaceOrder"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/orders"}}
+				command: {name: "PlaceOrder", fields: {}, query: {items: []}}
+				emits: [events.OrderPlaced]
+				scenarios: []
+			}, {
+				kind: "slice"
+				name: "PlaceOrder"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "PUT", params: {}, body: {}, path: "/orders"}}
+				command: {name: "PlaceOrder", fields: {}, query: {items: []}}
+				emits: [events.OrderPlaced]
+				scenarios: []
+			}]
 		}]
 	}]
 }
 `
-	assertValid(t, src)
+	assertInvalidGo(t, src, "E106", `slice "PlaceOrder" at flow index 1 has the same name as the slice at flow index 0`)
 }
 
-func TestInvalidViewReadModelFieldNotFromEvents(t *testing.T) {
-	src := `
+func TestInvalidEndpointVerb(t *testing.T) {
+	const src = `
 package test
 
 import "github.com/err0r500/event-modeling-dcb-spec/em"
@@ -441,50 +489,76 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
-	}
-	actors: {
-		User: {name: "User"}
+		EventA: {eventType: "EventA", fields: {}, tags: []}
 	}
+	actors: {User: {name: "User"}}
 	contexts: [{
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [
-				{
-					kind: "slice"
-					name: "Emit"
-					type: "change"
-					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/test"}}
-					command: {name: "Cmd", fields: {userId: string}, query: {items: []}}
-					emits: [events.EventA]
-					scenarios: []
-				},
-				{
-					kind: "slice"
-					name: "ReadA"
-					type: "view"
-					actor: {name: "User"}
-					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
-					readModel: {
-						name: "ViewA"
-						cardinality: "single"
-						fields: {userId: string, bogusField: string}
-					}
-					query: {items: [{types: [events.EventA], tags: []}]}
-					scenarios: []
-				},
-			]
+			flow: [{
+				kind: "slice"
+				name: "Emit"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "CHANGE_VERB", params: {}, body: {}, path: "/test"}}
+				command: {name: "Cmd", fields: {}, query: {items: []}}
+				emits: [events.EventA]
+				scenarios: []
+			}, {
+				kind: "slice"
+				name: "ReadA"
+				type: "view"
+				actor: {name: "User"}
+				endpoint: {verb: "VIEW_VERB", params: {}, body: {}, path: "/test"}
+				readModel: {name: "ViewA", cardinality: "single", fields: {}}
+				query: {items: [{types: [events.EventA], tags: []}]}
+				scenarios: []
+			}]
 		}]
 	}]
 }
 `
-	assertInvalid(t, src, "view_ReadA_field_bogusField_must_come_from_events_or_computed")
+	// #HTTPVerb rejects both, uppercase only. The failed slice disjunction
+	// is reported as one positioned E107 per slice, without the mismatches
+	// of the other slice and trigger kinds.
+	const changeErr = `slice at flow index 0: endpoint verb "POSTT" must be one of GET, POST, PUT, PATCH, DELETE`
+	const viewErr = `slice at flow index 1: endpoint verb "get" must be one of GET, POST, PUT, PATCH, DELETE`
+	for _, tt := range []struct {
+		changeVerb, viewVerb string
+		want                 []string
+	}{
+		{"POSTT", "GET", []string{changeErr}},
+		{"POST", "get", []string{viewErr}},
+		{"POSTT", "get", []string{changeErr, viewErr}},
+	} {
+		boardSrc := strings.NewReplacer("CHANGE_VERB", tt.changeVerb, "VIEW_VERB", tt.viewVerb).Replace(src)
+		_, _, err := board.LoadBoardFromSource(boardSrc, "")
+		if err == nil {
+			t.Fatalf("verbs %s/%s: expected a build error", tt.changeVerb, tt.viewVerb)
+		}
+		lines := strings.Split(strings.TrimPrefix(err.Error(), "build: "), "\n")
+		if len(lines) != len(tt.want) {
+			t.Errorf("verbs %s/%s: error = %q, want %d diagnostics", tt.changeVerb, tt.viewVerb, err, len(tt.want))
+			continue
+		}
+		for i, line := range lines {
+			d := render.ParseValidationError(line)
+			if d.Code != render.ErrEndpointVerb || d.Message != tt.want[i] || d.File == "" {
+				t.Errorf("verbs %s/%s: diagnostic %q, want a positioned E107 %q", tt.changeVerb, tt.viewVerb, line, tt.want[i])
+			}
+		}
+	}
 }
 
-func TestValidViewReadModelWithComputed(t *testing.T) {
-	src := `
+func TestInvalidEndpointPath(t *testing.T) {
+	for path, problem := range map[string]string{
+		"users/{id}": "must start with /",
+		"/users/{id": "has an unbalanced {",
+		"/users/{}":  "has an empty {} param",
+	} {
+		t.Run(path, func(t *testing.T) {
+			src := fmt.Sprintf(`
 package test
 
 import "github.com/err0r500/event-modeling-dcb-spec/em"
@@ -493,51 +567,35 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		EventA: {eventType: "EventA", fields: {userId: string, amount: int}, tags: []}
-	}
-	actors: {
-		User: {name: "User"}
+		EventA: {eventType: "EventA", fields: {}, tags: []}
 	}
+	actors: {User: {name: "User"}}
 	contexts: [{
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [
-				{
-					kind: "slice"
-					name: "Emit"
-					type: "change"
-					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {amount: int}, path: "/test"}}
-					command: {name: "Cmd", fields: {userId: string, amount: int}, query: {items: []}}
-					emits: [events.EventA]
-					scenarios: []
-				},
-				{
-					kind: "slice"
-					name: "ReadA"
-					type: "view"
-					actor: {name: "User"}
-					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
-					readModel: {
-						name: "ViewA"
-						cardinality: "single"
-						fields: {userId: string, totalSpent: int}
-						computed: {totalSpent: {event: events.EventA, fields: ["amount"]}}
-					}
-					query: {items: [{types: [events.EventA], tags: []}]}
-					scenarios: []
-				},
-			]
+			flow: [{
+				kind: "slice"
+				name: "Emit"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {id: string}, body: {}, path: %q}}
+				command: {name: "Cmd", fields: {}, query: {items: []}}
+				emits: [events.EventA]
+				scenarios: []
+			}]
 		}]
 	}]
 }
-`
-	assertValid(t, src)
+`, path)
+			assertInvalidGo(t, src, "E108", fmt.Sprintf("slice %q endpoint path %q %s", "Emit", path, problem))
+		})
+	}
 }
 
-func TestInvalidComputedFieldNotInEvent(t *testing.T) {
-	src := `
+func TestInvalidScenarioWhen(t *testing.T) {
+	srcWith := func(when string) string {
+		return `
 package test
 
 import "github.com/err0r500/event-modeling-dcb-spec/em"
@@ -546,51 +604,47 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
-	}
-	actors: {
-		User: {name: "User"}
+		EventA: {eventType: "EventA", fields: {qty: int}, tags: []}
 	}
+	actors: {User: {name: "User"}}
 	contexts: [{
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [
-				{
-					kind: "slice"
-					name: "Emit"
-					type: "change"
-					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/test"}}
-					command: {name: "Cmd", fields: {userId: string}, query: {items: []}}
-					emits: [events.EventA]
-					scenarios: []
-				},
-				{
-					kind: "slice"
-					name: "ReadA"
-					type: "view"
-					actor: {name: "User"}
-					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
-					readModel: {
-						name: "ViewA"
-						cardinality: "single"
-						fields: {userId: string, total: int}
-						computed: {total: {event: events.EventA, fields: ["bogusField"]}}
-					}
-					query: {items: [{types: [events.EventA], tags: []}]}
-					scenarios: []
-				},
-			]
+			flow: [{
+				kind: "slice"
+				name: "Emit"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {qty: int}, path: "/test"}}
+				command: {name: "Cmd", fields: {qty: int}, query: {items: []}}
+				emits: [events.EventA]
+				scenarios: [{name: "s", given: [], when: ` + when + `, then: {success: false, error: "e"}}]
+			}]
 		}]
 	}]
 }
 `
-	assertInvalid(t, src, "view_ReadA_computed_total_field_bogusField_must_exist_in_event")
+	}
+
+	// The schema unifies when with the command fields: a wrong type fails
+	// the build with one positioned E405, not the slice kind mismatches
+	_, _, err := board.LoadBoardFromSource(srcWith(`{qty: "x"}`), "")
+	if err == nil {
+		t.Fatal("expected the when type to fail the build")
+	}
+	d := render.ParseValidationError(strings.TrimPrefix(err.Error(), "build: "))
+	if want := `slice at flow index 0 scenario 0 when: field "qty" must be int, got "x"`; d.Code != render.ErrScenarioWhen || d.Message != want || d.File == "" {
+		t.Errorf("error = %q, want a positioned E405 %q", err, want)
+	}
+
+	// Command fields are open, so extra keys are checked in Go
+	assertInvalidGo(t, srcWith(`{qty: 1, bogus: "y"}`), "E405", `slice "Emit" scenario 0 when: "bogus" is not a command field`)
 }
 
-func TestInvalidComputedEventNotQueried(t *testing.T) {
-	src := `
+func TestScenarioThenValues(t *testing.T) {
+	board := func(amount string) string {
+		return `
 package test
 
 import "github.com/err0r500/event-modeling-dcb-spec/em"
@@ -599,62 +653,69 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
-		EventB: {eventType: "EventB", fields: {amount: int}, tags: []}
-	}
-	actors: {
-		User: {name: "User"}
+		PaymentMade: {eventType: "PaymentMade", fields: {amount: int}, tags: []}
 	}
+	actors: {User: {name: "User"}}
 	contexts: [{
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [
-				{
-					kind: "slice"
-					name: "EmitA"
-					type: "change"
-					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/a"}}
-					command: {name: "CmdA", fields: {userId: string}, query: {items: []}}
-					emits: [events.EventA]
-					scenarios: []
-				},
-				{
-					kind: "slice"
-					name: "EmitB"
-					type: "change"
-					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {amount: int}, path: "/b"}}
-					command: {name: "CmdB", fields: {amount: int}, query: {items: []}}
-					emits: [events.EventB]
-					scenarios: []
-				},
-				{
-					kind: "slice"
-					name: "ReadA"
-					type: "view"
-					actor: {name: "User"}
-					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
-					readModel: {
-						name: "ViewA"
-						cardinality: "single"
-						fields: {userId: string, total: int}
-						// EventB is NOT in query — only EventA is queried
-						computed: {total: {event: events.EventB, fields: ["amount"]}}
-					}
-					query: {items: [{types: [events.EventA], tags: []}]}
-					scenarios: []
-				},
-			]
+			flow: [{
+				kind: "slice"
+				name: "Pay"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {amount: int}, path: "/pay"}}
+				command: {name: "Pay", fields: {amount: int}, query: {items: []}}
+				emits: [events.PaymentMade]
+				scenarios: [{
+					name: "pays"
+					given: []
+					when: {amount: 10}
+					then: {success: true, events: [{eventType: "PaymentMade", fields: {amount: ` + amount + `}, tags: []}]}
+				}]
+			}]
 		}]
 	}]
 }
 `
-	assertInvalid(t, src, "view_ReadA_computed_total_event_must_be_queried")
+	}
+
+	assertValid(t, board("10"))
+	res := buildValue(t, board("10"))
+	for _, e := range render.ValidateBoard(res.value.LookupPath(cue.ParsePath("board"))) {
+		if render.DiagnosticCode(e) == "E406" {
+			t.Errorf("unexpected %s", e)
+		}
+	}
+	assertInvalidGo(t, board(`"ten"`), "E406", `slice "Pay" scenario 0 then: event "PaymentMade" field "amount" must be int, got "ten"`)
 }
 
-func TestValidViewMappingTypeMatch(t *testing.T) {
+func TestValidFutureEventInGWT(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+gwt: em.#GWT & {
+	name: "Valid GWT with future event"
+	given: [{
+		eventType: "SomeEvent"
+		fields: {}
+		tags: []
+		fromFuture: true  // allowed in both success and error scenarios
+	}]
+	when: {}
+	then: {
+		success: false
+		error: "Expected error"
+	}
+}
+`
+	assertValid(t, src)
+}
+
+func TestValidFieldsFromEndpoint(t *testing.T) {
 	src := `
 package test
 
@@ -664,7 +725,7 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		EventA: {eventType: "EventA", fields: {userId: string, amount: int}, tags: []}
+		PaymentMade: {eventType: "PaymentMade", fields: {userId: string, amount: int}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
@@ -673,33 +734,28 @@ board: em.#Board & {
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [
-				{
-					kind: "slice"
-					name: "Emit"
-					type: "change"
-					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {amount: int}, path: "/test"}}
-					command: {name: "Cmd", fields: {userId: string, amount: int}, query: {items: []}}
-					emits: [events.EventA]
-					scenarios: []
-				},
-				{
-					kind: "slice"
-					name: "ReadA"
-					type: "view"
-					actor: {name: "User"}
-					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
-					readModel: {
-						name: "ViewA"
-						cardinality: "single"
-						fields: {userId: string, totalPrice: int}
-						mapping: {totalPrice: {event: events.EventA, field: "amount"}}
+			flow: [{
+				kind: "slice"
+				name: "TestSlice"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {
+					verb: "POST"
+					params: {userId: string}
+					body: {amount: int}
+					path: "/users/{userId}/pay"
+				}}
+				command: {
+					name: "Pay"
+					fields: {
+						userId: string
+						amount: int
 					}
-					query: {items: [{types: [events.EventA], tags: []}]}
-					scenarios: []
-				},
-			]
+					query: {items: []}
+				}
+				emits: [events.PaymentMade]
+				scenarios: []
+			}]
 		}]
 	}]
 }
@@ -707,7 +763,7 @@ board: em.#Board & {
 	assertValid(t, src)
 }
 
-func TestInvalidViewMappingTypeMismatch(t *testing.T) {
+func TestValidFieldsFromUITrigger(t *testing.T) {
 	src := `
 package test
 
@@ -717,7 +773,7 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		EventA: {eventType: "EventA", fields: {userId: string, amount: int}, tags: []}
+		ItemStarred: {eventType: "ItemStarred", fields: {itemId: string, note: string}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
@@ -726,42 +782,34 @@ board: em.#Board & {
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [
-				{
-					kind: "slice"
-					name: "Emit"
-					type: "change"
-					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {amount: int}, path: "/test"}}
-					command: {name: "Cmd", fields: {userId: string, amount: int}, query: {items: []}}
-					emits: [events.EventA]
-					scenarios: []
-				},
-				{
-					kind: "slice"
-					name: "ReadA"
-					type: "view"
-					actor: {name: "User"}
-					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
-					readModel: {
-						name: "ViewA"
-						cardinality: "single"
-						fields: {userId: string, totalPrice: string}
-						// totalPrice is string but amount is int — type mismatch
-						mapping: {totalPrice: {event: events.EventA, field: "amount"}}
+			flow: [{
+				kind: "slice"
+				name: "StarItem"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "ui", ui: {
+					name: "ClickStar"
+					fields: {itemId: string, note: string}
+				}}
+				command: {
+					name: "StarItem"
+					fields: {
+						itemId: string
+						note:   string
 					}
-					query: {items: [{types: [events.EventA], tags: []}]}
-					scenarios: []
-				},
-			]
+					query: {items: []}
+				}
+				emits: [events.ItemStarred]
+				scenarios: []
+			}]
 		}]
 	}]
 }
 `
-	assertInvalid(t, src, "view_ReadA_mapping_totalPrice_type")
+	assertValid(t, src)
 }
 
-func TestInvalidViewMappingFieldNotInEvent(t *testing.T) {
+func TestInvalidFieldNotFromUITrigger(t *testing.T) {
 	src := `
 package test
 
@@ -771,7 +819,7 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
+		ItemStarred: {eventType: "ItemStarred", fields: {itemId: string, note: string}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
@@ -780,41 +828,34 @@ board: em.#Board & {
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [
-				{
-					kind: "slice"
-					name: "Emit"
-					type: "change"
-					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/test"}}
-					command: {name: "Cmd", fields: {userId: string}, query: {items: []}}
-					emits: [events.EventA]
-					scenarios: []
-				},
-				{
-					kind: "slice"
-					name: "ReadA"
-					type: "view"
-					actor: {name: "User"}
-					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
-					readModel: {
-						name: "ViewA"
-						cardinality: "single"
-						fields: {userId: string, total: int}
-						mapping: {total: {event: events.EventA, field: "noSuchField"}}
+			flow: [{
+				kind: "slice"
+				name: "StarItem"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "ui", ui: {
+					name: "ClickStar"
+					fields: {itemId: string}
+				}}
+				command: {
+					name: "StarItem"
+					fields: {
+						itemId: string
+						note:   string
 					}
-					query: {items: [{types: [events.EventA], tags: []}]}
-					scenarios: []
-				},
-			]
+					query: {items: []}
+				}
+				emits: [events.ItemStarred]
+				scenarios: []
+			}]
 		}]
 	}]
 }
 `
-	assertInvalid(t, src, "view_ReadA_mapping_total_field_noSuchField_must_exist_in_event")
+	assertInvalid(t, src, "slice_StarItem_field_note_must_come_from_trigger")
 }
 
-func TestValidDottedPathMapping(t *testing.T) {
+func TestValidViewReadModelFromEvents(t *testing.T) {
 	src := `
 package test
 
@@ -824,7 +865,7 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		ItemAdded: {eventType: "ItemAdded", fields: {itemId: string, price: int}, tags: []}
+		EventA: {eventType: "EventA", fields: {userId: string, amount: int}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
@@ -839,29 +880,23 @@ board: em.#Board & {
 					name: "Emit"
 					type: "change"
 					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {itemId: string, price: int}, path: "/items"}}
-					command: {name: "AddItem", fields: {itemId: string, price: int}, query: {items: []}}
-					emits: [events.ItemAdded]
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {amount: int}, path: "/test"}}
+					command: {name: "Cmd", fields: {userId: string, amount: int}, query: {items: []}}
+					emits: [events.EventA]
 					scenarios: []
 				},
 				{
 					kind: "slice"
-					name: "ViewItems"
+					name: "ReadA"
 					type: "view"
 					actor: {name: "User"}
-					endpoint: {verb: "GET", params: {}, body: {}, path: "/items"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
 					readModel: {
-						name: "ItemsView"
+						name: "ViewA"
 						cardinality: "single"
-						fields: {
-							items: [{itemId: string, price: int}]
-						}
-						mapping: {
-							"items.itemId": {event: events.ItemAdded, field: "itemId"}
-							"items.price": {event: events.ItemAdded, field: "price"}
-						}
+						fields: {userId: string, amount: int}
 					}
-					query: {items: [{types: [events.ItemAdded], tags: []}]}
+					query: {items: [{types: [events.EventA], tags: []}]}
 					scenarios: []
 				},
 			]
@@ -872,7 +907,7 @@ board: em.#Board & {
 	assertValid(t, src)
 }
 
-func TestInvalidDottedPathMapping(t *testing.T) {
+func TestInvalidQueriedEventNeverEmitted(t *testing.T) {
 	src := `
 package test
 
@@ -882,7 +917,8 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		ItemAdded: {eventType: "ItemAdded", fields: {itemId: string, price: int}, tags: []}
+		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
+		EventB: {eventType: "EventB", fields: {userId: string}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
@@ -897,28 +933,23 @@ board: em.#Board & {
 					name: "Emit"
 					type: "change"
 					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {itemId: string, price: int}, path: "/items"}}
-					command: {name: "AddItem", fields: {itemId: string, price: int}, query: {items: []}}
-					emits: [events.ItemAdded]
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/test"}}
+					command: {name: "Cmd", fields: {userId: string}, query: {items: []}}
+					emits: [events.EventA]
 					scenarios: []
 				},
 				{
 					kind: "slice"
-					name: "ViewItems"
+					name: "ReadA"
 					type: "view"
 					actor: {name: "User"}
-					endpoint: {verb: "GET", params: {}, body: {}, path: "/items"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
 					readModel: {
-						name: "ItemsView"
+						name: "ViewA"
 						cardinality: "single"
-						fields: {
-							items: [{itemId: string, price: int}]
-						}
-						mapping: {
-							"items.nonexistent": {event: events.ItemAdded, field: "itemId"}
-						}
+						fields: {userId: string}
 					}
-					query: {items: [{types: [events.ItemAdded], tags: []}]}
+					query: {items: [{types: [events.EventA, events.EventB], tags: []}]}
 					scenarios: []
 				},
 			]
@@ -926,10 +957,10 @@ board: em.#Board & {
 	}]
 }
 `
-	assertInvalidGo(t, src, "items.nonexistent", "must resolve to a field")
+	assertInvalidGo(t, src, "E320", `slice "ReadA" queries event "EventB", which no slice emits`)
 }
 
-func TestInvalidDottedPathTypeMismatch(t *testing.T) {
+func TestInvalidUnusedEvent(t *testing.T) {
 	src := `
 package test
 
@@ -939,7 +970,9 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		ItemAdded: {eventType: "ItemAdded", fields: {itemId: string, price: int}, tags: []}
+		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
+		EventB: {eventType: "EventB", fields: {userId: string}, tags: []}
+		EventC: {eventType: "EventC", fields: {userId: string}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
@@ -948,35 +981,93 @@ board: em.#Board & {
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [
+			flow: [{
+				kind: "slice"
+				name: "Emit"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/test"}}
+				command: {name: "Cmd", fields: {userId: string}, query: {items: [{types: [events.EventC], tags: []}]}}
+				emits: [events.EventA]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+	res := buildValue(t, src)
+	if res.err != nil {
+		t.Fatalf("build: %v", res.err)
+	}
+	errs := render.ValidateBoard(res.value.LookupPath(cue.ParsePath("board")))
+	var unused []string
+	for _, e := range errs {
+		if render.DiagnosticCode(e) == "E321" {
+			unused = append(unused, e)
+		}
+	}
+	if len(unused) != 1 || !strings.Contains(unused[0], `event "EventB" is declared but never emitted`) {
+		t.Errorf("expected only EventB reported unused, got %v", unused)
+	}
+}
+
+func TestValidReadModelReference(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		EventA: {eventType: "EventA", fields: {userId: string, amount: int}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
 				{
 					kind: "slice"
 					name: "Emit"
 					type: "change"
 					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {itemId: string, price: int}, path: "/items"}}
-					command: {name: "AddItem", fields: {itemId: string, price: int}, query: {items: []}}
-					emits: [events.ItemAdded]
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {amount: int}, path: "/test"}}
+					command: {name: "Cmd", fields: {userId: string, amount: int}, query: {items: []}}
+					emits: [events.EventA]
 					scenarios: []
 				},
 				{
 					kind: "slice"
-					name: "ViewItems"
+					name: "ReadUser"
 					type: "view"
 					actor: {name: "User"}
-					endpoint: {verb: "GET", params: {}, body: {}, path: "/items"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/users"}
 					readModel: {
-						name: "ItemsView"
+						name: "UserView"
 						cardinality: "single"
-						fields: {
-							items: [{itemId: string, price: int}]
-						}
-						mapping: {
-							// itemId is string but price is int - type mismatch
-							"items.itemId": {event: events.ItemAdded, field: "price"}
-						}
+						fields: {userId: string}
 					}
-					query: {items: [{types: [events.ItemAdded], tags: []}]}
+					query: {items: [{types: [events.EventA], tags: []}]}
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "ReadAmount"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/amounts"}
+					readModel: {
+						name: "AmountView"
+						cardinality: "single"
+						fields: {userId: string, amount: int}
+						references: {userId: "UserView"}
+					}
+					query: {items: [{types: [events.EventA], tags: []}]}
 					scenarios: []
 				},
 			]
@@ -984,10 +1075,17 @@ board: em.#Board & {
 	}]
 }
 `
-	assertInvalidGo(t, src, "items.itemId", "must match source event field type")
+	assertValid(t, src)
+
+	res := buildValue(t, src)
+	for _, e := range render.ValidateBoard(res.value.LookupPath(cue.ParsePath("board"))) {
+		if strings.HasPrefix(e, render.ErrReadModelRef) {
+			t.Errorf("unexpected reference error: %s", e)
+		}
+	}
 }
 
-func TestValidFieldsFromAuth(t *testing.T) {
+func TestInvalidReadModelReferenceDangling(t *testing.T) {
 	src := `
 package test
 
@@ -997,7 +1095,7 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		OrderPlaced: {eventType: "OrderPlaced", fields: {userId: string, amount: int}, tags: []}
+		EventA: {eventType: "EventA", fields: {userId: string, amount: int}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
@@ -1006,37 +1104,55 @@ board: em.#Board & {
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [{
-				kind: "slice"
-				name: "PlaceOrder"
-				type: "change"
-				actor: {name: "User"}
-				trigger: {kind: "endpoint", endpoint: {
-					verb: "POST"
-					params: {}
-					body: {amount: int}
-					auth: {userId: string}
-					path: "/orders"
-				}}
-				command: {
-					name: "PlaceOrder"
-					fields: {
-						userId: string
-						amount: int
+			flow: [
+				{
+					kind: "slice"
+					name: "Emit"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {amount: int}, path: "/test"}}
+					command: {name: "Cmd", fields: {userId: string, amount: int}, query: {items: []}}
+					emits: [events.EventA]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "ReadUser"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/users"}
+					readModel: {
+						name: "UserView"
+						cardinality: "single"
+						fields: {userId: string}
 					}
-					query: {items: []}
-				}
-				emits: [events.OrderPlaced]
-				scenarios: []
-			}]
+					query: {items: [{types: [events.EventA], tags: []}]}
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "ReadAmount"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/amounts"}
+					readModel: {
+						name: "AmountView"
+						cardinality: "single"
+						fields: {userId: string, amount: int}
+						references: {userId: "CustomerView"}
+					}
+					query: {items: [{types: [events.EventA], tags: []}]}
+					scenarios: []
+				},
+			]
 		}]
 	}]
 }
 `
-	assertValid(t, src)
+	assertInvalidGo(t, src, "E214", `read model "AmountView" field "userId" references undefined read model "CustomerView"`)
 }
 
-func TestInvalidFieldFromAuthTypeMismatch(t *testing.T) {
+func TestInvalidViewReadModelFieldNotFromEvents(t *testing.T) {
 	src := `
 package test
 
@@ -1046,7 +1162,7 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		OrderPlaced: {eventType: "OrderPlaced", fields: {userId: string}, tags: []}
+		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
@@ -1055,36 +1171,40 @@ board: em.#Board & {
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [{
-				kind: "slice"
-				name: "PlaceOrder"
-				type: "change"
-				actor: {name: "User"}
-				trigger: {kind: "endpoint", endpoint: {
-					verb: "POST"
-					params: {}
-					body: {}
-					auth: {userId: int}  // int, but command expects string
-					path: "/orders"
-				}}
-				command: {
-					name: "PlaceOrder"
-					fields: {
-						userId: string
+			flow: [
+				{
+					kind: "slice"
+					name: "Emit"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/test"}}
+					command: {name: "Cmd", fields: {userId: string}, query: {items: []}}
+					emits: [events.EventA]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "ReadA"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
+					readModel: {
+						name: "ViewA"
+						cardinality: "single"
+						fields: {userId: string, bogusField: string}
 					}
-					query: {items: []}
-				}
-				emits: [events.OrderPlaced]
-				scenarios: []
-			}]
+					query: {items: [{types: [events.EventA], tags: []}]}
+					scenarios: []
+				},
+			]
 		}]
 	}]
 }
 `
-	assertInvalid(t, src, "slice_PlaceOrder_field_userId_type")
+	assertInvalid(t, src, "view_ReadA_field_bogusField_must_come_from_events_or_computed")
 }
 
-func TestInvalidFieldNotInAnyEndpointSource(t *testing.T) {
+func TestValidViewReadModelWithComputed(t *testing.T) {
 	src := `
 package test
 
@@ -1094,7 +1214,7 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		OrderPlaced: {eventType: "OrderPlaced", fields: {userId: string}, tags: []}
+		EventA: {eventType: "EventA", fields: {userId: string, amount: int}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
@@ -1103,149 +1223,94 @@ board: em.#Board & {
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [{
-				kind: "slice"
-				name: "PlaceOrder"
-				type: "change"
-				actor: {name: "User"}
-				trigger: {kind: "endpoint", endpoint: {
-					verb: "POST"
-					params: {}
-					body: {}
-					auth: {}
-					path: "/orders"
-				}}
-				command: {
-					name: "PlaceOrder"
-					fields: {
-						userId: string  // not in params, body, or auth
+			flow: [
+				{
+					kind: "slice"
+					name: "Emit"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {amount: int}, path: "/test"}}
+					command: {name: "Cmd", fields: {userId: string, amount: int}, query: {items: []}}
+					emits: [events.EventA]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "ReadA"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
+					readModel: {
+						name: "ViewA"
+						cardinality: "single"
+						fields: {userId: string, totalSpent: int}
+						computed: {totalSpent: {event: events.EventA, fields: ["amount"]}}
 					}
-					query: {items: []}
-				}
-				emits: [events.OrderPlaced]
-				scenarios: []
-			}]
+					query: {items: [{types: [events.EventA], tags: []}]}
+					scenarios: []
+				},
+			]
 		}]
 	}]
 }
 `
-	assertInvalid(t, src, "slice_PlaceOrder_field_userId_must_come_from_trigger")
+	assertValid(t, src)
 }
 
-// Helper functions
-
-// buildResult holds the CUE value and any errors from loading/building
-type buildResult struct {
-	value cue.Value
-	err   error
-}
+func TestInvalidViewReadModelOpenFieldType(t *testing.T) {
+	src := `
+package test
 
-func buildValue(t *testing.T, src string) buildResult {
-	t.Helper()
+import "github.com/err0r500/event-modeling-dcb-spec/em"
 
-	absDir, err := filepath.Abs(".")
-	if err != nil {
-		return buildResult{err: err}
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		EventA: {eventType: "EventA", fields: {userId: string, amount: int}, tags: []}
 	}
-
-	overlay := map[string]load.Source{
-		filepath.Join(absDir, "test_input.cue"): load.FromString(src),
-	}
-
-	cfg := &load.Config{
-		Dir:     absDir,
-		Overlay: overlay,
-	}
-
-	instances := load.Instances([]string{"./test_input.cue"}, cfg)
-	if len(instances) == 0 {
-		return buildResult{err: err}
-	}
-
-	inst := instances[0]
-	if inst.Err != nil {
-		return buildResult{err: inst.Err}
-	}
-
-	ctx := cuecontext.New()
-	v := ctx.BuildInstance(inst)
-	if v.Err() != nil {
-		return buildResult{err: v.Err()}
-	}
-
-	return buildResult{value: v}
-}
-
-func assertValid(t *testing.T, src string) {
-	t.Helper()
-	res := buildValue(t, src)
-	if res.err != nil {
-		t.Errorf("expected valid, got build error: %v", res.err)
-		return
-	}
-	if err := res.value.Validate(cue.Concrete(false)); err != nil {
-		t.Errorf("expected valid, got validation error: %v", err)
-	}
-}
-
-func assertInvalid(t *testing.T, src string, expectedErrContains string) {
-	t.Helper()
-	res := buildValue(t, src)
-
-	// Check build error first
-	if res.err != nil {
-		if !strings.Contains(res.err.Error(), expectedErrContains) {
-			t.Errorf("expected error containing %q, got build error: %v", expectedErrContains, res.err)
-		}
-		return
-	}
-
-	// Check validation error
-	err := res.value.Validate(cue.Concrete(false))
-	if err == nil {
-		t.Errorf("expected invalid (containing %q), but validation passed", expectedErrContains)
-		return
-	}
-	if !strings.Contains(err.Error(), expectedErrContains) {
-		t.Errorf("expected error containing %q, got validation error: %v", expectedErrContains, err)
+	actors: {
+		User: {name: "User"}
 	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "Emit"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {amount: int}, path: "/test"}}
+					command: {name: "Cmd", fields: {userId: string, amount: int}, query: {items: []}}
+					emits: [events.EventA]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "ReadA"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
+					readModel: {
+						name: "ViewA"
+						cardinality: "single"
+						fields: {userId: string, totalSpent: int, meta: {...}}
+						computed: {totalSpent: {event: events.EventA, fields: ["amount"]}, meta: {event: events.EventA, fields: ["amount"]}}
+					}
+					query: {items: [{types: [events.EventA], tags: []}]}
+					scenarios: []
+				},
+			]
+		}]
+	}]
 }
-
-func assertInvalidGo(t *testing.T, src string, pathContains string, errContains string) {
-	t.Helper()
-	res := buildValue(t, src)
-	if res.err != nil {
-		t.Errorf("expected valid CUE build, got build error: %v", res.err)
-		return
-	}
-
-	// CUE validation should pass (dotted paths not checked by CUE)
-	if err := res.value.Validate(cue.Concrete(false)); err != nil {
-		t.Errorf("expected CUE validation to pass, got: %v", err)
-		return
-	}
-
-	// Go validation should catch it
-	board := res.value.LookupPath(cue.ParsePath("board"))
-	errs := render.ValidateBoard(board)
-	if len(errs) == 0 {
-		t.Errorf("expected Go validation error containing %q and %q, but none found", pathContains, errContains)
-		return
-	}
-
-	found := false
-	for _, e := range errs {
-		if strings.Contains(e, pathContains) && strings.Contains(e, errContains) {
-			found = true
-			break
-		}
-	}
-	if !found {
-		t.Errorf("expected error containing %q and %q, got: %v", pathContains, errContains, errs)
-	}
+`
+	assertInvalidGo(t, src, "meta", "E213")
 }
 
-func TestValidPathParamInParams(t *testing.T) {
+func TestInvalidComputedFieldNotInEvent(t *testing.T) {
 	src := `
 package test
 
@@ -1267,23 +1332,38 @@ board: em.#Board & {
 			flow: [
 				{
 					kind: "slice"
-					name: "CreateUser"
+					name: "Emit"
 					type: "change"
 					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/users/{userId}"}}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/test"}}
 					command: {name: "Cmd", fields: {userId: string}, query: {items: []}}
 					emits: [events.EventA]
 					scenarios: []
 				},
+				{
+					kind: "slice"
+					name: "ReadA"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
+					readModel: {
+						name: "ViewA"
+						cardinality: "single"
+						fields: {userId: string, total: int}
+						computed: {total: {event: events.EventA, fields: ["bogusField"]}}
+					}
+					query: {items: [{types: [events.EventA], tags: []}]}
+					scenarios: []
+				},
 			]
 		}]
 	}]
 }
 `
-	assertValid(t, src)
+	assertInvalid(t, src, "view_ReadA_computed_total_field_bogusField_must_exist_in_event")
 }
 
-func TestInvalidPathParamMissing(t *testing.T) {
+func TestInvalidComputedEventNotQueried(t *testing.T) {
 	src := `
 package test
 
@@ -1293,7 +1373,8 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		EventA: {eventType: "EventA", fields: {}, tags: []}
+		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
+		EventB: {eventType: "EventB", fields: {amount: int}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
@@ -1305,23 +1386,49 @@ board: em.#Board & {
 			flow: [
 				{
 					kind: "slice"
-					name: "CreateUser"
+					name: "EmitA"
 					type: "change"
 					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/users/{userId}"}}
-					command: {name: "Cmd", fields: {}, query: {items: []}}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/a"}}
+					command: {name: "CmdA", fields: {userId: string}, query: {items: []}}
 					emits: [events.EventA]
 					scenarios: []
 				},
+				{
+					kind: "slice"
+					name: "EmitB"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {amount: int}, path: "/b"}}
+					command: {name: "CmdB", fields: {amount: int}, query: {items: []}}
+					emits: [events.EventB]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "ReadA"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
+					readModel: {
+						name: "ViewA"
+						cardinality: "single"
+						fields: {userId: string, total: int}
+						// EventB is NOT in query — only EventA is queried
+						computed: {total: {event: events.EventB, fields: ["amount"]}}
+					}
+					query: {items: [{types: [events.EventA], tags: []}]}
+					scenarios: []
+				},
 			]
 		}]
 	}]
 }
 `
-	assertInvalid(t, src, "path_param_userId")
+	assertInvalid(t, src, "view_ReadA_computed_total_event_must_be_queried")
 }
 
-func TestValidViewScenarioGivenInQuery(t *testing.T) {
+func TestValidViewMappingTypeMatch(t *testing.T) {
 	src := `
 package test
 
@@ -1331,7 +1438,7 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
+		EventA: {eventType: "EventA", fields: {userId: string, amount: int}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
@@ -1346,8 +1453,8 @@ board: em.#Board & {
 					name: "Emit"
 					type: "change"
 					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/test"}}
-					command: {name: "Cmd", fields: {userId: string}, query: {items: []}}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {amount: int}, path: "/test"}}
+					command: {name: "Cmd", fields: {userId: string, amount: int}, query: {items: []}}
 					emits: [events.EventA]
 					scenarios: []
 				},
@@ -1357,11 +1464,14 @@ board: em.#Board & {
 					type: "view"
 					actor: {name: "User"}
 					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
-					readModel: {name: "View", cardinality: "single", fields: {userId: string}}
+					readModel: {
+						name: "ViewA"
+						cardinality: "single"
+						fields: {userId: string, totalPrice: int}
+						mapping: {totalPrice: {event: events.EventA, field: "amount"}}
+					}
 					query: {items: [{types: [events.EventA], tags: []}]}
-					scenarios: [
-						{name: "ok", given: [events.EventA], query: {}, expect: {userId: "abc"}}
-					]
+					scenarios: []
 				},
 			]
 		}]
@@ -1371,7 +1481,7 @@ board: em.#Board & {
 	assertValid(t, src)
 }
 
-func TestInvalidViewScenarioGivenNotInQuery(t *testing.T) {
+func TestInvalidViewMappingTypeMismatch(t *testing.T) {
 	src := `
 package test
 
@@ -1381,8 +1491,7 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
-		EventB: {eventType: "EventB", fields: {}, tags: []}
+		EventA: {eventType: "EventA", fields: {userId: string, amount: int}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
@@ -1394,45 +1503,39 @@ board: em.#Board & {
 			flow: [
 				{
 					kind: "slice"
-					name: "EmitA"
+					name: "Emit"
 					type: "change"
 					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/test"}}
-					command: {name: "Cmd", fields: {userId: string}, query: {items: []}}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {amount: int}, path: "/test"}}
+					command: {name: "Cmd", fields: {userId: string, amount: int}, query: {items: []}}
 					emits: [events.EventA]
 					scenarios: []
 				},
-				{
-					kind: "slice"
-					name: "EmitB"
-					type: "change"
-					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/test2"}}
-					command: {name: "Cmd2", fields: {}, query: {items: []}}
-					emits: [events.EventB]
-					scenarios: []
-				},
 				{
 					kind: "slice"
 					name: "ReadA"
 					type: "view"
 					actor: {name: "User"}
 					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
-					readModel: {name: "View", cardinality: "single", fields: {userId: string}}
+					readModel: {
+						name: "ViewA"
+						cardinality: "single"
+						fields: {userId: string, totalPrice: string}
+						// totalPrice is string but amount is int — type mismatch
+						mapping: {totalPrice: {event: events.EventA, field: "amount"}}
+					}
 					query: {items: [{types: [events.EventA], tags: []}]}
-					scenarios: [
-						{name: "bad", given: [events.EventB], query: {}, expect: {userId: "abc"}}
-					]
+					scenarios: []
 				},
 			]
 		}]
 	}]
 }
 `
-	assertInvalid(t, src, "given_EventB_must_be_in_query")
+	assertInvalid(t, src, "view_ReadA_mapping_totalPrice_type")
 }
 
-func TestValidBoardWithMultipleContextsAndChapters(t *testing.T) {
+func TestInvalidViewMappingFieldNotInEvent(t *testing.T) {
 	src := `
 package test
 
@@ -1442,59 +1545,50 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		EventA: {eventType: "EventA", fields: {}, tags: []}
-		EventB: {eventType: "EventB", fields: {}, tags: []}
+		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
 	}
 	actors: {
 		User: {name: "User"}
 	}
-	contexts: [
-		{
-			name: "Billing"
-			description: "Handles payments"
-			chapters: [
-				{
-					name: "Onboarding"
-					description: "User signs up"
-					flow: [{
-						kind: "slice"
-						name: "SliceA"
-						type: "change"
-						actor: {name: "User"}
-						trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/a"}}
-						command: {name: "CmdA", fields: {}, query: {items: []}}
-						emits: [events.EventA]
-						scenarios: []
-					}]
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "Emit"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/test"}}
+					command: {name: "Cmd", fields: {userId: string}, query: {items: []}}
+					emits: [events.EventA]
+					scenarios: []
 				},
-			]
-		},
-		{
-			name: "Shipping"
-			description: "Handles deliveries"
-			chapters: [
 				{
-					name: "Fulfillment"
-					flow: [{
-						kind: "slice"
-						name: "SliceB"
-						type: "change"
-						actor: {name: "User"}
-						trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/b"}}
-						command: {name: "CmdB", fields: {}, query: {items: []}}
-						emits: [events.EventB]
-						scenarios: []
-					}]
+					kind: "slice"
+					name: "ReadA"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
+					readModel: {
+						name: "ViewA"
+						cardinality: "single"
+						fields: {userId: string, total: int}
+						mapping: {total: {event: events.EventA, field: "noSuchField"}}
+					}
+					query: {items: [{types: [events.EventA], tags: []}]}
+					scenarios: []
 				},
 			]
-		},
-	]
+		}]
+	}]
 }
 `
-	assertValid(t, src)
+	assertInvalid(t, src, "view_ReadA_mapping_total_field_noSuchField_must_exist_in_event")
 }
 
-func TestValidBoardWithEmptyContexts(t *testing.T) {
+func TestValidDottedPathMapping(t *testing.T) {
 	src := `
 package test
 
@@ -1503,33 +1597,12 @@ import "github.com/err0r500/event-modeling-dcb-spec/em"
 board: em.#Board & {
 	name: "Test"
 	tags: {}
-	events: {}
-	actors: {}
-	contexts: []
-}
-`
-	assertValid(t, src)
-}
-
-func TestValidDependentQuery(t *testing.T) {
-	src := `
-package test
-
-import "github.com/err0r500/event-modeling-dcb-spec/em"
-
-_tags: {
-	cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
-	product_id: em.#Tag & {name: "product_id", param: "productId", type: string}
-}
-
-board: em.#Board & {
-	name: "Test"
-	tags: _tags
 	events: {
-		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string, productId: string}, tags: [_tags.cart_id, _tags.product_id]}
-		InventoryChanged: {eventType: "InventoryChanged", fields: {productId: string, qty: int}, tags: [_tags.product_id]}
+		ItemAdded: {eventType: "ItemAdded", fields: {itemId: string, price: int}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
 	}
-	actors: {User: {name: "User"}}
 	contexts: [{
 		name: "Default"
 		chapters: [{
@@ -1537,46 +1610,33 @@ board: em.#Board & {
 			flow: [
 				{
 					kind: "slice"
-					name: "EmitItem"
+					name: "Emit"
 					type: "change"
 					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {productId: string}, path: "/cart/{cartId}/items"}}
-					command: {name: "AddItem", fields: {cartId: string, productId: string}, query: {items: []}}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {itemId: string, price: int}, path: "/items"}}
+					command: {name: "AddItem", fields: {itemId: string, price: int}, query: {items: []}}
 					emits: [events.ItemAdded]
 					scenarios: []
 				},
 				{
 					kind: "slice"
-					name: "EmitInventory"
-					type: "change"
-					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {productId: string, qty: int}, path: "/inventory"}}
-					command: {name: "ChangeInventory", fields: {productId: string, qty: int}, query: {items: []}}
-					emits: [events.InventoryChanged]
-					scenarios: []
-				},
-				{
-					kind: "slice"
-					name: "SubmitCart"
-					type: "change"
+					name: "ViewItems"
+					type: "view"
 					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/submit"}}
-					command: {
-						name: "SubmitCart"
-						fields: {cartId: string}
-						query: {items: [{types: [events.ItemAdded], tags: [{tag: _tags.cart_id, value: fields.cartId}]}]}
-						dependentQuery: {
-							extract: {productId: {event: events.ItemAdded, field: "productId"}}
-							items: [{types: [events.InventoryChanged], tags: [{tag: _tags.product_id, fromExtract: "productId"}]}]
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/items"}
+					readModel: {
+						name: "ItemsView"
+						cardinality: "single"
+						fields: {
+							items: [{itemId: string, price: int}]
+						}
+						mapping: {
+							"items.itemId": {event: events.ItemAdded, field: "itemId"}
+							"items.price": {event: events.ItemAdded, field: "price"}
 						}
 					}
-					emits: []
-					scenarios: [{
-						name: "with inventory"
-						given: [events.ItemAdded, events.InventoryChanged]
-						when: {}
-						then: {success: false, error: "test"}
-					}]
+					query: {items: [{types: [events.ItemAdded], tags: []}]}
+					scenarios: []
 				},
 			]
 		}]
@@ -1586,26 +1646,21 @@ board: em.#Board & {
 	assertValid(t, src)
 }
 
-func TestInvalidDependentQueryExtractEventNotInPrimary(t *testing.T) {
+func TestInvalidDottedPathMapping(t *testing.T) {
 	src := `
 package test
 
 import "github.com/err0r500/event-modeling-dcb-spec/em"
 
-_tags: {
-	cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
-	product_id: em.#Tag & {name: "product_id", param: "productId", type: string}
-}
-
 board: em.#Board & {
 	name: "Test"
-	tags: _tags
+	tags: {}
 	events: {
-		CartCreated: {eventType: "CartCreated", fields: {cartId: string}, tags: [_tags.cart_id]}
-		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string, productId: string}, tags: [_tags.cart_id, _tags.product_id]}
-		InventoryChanged: {eventType: "InventoryChanged", fields: {productId: string}, tags: [_tags.product_id]}
+		ItemAdded: {eventType: "ItemAdded", fields: {itemId: string, price: int}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
 	}
-	actors: {User: {name: "User"}}
 	contexts: [{
 		name: "Default"
 		chapters: [{
@@ -1613,52 +1668,31 @@ board: em.#Board & {
 			flow: [
 				{
 					kind: "slice"
-					name: "EmitCart"
-					type: "change"
-					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {cartId: string}, path: "/cart"}}
-					command: {name: "CreateCart", fields: {cartId: string}, query: {items: []}}
-					emits: [events.CartCreated]
-					scenarios: []
-				},
-				{
-					kind: "slice"
-					name: "EmitItem"
+					name: "Emit"
 					type: "change"
 					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {productId: string}, path: "/cart/{cartId}/items"}}
-					command: {name: "AddItem", fields: {cartId: string, productId: string}, query: {items: []}}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {itemId: string, price: int}, path: "/items"}}
+					command: {name: "AddItem", fields: {itemId: string, price: int}, query: {items: []}}
 					emits: [events.ItemAdded]
 					scenarios: []
 				},
 				{
 					kind: "slice"
-					name: "EmitInventory"
-					type: "change"
-					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {productId: string}, path: "/inventory"}}
-					command: {name: "ChangeInventory", fields: {productId: string}, query: {items: []}}
-					emits: [events.InventoryChanged]
-					scenarios: []
-				},
-				{
-					kind: "slice"
-					name: "SubmitCart"
-					type: "change"
+					name: "ViewItems"
+					type: "view"
 					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/submit"}}
-					command: {
-						name: "SubmitCart"
-						fields: {cartId: string}
-						// Primary query only has CartCreated
-						query: {items: [{types: [events.CartCreated], tags: [{tag: _tags.cart_id, value: fields.cartId}]}]}
-						// But extract references ItemAdded which is NOT in primary
-						dependentQuery: {
-							extract: {productId: {event: events.ItemAdded, field: "productId"}}
-							items: [{types: [events.InventoryChanged], tags: [{tag: _tags.product_id, fromExtract: "productId"}]}]
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/items"}
+					readModel: {
+						name: "ItemsView"
+						cardinality: "single"
+						fields: {
+							items: [{itemId: string, price: int}]
+						}
+						mapping: {
+							"items.nonexistent": {event: events.ItemAdded, field: "itemId"}
 						}
 					}
-					emits: []
+					query: {items: [{types: [events.ItemAdded], tags: []}]}
 					scenarios: []
 				},
 			]
@@ -1666,28 +1700,24 @@ board: em.#Board & {
 	}]
 }
 `
-	assertInvalid(t, src, "empty disjunction")
+	assertInvalidGo(t, src, "items.nonexistent", "must resolve to a field")
 }
 
-func TestInvalidDependentQueryExtractFieldNotInEvent(t *testing.T) {
+func TestInvalidDottedPathTypeMismatch(t *testing.T) {
 	src := `
 package test
 
 import "github.com/err0r500/event-modeling-dcb-spec/em"
 
-_tags: {
-	cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
-	product_id: em.#Tag & {name: "product_id", param: "productId", type: string}
-}
-
 board: em.#Board & {
 	name: "Test"
-	tags: _tags
+	tags: {}
 	events: {
-		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string}, tags: [_tags.cart_id]}
-		InventoryChanged: {eventType: "InventoryChanged", fields: {productId: string}, tags: [_tags.product_id]}
+		ItemAdded: {eventType: "ItemAdded", fields: {itemId: string, price: int}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
 	}
-	actors: {User: {name: "User"}}
 	contexts: [{
 		name: "Default"
 		chapters: [{
@@ -1695,41 +1725,4466 @@ board: em.#Board & {
 			flow: [
 				{
 					kind: "slice"
-					name: "EmitItem"
+					name: "Emit"
 					type: "change"
 					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/items"}}
-					command: {name: "AddItem", fields: {cartId: string}, query: {items: []}}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {itemId: string, price: int}, path: "/items"}}
+					command: {name: "AddItem", fields: {itemId: string, price: int}, query: {items: []}}
 					emits: [events.ItemAdded]
 					scenarios: []
 				},
 				{
 					kind: "slice"
-					name: "EmitInventory"
-					type: "change"
+					name: "ViewItems"
+					type: "view"
 					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {productId: string}, path: "/inventory"}}
-					command: {name: "ChangeInventory", fields: {productId: string}, query: {items: []}}
-					emits: [events.InventoryChanged]
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/items"}
+					readModel: {
+						name: "ItemsView"
+						cardinality: "single"
+						fields: {
+							items: [{itemId: string, price: int}]
+						}
+						mapping: {
+							// itemId is string but price is int - type mismatch
+							"items.itemId": {event: events.ItemAdded, field: "price"}
+						}
+					}
+					query: {items: [{types: [events.ItemAdded], tags: []}]}
 					scenarios: []
 				},
-				{
-					kind: "slice"
-					name: "SubmitCart"
-					type: "change"
-					actor: {name: "User"}
+			]
+		}]
+	}]
+}
+`
+	assertInvalidGo(t, src, "items.itemId", "must match source event field type")
+}
+
+func TestValidFieldsFromAuth(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		OrderPlaced: {eventType: "OrderPlaced", fields: {userId: string, amount: int}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "PlaceOrder"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {
+					verb: "POST"
+					params: {}
+					body: {amount: int}
+					auth: {userId: string}
+					path: "/orders"
+				}}
+				command: {
+					name: "PlaceOrder"
+					fields: {
+						userId: string
+						amount: int
+					}
+					query: {items: []}
+				}
+				emits: [events.OrderPlaced]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+	assertValid(t, src)
+}
+
+func TestInvalidFieldFromAuthTypeMismatch(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		OrderPlaced: {eventType: "OrderPlaced", fields: {userId: string}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "PlaceOrder"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {
+					verb: "POST"
+					params: {}
+					body: {}
+					auth: {userId: int}  // int, but command expects string
+					path: "/orders"
+				}}
+				command: {
+					name: "PlaceOrder"
+					fields: {
+						userId: string
+					}
+					query: {items: []}
+				}
+				emits: [events.OrderPlaced]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+	assertInvalid(t, src, "slice_PlaceOrder_field_userId_type")
+}
+
+func TestInvalidFieldNotInAnyEndpointSource(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		OrderPlaced: {eventType: "OrderPlaced", fields: {userId: string}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "PlaceOrder"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {
+					verb: "POST"
+					params: {}
+					body: {}
+					auth: {}
+					path: "/orders"
+				}}
+				command: {
+					name: "PlaceOrder"
+					fields: {
+						userId: string  // not in params, body, or auth
+					}
+					query: {items: []}
+				}
+				emits: [events.OrderPlaced]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+	assertInvalid(t, src, "slice_PlaceOrder_field_userId_must_come_from_trigger")
+}
+
+// Helper functions
+
+// buildResult holds the CUE value and any errors from loading/building
+type buildResult struct {
+	value cue.Value
+	err   error
+}
+
+func buildValue(t *testing.T, src string) buildResult {
+	t.Helper()
+
+	absDir, err := filepath.Abs(".")
+	if err != nil {
+		return buildResult{err: err}
+	}
+
+	overlay := map[string]load.Source{
+		filepath.Join(absDir, "test_input.cue"): load.FromString(src),
+	}
+
+	cfg := &load.Config{
+		Dir:     absDir,
+		Overlay: overlay,
+	}
+
+	instances := load.Instances([]string{"./test_input.cue"}, cfg)
+	if len(instances) == 0 {
+		return buildResult{err: err}
+	}
+
+	inst := instances[0]
+	if inst.Err != nil {
+		return buildResult{err: inst.Err}
+	}
+
+	ctx := cuecontext.New()
+	v := ctx.BuildInstance(inst)
+	if v.Err() != nil {
+		return buildResult{err: v.Err()}
+	}
+
+	return buildResult{value: v}
+}
+
+func assertValid(t *testing.T, src string) {
+	t.Helper()
+	res := buildValue(t, src)
+	if res.err != nil {
+		t.Errorf("expected valid, got build error: %v", res.err)
+		return
+	}
+	if err := res.value.Validate(cue.Concrete(false)); err != nil {
+		t.Errorf("expected valid, got validation error: %v", err)
+	}
+}
+
+func assertInvalid(t *testing.T, src string, expectedErrContains string) {
+	t.Helper()
+	res := buildValue(t, src)
+
+	// Check build error first
+	if res.err != nil {
+		if !strings.Contains(res.err.Error(), expectedErrContains) {
+			t.Errorf("expected error containing %q, got build error: %v", expectedErrContains, res.err)
+		}
+		return
+	}
+
+	// Check validation error
+	err := res.value.Validate(cue.Concrete(false))
+	if err == nil {
+		t.Errorf("expected invalid (containing %q), but validation passed", expectedErrContains)
+		return
+	}
+	if !strings.Contains(err.Error(), expectedErrContains) {
+		t.Errorf("expected error containing %q, got validation error: %v", expectedErrContains, err)
+	}
+}
+
+func assertInvalidGo(t *testing.T, src string, pathContains string, errContains string) {
+	t.Helper()
+	res := buildValue(t, src)
+	if res.err != nil {
+		t.Errorf("expected valid CUE build, got build error: %v", res.err)
+		return
+	}
+
+	// CUE validation should pass (dotted paths not checked by CUE)
+	if err := res.value.Validate(cue.Concrete(false)); err != nil {
+		t.Errorf("expected CUE validation to pass, got: %v", err)
+		return
+	}
+
+	// Go validation should catch it
+	board := res.value.LookupPath(cue.ParsePath("board"))
+	errs := render.ValidateBoard(board)
+	if len(errs) == 0 {
+		t.Errorf("expected Go validation error containing %q and %q, but none found", pathContains, errContains)
+		return
+	}
+
+	found := false
+	for _, e := range errs {
+		if strings.Contains(e, pathContains) && strings.Contains(e, errContains) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("expected error containing %q and %q, got: %v", pathContains, errContains, errs)
+	}
+}
+
+func TestValidPathParamInParams(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "CreateUser"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/users/{userId}"}}
+					command: {name: "Cmd", fields: {userId: string}, query: {items: []}}
+					emits: [events.EventA]
+					scenarios: []
+				},
+			]
+		}]
+	}]
+}
+`
+	assertValid(t, src)
+}
+
+func TestUnusedPathParam(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_tags: {
+	cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
+}
+
+board: em.#Board & {
+	name: "Test"
+	tags: _tags
+	events: {
+		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string, owner: string}, tags: [_tags.cart_id]}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "AddItem"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {orgId: string, cartId: string, lang: string}, body: {}, path: "/orgs/{orgId}/carts/{cartId}/{lang}"}}
+					command: {
+						name: "AddItem"
+						fields: {cartId: string, owner: string}
+						mapping: {owner: trigger.endpoint.params.orgId}
+						query: {items: []}
+					}
+					emits: [events.ItemAdded]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "ViewCart"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {cartId: string, owner: string, page: int}, body: {}, path: "/carts/{cartId}/{owner}/{page}"}
+					readModel: {name: "Cart", cardinality: "single", fields: {owner: string}}
+					query: {items: [{types: [events.ItemAdded], tags: [{tag: _tags.cart_id, value: endpoint.params.cartId}]}]}
+					scenarios: []
+				},
+			]
+		}]
+	}]
+}
+`
+	assertValid(t, src)
+	assertInvalidGo(t, src, `slice "AddItem" endpoint: path param {lang} is neither a command field nor used by the command mapping`, render.ErrCmdUnusedParam)
+	assertInvalidGo(t, src, `view "ViewCart" endpoint: path param {page} is neither a read model field nor bound to a query tag`, render.ErrViewUnusedParam)
+
+	// cartId and orgId (mapped) are used by the command, cartId (query
+	// binding) and owner (read model field) by the view
+	res := buildValue(t, src)
+	var unused []string
+	for _, e := range render.ValidateBoard(res.value.LookupPath(cue.ParsePath("board"))) {
+		if code := render.DiagnosticCode(e); code == render.ErrCmdUnusedParam || code == render.ErrViewUnusedParam {
+			unused = append(unused, e)
+		}
+	}
+	if len(unused) != 2 {
+		t.Errorf("want only {lang} and {page} reported unused, got %v", unused)
+	}
+	if render.CodeSeverity(render.ErrCmdUnusedParam) != render.SeverityWarning || render.CodeSeverity(render.ErrViewUnusedParam) != render.SeverityWarning {
+		t.Error("unused path params should be warnings")
+	}
+}
+
+func TestInvalidPathParamMissing(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		EventA: {eventType: "EventA", fields: {}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "CreateUser"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/users/{userId}"}}
+					command: {name: "Cmd", fields: {}, query: {items: []}}
+					emits: [events.EventA]
+					scenarios: []
+				},
+			]
+		}]
+	}]
+}
+`
+	assertInvalid(t, src, "path_param_userId")
+}
+
+func TestValidViewScenarioGivenInQuery(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "Emit"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/test"}}
+					command: {name: "Cmd", fields: {userId: string}, query: {items: []}}
+					emits: [events.EventA]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "ReadA"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
+					readModel: {name: "View", cardinality: "single", fields: {userId: string}}
+					query: {items: [{types: [events.EventA], tags: []}]}
+					scenarios: [
+						{name: "ok", given: [events.EventA], query: {}, expect: {userId: "abc"}}
+					]
+				},
+			]
+		}]
+	}]
+}
+`
+	assertValid(t, src)
+}
+
+func TestInvalidViewScenarioGivenNotInQuery(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
+		EventB: {eventType: "EventB", fields: {}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "EmitA"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/test"}}
+					command: {name: "Cmd", fields: {userId: string}, query: {items: []}}
+					emits: [events.EventA]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "EmitB"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/test2"}}
+					command: {name: "Cmd2", fields: {}, query: {items: []}}
+					emits: [events.EventB]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "ReadA"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/test"}
+					readModel: {name: "View", cardinality: "single", fields: {userId: string}}
+					query: {items: [{types: [events.EventA], tags: []}]}
+					scenarios: [
+						{name: "bad", given: [events.EventB], query: {}, expect: {userId: "abc"}}
+					]
+				},
+			]
+		}]
+	}]
+}
+`
+	assertInvalid(t, src, "given_EventB_must_be_in_query")
+}
+
+func TestValidBoardWithMultipleContextsAndChapters(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		EventA: {eventType: "EventA", fields: {}, tags: []}
+		EventB: {eventType: "EventB", fields: {}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [
+		{
+			name: "Billing"
+			description: "Handles payments"
+			chapters: [
+				{
+					name: "Onboarding"
+					description: "User signs up"
+					flow: [{
+						kind: "slice"
+						name: "SliceA"
+						type: "change"
+						actor: {name: "User"}
+						trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/a"}}
+						command: {name: "CmdA", fields: {}, query: {items: []}}
+						emits: [events.EventA]
+						scenarios: []
+					}]
+				},
+			]
+		},
+		{
+			name: "Shipping"
+			description: "Handles deliveries"
+			chapters: [
+				{
+					name: "Fulfillment"
+					flow: [{
+						kind: "slice"
+						name: "SliceB"
+						type: "change"
+						actor: {name: "User"}
+						trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/b"}}
+						command: {name: "CmdB", fields: {}, query: {items: []}}
+						emits: [events.EventB]
+						scenarios: []
+					}]
+				},
+			]
+		},
+	]
+}
+`
+	assertValid(t, src)
+}
+
+func TestValidBoardWithEmptyContexts(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {}
+	actors: {}
+	contexts: []
+}
+`
+	assertValid(t, src)
+}
+
+// dependentQueryBoard is a board whose SubmitCart slice binds its query to a
+// command field and its dependent query to an extracted value.
+const dependentQueryBoard = `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_tags: {
+	cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
+	product_id: em.#Tag & {name: "product_id", param: "productId", type: string}
+}
+
+board: em.#Board & {
+	name: "Test"
+	tags: _tags
+	events: {
+		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string, productId: string}, tags: [_tags.cart_id, _tags.product_id]}
+		InventoryChanged: {eventType: "InventoryChanged", fields: {productId: string, qty: int}, tags: [_tags.product_id]}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "EmitItem"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {productId: string}, path: "/cart/{cartId}/items"}}
+					command: {name: "AddItem", fields: {cartId: string, productId: string}, query: {items: []}}
+					emits: [events.ItemAdded]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "EmitInventory"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {productId: string, qty: int}, path: "/inventory"}}
+					command: {name: "ChangeInventory", fields: {productId: string, qty: int}, query: {items: []}}
+					emits: [events.InventoryChanged]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "SubmitCart"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/submit"}}
+					command: {
+						name: "SubmitCart"
+						fields: {cartId: string}
+						query: {items: [{types: [events.ItemAdded], tags: [{tag: _tags.cart_id, value: fields.cartId}]}]}
+						dependentQuery: {
+							extract: {productId: {event: events.ItemAdded, field: "productId"}}
+							items: [{types: [events.InventoryChanged], tags: [{tag: _tags.product_id, fromExtract: "productId"}]}]
+						}
+					}
+					emits: []
+					scenarios: [{
+						name: "with inventory"
+						given: [events.ItemAdded, events.InventoryChanged]
+						when: {}
+						then: {success: false, error: "test"}
+					}]
+				},
+			]
+		}]
+	}]
+}
+`
+
+func TestValidDependentQuery(t *testing.T) {
+	assertValid(t, dependentQueryBoard)
+}
+
+func TestRenderQueryBindings(t *testing.T) {
+	b, _, err := board.LoadBoardFromSource(dependentQueryBoard, "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	_, files, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	out, err := render.RenderSliceIR(files["SubmitCart.json"], 100)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, want := range []string{
+		"Stream 1: [ItemAdded] tagged cart_id=command.fields.cartId",
+		"Dependent Query:",
+		"extract.productId ← ItemAdded.productId",
+		"Stream 1: [InventoryChanged] tagged product_id←extract.productId",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render missing %q:\n%s", want, out)
+		}
+	}
+
+	// Views render theirs under their query
+	view := map[string]any{
+		"kind": "slice", "type": "view", "name": "CartStock",
+		"readModel": map[string]any{"name": "CartStock", "cardinality": "table", "fields": map[string]any{"qty": "int"}},
+		"query":     []any{map[string]any{"types": []any{"ItemAdded"}, "tags": []any{map[string]any{"tag": "cart_id", "param": "cartId", "value": "endpoint.params.cartId"}}}},
+		"dependentQuery": map[string]any{
+			"extract": map[string]any{"productIds": map[string]any{"event": "ItemAdded", "field": "productId", "many": true}},
+			"items":   []any{map[string]any{"types": []any{"InventoryChanged"}, "tags": []any{map[string]any{"tag": "product_id", "param": "productId", "fromExtract": "productIds"}}}},
+		},
+	}
+	out, err = render.RenderSliceIR(view, 100)
+	if err != nil {
+		t.Fatalf("render view: %v", err)
+	}
+	if !regexp.MustCompile(`(?s)Query:.*cart_id=endpoint\.params\.cartId.*Dependent Query:.*extract\.productIds ← ItemAdded\.productId \(many\).*\[InventoryChanged\] tagged product_id←extract\.productIds`).MatchString(out) {
+		t.Errorf("view render missing its dependent query:\n%s", out)
+	}
+}
+
+func TestInvalidUnusedTag(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_tags: {
+	cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
+	region: em.#Tag & {name: "region", param: "region", type: string}
+}
+
+board: em.#Board & {
+	name: "Test"
+	tags: _tags
+	events: {
+		CartCreated: {eventType: "CartCreated", fields: {cartId: string}, tags: [_tags.cart_id]}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "CreateCart"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/carts/{cartId}"}}
+				command: {name: "CreateCart", fields: {cartId: string}, query: {items: []}}
+				emits: [events.CartCreated]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+	assertInvalidGo(t, src, "E322", `tag "region" is declared but no event or query uses it`)
+}
+
+func TestInvalidDependentQueryExtractCycle(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_tags: {
+	order_id: em.#Tag & {name: "order_id"}
+	customer_id: em.#Tag & {name: "customer_id"}
+}
+
+board: em.#Board & {
+	name: "Test"
+	tags: _tags
+	events: {
+		OrderPlaced: {eventType: "OrderPlaced", fields: {orderId: string, customerId: string}, tags: [_tags.order_id, _tags.customer_id]}
+		CustomerRegistered: {eventType: "CustomerRegistered", fields: {customerId: string, orderId: string}, tags: [_tags.customer_id, _tags.order_id]}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "OrderCustomer"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/orders/customer"}
+					readModel: {name: "OrderCustomer", cardinality: "single", fields: {customerId: string}}
+					query: {items: [{types: [events.OrderPlaced], tags: []}]}
+					dependentQuery: {
+						extract: {cid: {event: events.OrderPlaced, field: "customerId"}}
+						items: [{types: [events.CustomerRegistered], tags: [{tag: _tags.customer_id, fromExtract: "cid"}]}]
+					}
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "CustomerFirstOrder"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/customers/order"}
+					readModel: {name: "CustomerFirstOrder", cardinality: "single", fields: {orderId: string}}
+					query: {items: [{types: [events.CustomerRegistered], tags: []}]}
+					dependentQuery: {
+						extract: {oid: {event: events.CustomerRegistered, field: "orderId"}}
+						items: [{types: [events.OrderPlaced], tags: [{tag: _tags.order_id, fromExtract: "oid"}]}]
+					}
+					scenarios: []
+				},
+			]
+		}]
+	}]
+}
+`
+	assertInvalidGo(t, src, "E315", `OrderPlaced -(slice "OrderCustomer")-> CustomerRegistered -(slice "CustomerFirstOrder")-> OrderPlaced`)
+}
+
+// customerOrdersBoard has dependent queries that only look cyclic within a
+// slice: CustomerOrders extracts from OrderPlaced to load more OrderPlaced
+// events, OrderLinks extracts from each of its events to load the other.
+const customerOrdersBoard = `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_tags: {
+	order_id: em.#Tag & {name: "order_id"}
+	customer_id: em.#Tag & {name: "customer_id"}
+}
+
+board: em.#Board & {
+	name: "Test"
+	tags: _tags
+	events: {
+		OrderPlaced: {eventType: "OrderPlaced", fields: {orderId: string, customerId: string}, tags: [_tags.order_id, _tags.customer_id]}
+		CustomerRegistered: {eventType: "CustomerRegistered", fields: {customerId: string, orderId: string}, tags: [_tags.customer_id, _tags.order_id]}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "CustomerOrders"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/orders/siblings"}
+					readModel: {name: "CustomerOrders", cardinality: "single", fields: {customerId: string}}
+					query: {items: [{types: [events.OrderPlaced], tags: []}]}
+					dependentQuery: {
+						extract: {cid: {event: events.OrderPlaced, field: "customerId"}}
+						items: [{types: [events.OrderPlaced], tags: [{tag: _tags.customer_id, fromExtract: "cid"}]}]
+					}
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "OrderLinks"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/orders/links"}
+					readModel: {name: "OrderLinks", cardinality: "single", fields: {orderId: string}}
+					query: {items: [{types: [events.OrderPlaced, events.CustomerRegistered], tags: []}]}
+					dependentQuery: {
+						extract: {
+							cid: {event: events.OrderPlaced, field: "customerId"}
+							oid: {event: events.CustomerRegistered, field: "orderId"}
+						}
+						items: [
+							{types: [events.CustomerRegistered], tags: [{tag: _tags.customer_id, fromExtract: "cid"}]},
+							{types: [events.OrderPlaced], tags: [{tag: _tags.order_id, fromExtract: "oid"}]},
+						]
+					}
+					scenarios: []
+				},
+			]
+		}]
+	}]
+}
+`
+
+func TestValidDependentQuerySingleSliceExtraction(t *testing.T) {
+	assertValid(t, customerOrdersBoard)
+	res := buildValue(t, customerOrdersBoard)
+	if res.err != nil {
+		t.Fatal(res.err)
+	}
+	for _, e := range render.ValidateBoard(res.value.LookupPath(cue.ParsePath("board"))) {
+		if strings.HasPrefix(e, render.ErrDepExtractCycle) {
+			t.Errorf("unexpected cycle: %s", e)
+		}
+	}
+}
+
+func TestInvalidDependentQueryUnknownExtract(t *testing.T) {
+	// The schema rejects the name; the failed slice disjunction is reported
+	// as one positioned E317
+	src := strings.Replace(customerOrdersBoard, `fromExtract: "oid"`, `fromExtract: "orderRef"`, 1)
+	_, _, err := board.LoadBoardFromSource(src, "")
+	if err == nil {
+		t.Fatal("expected a build error")
+	}
+	want := `slice at flow index 1 dependentQuery: fromExtract "orderRef" is not declared in extract`
+	d := render.ParseValidationError(strings.TrimPrefix(err.Error(), "build: "))
+	if d.Code != render.ErrDepFromExtractUnknown || d.Message != want || d.File == "" || strings.Contains(err.Error(), "\n") {
+		t.Errorf("error = %q, want a single positioned E317 %q", err, want)
+	}
+}
+
+func TestValidCommandComputedWithExpr(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_tags: {
+	cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
+	product_id: em.#Tag & {name: "product_id", param: "productId", type: string}
+}
+
+board: em.#Board & {
+	name: "Test"
+	tags: _tags
+	events: {
+		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string, productId: string}, tags: [_tags.cart_id, _tags.product_id]}
+		InventoryChanged: {eventType: "InventoryChanged", fields: {productId: string, qty: int}, tags: [_tags.product_id]}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "EmitItem"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/items"}}
+					command: {name: "AddItem", fields: {cartId: string, productId: string}, computed: {productId: {description: "picked by the server", expr: "pickProduct(cartId)"}}, query: {items: []}}
+					emits: [events.ItemAdded]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "EmitInventory"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {productId: string, qty: int}, path: "/inventory"}}
+					command: {name: "ChangeInventory", fields: {productId: string, qty: int}, query: {items: []}}
+					emits: [events.InventoryChanged]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "SubmitCart"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/submit"}}
+					command: {
+						name: "SubmitCart"
+						fields: {cartId: string}
+						query: {items: [{types: [events.ItemAdded], tags: [{tag: _tags.cart_id, value: fields.cartId}]}]}
+						dependentQuery: {
+							extract: {productId: {event: events.ItemAdded, field: "productId"}}
+							items: [{types: [events.InventoryChanged], tags: [{tag: _tags.product_id, fromExtract: "productId"}]}]
+						}
+					}
+					emits: []
+					scenarios: [{
+						name: "with inventory"
+						given: [events.ItemAdded, events.InventoryChanged]
+						when: {}
+						then: {success: false, error: "test"}
+					}]
+				},
+			]
+		}]
+	}]
+}
+`
+	assertValid(t, src)
+	res := buildValue(t, src)
+	for _, e := range render.ValidateBoard(res.value.LookupPath(cue.ParsePath("board"))) {
+		if strings.Contains(e, "E113") {
+			t.Errorf("unexpected %s", e)
+		}
+	}
+}
+
+func TestInvalidCommandComputedWithoutDescription(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_tags: {
+	cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
+	product_id: em.#Tag & {name: "product_id", param: "productId", type: string}
+}
+
+board: em.#Board & {
+	name: "Test"
+	tags: _tags
+	events: {
+		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string, productId: string}, tags: [_tags.cart_id, _tags.product_id]}
+		InventoryChanged: {eventType: "InventoryChanged", fields: {productId: string, qty: int}, tags: [_tags.product_id]}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "EmitItem"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/items"}}
+					command: {name: "AddItem", fields: {cartId: string, productId: string}, computed: {productId: {expr: "pickProduct(cartId)"}}, query: {items: []}}
+					emits: [events.ItemAdded]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "EmitInventory"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {productId: string, qty: int}, path: "/inventory"}}
+					command: {name: "ChangeInventory", fields: {productId: string, qty: int}, query: {items: []}}
+					emits: [events.InventoryChanged]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "SubmitCart"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/submit"}}
+					command: {
+						name: "SubmitCart"
+						fields: {cartId: string}
+						query: {items: [{types: [events.ItemAdded], tags: [{tag: _tags.cart_id, value: fields.cartId}]}]}
+						dependentQuery: {
+							extract: {productId: {event: events.ItemAdded, field: "productId"}}
+							items: [{types: [events.InventoryChanged], tags: [{tag: _tags.product_id, fromExtract: "productId"}]}]
+						}
+					}
+					emits: []
+					scenarios: [{
+						name: "with inventory"
+						given: [events.ItemAdded, events.InventoryChanged]
+						when: {}
+						then: {success: false, error: "test"}
+					}]
+				},
+			]
+		}]
+	}]
+}
+`
+	assertInvalidGo(t, src, "productId", "E113")
+}
+
+func TestInvalidCommandComputedShadowsTriggerField(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_tags: cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
+
+board: em.#Board & {
+	name: "Test"
+	tags: _tags
+	events: ItemAdded: {eventType: "ItemAdded", fields: {cartId: string, quantity: int}, tags: [_tags.cart_id]}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "AddItem"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {quantity: int}, path: "/cart/{cartId}/items"}}
+				command: {name: "AddItem", fields: {cartId: string, quantity: int}, computed: {quantity: "clamped to stock"}, query: {items: []}}
+				emits: [events.ItemAdded]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+	assertInvalidGo(t, src, "quantity", "E109")
+}
+
+func TestInvalidCommandComputedOpenType(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_tags: cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
+
+board: em.#Board & {
+	name: "Test"
+	tags: _tags
+	events: CartCreated: {eventType: "CartCreated", fields: {cartId: string}, tags: [_tags.cart_id]}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "CreateCart"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/cart"}}
+				command: {name: "CreateCart", fields: {cartId: string, meta: {...}}, computed: {cartId: "new uuid", meta: "request metadata"}, query: {items: []}}
+				emits: [events.CartCreated]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+	assertInvalidGo(t, src, "meta", "E109")
+
+	res := buildValue(t, src)
+	for _, e := range render.ValidateBoard(res.value.LookupPath(cue.ParsePath("board"))) {
+		if strings.Contains(e, "E109") && strings.Contains(e, "cartId") {
+			t.Errorf("unexpected %s", e)
+		}
+	}
+}
+
+func TestInvalidDependentQueryFromExtractTagNotOnEvent(t *testing.T) {
+	// The em schema already rejects this at build time, so exercise the Go
+	// validator on a plain board value.
+	src := `
+events: {
+	ItemAdded: {eventType: "ItemAdded", fields: {cartId: string}, tags: [{name: "cart_id"}]}
+	InventoryChanged: {eventType: "InventoryChanged", fields: {productId: string}, tags: [{name: "product_id"}]}
+}
+flow: [{
+	kind: "slice"
+	name: "SubmitCart"
+	type: "change"
+	command: {
+		query: {items: [{types: [events.ItemAdded], tags: [{tag: {name: "cart_id"}, value: "c1"}]}]}
+		dependentQuery: {
+			extract: {cartId: {event: events.ItemAdded, field: "cartId"}}
+			items: [{types: [events.InventoryChanged], tags: [{tag: {name: "cart_id"}, fromExtract: "cartId"}]}]
+		}
+	}
+}]
+`
+	board := cuecontext.New().CompileString(src)
+	if board.Err() != nil {
+		t.Fatalf("compile: %v", board.Err())
+	}
+
+	for _, e := range render.ValidateBoard(board) {
+		if strings.Contains(e, "E316") && strings.Contains(e, "SubmitCart") && strings.Contains(e, "cart_id") {
+			return
+		}
+	}
+	t.Errorf("expected E316 for SubmitCart, got: %v", render.ValidateBoard(board))
+}
+
+func TestInvalidAutomationShape(t *testing.T) {
+	const src = `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		CartClosed: {eventType: "CartClosed", fields: {}, tags: []}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "CloseCart"
+				type: "automation"
+				SHAPE
+				command: {name: "CloseCart", fields: {}, query: {items: []}}
+				emits: [events.CartClosed]
+			}]
+		}]
+	}]
+}
+`
+	// The schema rejects both shapes; the failed slice disjunction is
+	// reported as one positioned E110, without the mismatches of the
+	// change and view branches.
+	tests := []struct {
+		name  string
+		shape string
+		want  string
+	}{
+		{
+			name:  "endpoint trigger",
+			shape: `trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/close"}}`,
+			want:  "automation at flow index 0 is triggered by endpoint, want externalEvent or internalEvent",
+		},
+		{
+			name: "actor",
+			shape: `trigger: {kind: "externalEvent", externalEvent: {name: "Tick", source: "Clock", fields: {}}}
+				actor: {name: "User"}`,
+			want: "automation at flow index 0 declares an actor; automations run without one",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := board.LoadBoardFromSource(strings.Replace(src, "SHAPE", tt.shape, 1), "")
+			if err == nil {
+				t.Fatal("expected a build error")
+			}
+			d := render.ParseValidationError(strings.TrimPrefix(err.Error(), "build: "))
+			if d.Code != render.ErrAutomationShape || d.Message != tt.want || d.File == "" {
+				t.Errorf("error = %q, want a single positioned E110 %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidExpectCardinality(t *testing.T) {
+	const src = `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		CartCreated: {eventType: "CartCreated", fields: {cartId: string}, tags: []}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "CreateCart"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {cartId: string}, path: "/carts"}}
+				command: {name: "CreateCart", fields: {cartId: string}, query: {items: []}}
+				emits: [events.CartCreated]
+				scenarios: []
+			}, {
+				kind: "slice"
+				name: "Carts"
+				type: "view"
+				actor: {name: "User"}
+				endpoint: {verb: "GET", params: {}, body: {}, path: "/carts"}
+				readModel: {name: "Carts", cardinality: "CARDINALITY", persistence: "transient", SCHEMA: {cartId: string}}
+				query: {items: [{types: [events.CartCreated], tags: []}]}
+				scenarios: [{name: "one cart", given: [], expect: EXPECT}]
+			}]
+		}]
+	}]
+}
+`
+	// #ViewSlice constrains expect by cardinality; the failed slice
+	// disjunction is reported as one positioned E211, without the
+	// mismatches of the other slice kinds.
+	tests := []struct {
+		name        string
+		cardinality string
+		schema      string
+		expect      string
+		want        string
+	}{
+		{"single expects a list", "single", "fields", `[{cartId: "c1"}]`, `view at flow index 1 scenario 0: expect is a list but read model cardinality is "single"`},
+		{"table expects a struct", "table", "columns", `{cartId: "c1"}`, `view at flow index 1 scenario 0: expect is a struct but read model cardinality is "table"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := os.MkdirTemp(".", "expect-cardinality-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			boardSrc := strings.NewReplacer("CARDINALITY", tt.cardinality, "SCHEMA", tt.schema, "EXPECT", tt.expect).Replace(src)
+			file := filepath.Join(dir, "board.cue")
+			if err := os.WriteFile(file, []byte(boardSrc), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, _, err = board.LoadBoardPermissive(file, "")
+			if err == nil {
+				t.Fatal("expected a build error")
+			}
+			d := render.ParseValidationError(strings.TrimPrefix(err.Error(), "build: "))
+			if d.Code != render.ErrExpectShape || d.Message != tt.want || d.File == "" {
+				t.Errorf("error = %q, want a single positioned E211 %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidInlineEventShapeConflict(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_tags: {
+	cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
+	product_id: em.#Tag & {name: "product_id", param: "productId", type: string}
+}
+
+board: em.#Board & {
+	name: "Test"
+	tags: _tags
+	events: {
+		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string, productId: string}, tags: [_tags.cart_id, _tags.product_id]}
+		InventoryChanged: {eventType: "InventoryChanged", fields: {productId: string, qty: int}, tags: [_tags.product_id]}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "EmitItem"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {productId: string}, path: "/cart/{cartId}/items"}}
+					command: {name: "AddItem", fields: {cartId: string, productId: string}, query: {items: []}}
+					emits: [events.ItemAdded]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "EmitInventory"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {productId: string}, path: "/inventory"}}
+					command: {name: "ChangeInventory", fields: {productId: string}, query: {items: []}}
+					emits: [{eventType: "InventoryChanged", fields: {productId: string}, tags: [_tags.product_id]}]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "SubmitCart"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/submit"}}
+					command: {
+						name: "SubmitCart"
+						fields: {cartId: string}
+						query: {items: [{types: [events.ItemAdded], tags: [{tag: _tags.cart_id, value: fields.cartId}]}]}
+						dependentQuery: {
+							extract: {productId: {event: events.ItemAdded, field: "productId"}}
+							items: [{types: [events.InventoryChanged], tags: [{tag: _tags.product_id, fromExtract: "productId"}]}]
+						}
+					}
+					emits: []
+					scenarios: [{
+						name: "with inventory"
+						given: [events.ItemAdded, events.InventoryChanged]
+						when: {}
+						then: {success: false, error: "test"}
+					}]
+				},
+			]
+		}]
+	}]
+}
+`
+	assertInvalidGo(t, src, "InventoryChanged", "E305")
+}
+
+func TestInvalidDependentQueryExtractEventNotInPrimary(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_tags: {
+	cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
+	product_id: em.#Tag & {name: "product_id", param: "productId", type: string}
+}
+
+board: em.#Board & {
+	name: "Test"
+	tags: _tags
+	events: {
+		CartCreated: {eventType: "CartCreated", fields: {cartId: string}, tags: [_tags.cart_id]}
+		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string, productId: string}, tags: [_tags.cart_id, _tags.product_id]}
+		InventoryChanged: {eventType: "InventoryChanged", fields: {productId: string}, tags: [_tags.product_id]}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "EmitCart"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {cartId: string}, path: "/cart"}}
+					command: {name: "CreateCart", fields: {cartId: string}, query: {items: []}}
+					emits: [events.CartCreated]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "EmitItem"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {productId: string}, path: "/cart/{cartId}/items"}}
+					command: {name: "AddItem", fields: {cartId: string, productId: string}, query: {items: []}}
+					emits: [events.ItemAdded]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "EmitInventory"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {productId: string}, path: "/inventory"}}
+					command: {name: "ChangeInventory", fields: {productId: string}, query: {items: []}}
+					emits: [events.InventoryChanged]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "SubmitCart"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/submit"}}
+					command: {
+						name: "SubmitCart"
+						fields: {cartId: string}
+						// Primary query only has CartCreated
+						query: {items: [{types: [events.CartCreated], tags: [{tag: _tags.cart_id, value: fields.cartId}]}]}
+						// But extract references ItemAdded which is NOT in primary
+						dependentQuery: {
+							extract: {productId: {event: events.ItemAdded, field: "productId"}}
+							items: [{types: [events.InventoryChanged], tags: [{tag: _tags.product_id, fromExtract: "productId"}]}]
+						}
+					}
+					emits: []
+					scenarios: []
+				},
+			]
+		}]
+	}]
+}
+`
+	assertInvalid(t, src, "empty disjunction")
+}
+
+func TestInvalidDependentQueryExtractFieldNotInEvent(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_tags: {
+	cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
+	product_id: em.#Tag & {name: "product_id", param: "productId", type: string}
+}
+
+board: em.#Board & {
+	name: "Test"
+	tags: _tags
+	events: {
+		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string}, tags: [_tags.cart_id]}
+		InventoryChanged: {eventType: "InventoryChanged", fields: {productId: string}, tags: [_tags.product_id]}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "EmitItem"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/items"}}
+					command: {name: "AddItem", fields: {cartId: string}, query: {items: []}}
+					emits: [events.ItemAdded]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "EmitInventory"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {productId: string}, path: "/inventory"}}
+					command: {name: "ChangeInventory", fields: {productId: string}, query: {items: []}}
+					emits: [events.InventoryChanged]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "SubmitCart"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/submit"}}
+					command: {
+						name: "SubmitCart"
+						fields: {cartId: string}
+						query: {items: [{types: [events.ItemAdded], tags: [{tag: _tags.cart_id, value: fields.cartId}]}]}
+						// Extract field "productId" doesn't exist in ItemAdded
+						dependentQuery: {
+							extract: {productId: {event: events.ItemAdded, field: "productId"}}
+							items: [{types: [events.InventoryChanged], tags: [{tag: _tags.product_id, fromExtract: "productId"}]}]
+						}
+					}
+					emits: []
+					scenarios: []
+				},
+			]
+		}]
+	}]
+}
+`
+	assertInvalid(t, src, "empty disjunction")
+}
+
+func TestInvalidDependentQueryFromExtractInPrimaryQuery(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_tags: {
+	cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
+}
+
+board: em.#Board & {
+	name: "Test"
+	tags: _tags
+	events: {
+		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string}, tags: [_tags.cart_id]}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "EmitItem"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/items"}}
+					command: {name: "AddItem", fields: {cartId: string}, query: {items: []}}
+					emits: [events.ItemAdded]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "SubmitCart"
+					type: "change"
+					actor: {name: "User"}
 					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/submit"}}
 					command: {
 						name: "SubmitCart"
 						fields: {cartId: string}
-						query: {items: [{types: [events.ItemAdded], tags: [{tag: _tags.cart_id, value: fields.cartId}]}]}
-						// Extract field "productId" doesn't exist in ItemAdded
-						dependentQuery: {
-							extract: {productId: {event: events.ItemAdded, field: "productId"}}
-							items: [{types: [events.InventoryChanged], tags: [{tag: _tags.product_id, fromExtract: "productId"}]}]
-						}
+						// fromExtract in primary query - not allowed
+						query: {items: [{types: [events.ItemAdded], tags: [{tag: _tags.cart_id, fromExtract: "something"}]}]}
+					}
+					emits: []
+					scenarios: []
+				},
+			]
+		}]
+	}]
+}
+`
+	assertInvalidGo(t, src, "SubmitCart", "fromExtract only allowed in dependentQuery")
+}
+
+// --- Union type tests ---
+
+// TestInvalidUnionTypeEndpointIncompatibleWithCommand checks that when an endpoint field has
+// a union type (int | string) and the command field has a completely incompatible type (float),
+// a type mismatch error is reported. CUE detects this as an empty disjunction; the fix in
+// formatSingleError ensures the error is surfaced rather than silently dropped.
+func TestInvalidUnionTypeEndpointIncompatibleWithCommand(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		TestEvent: {eventType: "TestEvent", fields: {x: int | string}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "TestSlice"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {
+					verb: "POST"
+					params: {x: int | string}  // union type
+					body: {}
+					path: "/test"
+				}}
+				command: {
+					name: "TestCmd"
+					fields: {x: float}  // float: incompatible with int | string
+					query: {items: []}
+				}
+				emits: [events.TestEvent]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+	assertInvalid(t, src, "slice_TestSlice_field_x_type")
+}
+
+// TestInvalidUnionTypeCommandTooNarrow checks that when an endpoint provides a union type
+// (int | string) but the command only declares a subset (string), a Go-side subsumption
+// error is reported. CUE's unification (int|string & string = string) passes, but the
+// command is too narrow to safely handle all values the endpoint might provide.
+func TestInvalidUnionTypeCommandTooNarrow(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		TestEvent: {eventType: "TestEvent", fields: {x: string}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "TestSlice"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {
+					verb: "POST"
+					params: {x: int | string}  // union: endpoint can provide int OR string
+					body: {}
+					path: "/test"
+				}}
+				command: {
+					name: "TestCmd"
+					fields: {x: string}  // too narrow: can't handle ints from the endpoint
+					query: {items: []}
+				}
+				emits: [events.TestEvent]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+	assertInvalidGo(t, src, "TestSlice", "type too narrow")
+}
+
+// TestValidUnionTypeSameOnBothSides checks that when endpoint and command both declare the
+// same union type (int | string), no error is reported.
+func TestValidUnionTypeSameOnBothSides(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		TestEvent: {eventType: "TestEvent", fields: {x: int | string}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "TestSlice"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {
+					verb: "POST"
+					params: {x: int | string}
+					body: {}
+					path: "/test"
+				}}
+				command: {
+					name: "TestCmd"
+					fields: {x: int | string}  // same union type: command handles everything
+					query: {items: []}
+				}
+				emits: [events.TestEvent]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+	assertValid(t, src)
+}
+
+// TestValidUnionTypeCommandWiderThanEndpoint checks that when an endpoint provides a concrete
+// type (string) and the command declares a wider union type (int | string), no error is
+// reported — the command can safely handle the narrower endpoint type.
+func TestValidUnionTypeCommandWiderThanEndpoint(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		TestEvent: {eventType: "TestEvent", fields: {x: string}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "TestSlice"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {
+					verb: "POST"
+					params: {x: string}  // concrete type
+					body: {}
+					path: "/test"
+				}}
+				command: {
+					name: "TestCmd"
+					fields: {x: int | string}  // wider union: command accepts more than endpoint provides
+					query: {items: []}
+				}
+				emits: [events.TestEvent]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+	assertValid(t, src)
+}
+
+func TestScenarioConsistencyTagConflict(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_tags: {
+	cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
+}
+
+board: em.#Board & {
+	name: "Test"
+	tags: _tags
+	events: {
+		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string}, tags: [_tags.cart_id]}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "AddItem"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}"}}
+				command: {
+					name: "AddItem"
+					fields: {cartId: string}
+					query: {items: [{types: [events.ItemAdded], tags: [{tag: _tags.cart_id, value: fields.cartId}]}]}
+				}
+				emits: [events.ItemAdded]
+				scenarios: [{
+					name: "same cart"
+					given: [events.ItemAdded & {fields: cartId: "abc"}]
+					when: {cartId: "abc"}
+					then: {success: false, error: "test"}
+				}, {
+					name: "other cart"
+					given: [events.ItemAdded & {fields: cartId: "def"}]
+					when: {cartId: "abc"}
+					then: {success: false, error: "test"}
+				}]
+			}]
+		}]
+	}]
+}
+`
+	res := buildValue(t, src)
+	if res.err != nil {
+		t.Fatalf("build: %v", res.err)
+	}
+	errs := render.ValidateScenarioConsistency(res.value.LookupPath(cue.ParsePath("board")))
+	if len(errs) != 1 {
+		t.Fatalf("expected exactly one E407, got: %v", errs)
+	}
+	if errs[0].Code != render.ErrScenarioTagConflict || errs[0].Slice != "AddItem" || !strings.Contains(errs[0].Message, "other cart") {
+		t.Errorf("expected E407 for AddItem scenario %q, got: %+v", "other cart", errs[0])
+	}
+}
+
+func TestNamingConventions(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+_events: [Type=string]: em.#Event & {eventType: Type}
+_events: {
+	eventA: {fields: {}, tags: []}
+}
+
+_sliceA: em.#ChangeSlice & {
+	kind: "slice"
+	name: "SliceA"
+	type: "change"
+	actor: {name: "User"}
+	trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/test"}}
+	command: {name: "cmdA", fields: {}, query: {items: []}}
+	emits: [_events.eventA]
+	scenarios: []
+}
+
+board: em.#Board & {
+	name: "Test Board"
+	tags: {
+		mytag: {name: "mytag"}
+	}
+	events: _events
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				_sliceA,
+				{
+					kind: "story"
+					name: "story step"
+					slice: _sliceA
+					description: "Test story"
+				},
+			]
+		}]
+	}]
+}
+`
+	res := buildValue(t, src)
+	if res.err != nil {
+		t.Fatalf("build: %v", res.err)
+	}
+	board := res.value.LookupPath(cue.ParsePath("board"))
+
+	errs, err := render.ValidateNaming(board, render.DefaultNamingConfig())
+	if err != nil {
+		t.Fatalf("ValidateNaming: %v", err)
+	}
+	if len(errs) != 2 || !strings.Contains(errs[0].Message, `event "eventA"`) || !strings.Contains(errs[1].Message, `command "cmdA"`) {
+		t.Errorf("expected E601 for eventA and cmdA, got: %v", errs)
+	}
+
+	// Custom patterns replace the defaults per category
+	errs, err = render.ValidateNaming(board, render.NamingConfig{Events: "^[a-z]", Actors: "^[a-z]"})
+	if err != nil {
+		t.Fatalf("ValidateNaming: %v", err)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, `actor "User"`) {
+		t.Errorf("expected E601 for actor User, got: %v", errs)
+	}
+
+	if _, err := render.ValidateNaming(board, render.NamingConfig{Events: "("}); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestExampleBoardIRRoundTrip(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if err := board.RoundTripCheck(b); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReifyIncludeSource(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	_, slices, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{IncludeSource: true})
+	for file, data := range slices {
+		src, _ := data["_source"].(string)
+		if !strings.Contains(src, fmt.Sprintf("%q", data["name"])) {
+			t.Errorf("%s: _source missing slice name, got:\n%s", file, src)
+		}
+	}
+
+	_, slices, _ = board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	for file, data := range slices {
+		if _, ok := data["_source"]; ok {
+			t.Errorf("%s: _source present without IncludeSource", file)
+		}
+	}
+}
+
+func TestManifestContextsIndexFlow(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, _, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+
+	seen := make(map[int]int)
+	stories := 0
+	for _, ctx := range manifest.Contexts {
+		for _, chap := range ctx.Chapters {
+			for _, idx := range chap.FlowIndices {
+				seen[idx]++
+				if idx >= 0 && idx < len(manifest.Flow) && manifest.Flow[idx].Kind == "story" {
+					stories++
+				}
+			}
+		}
+	}
+	for i := range manifest.Flow {
+		if seen[i] != 1 {
+			t.Errorf("flow index %d (%s) appears %d times in contexts, want 1", i, manifest.Flow[i].Name, seen[i])
+		}
+	}
+	if len(seen) != len(manifest.Flow) {
+		t.Errorf("contexts index %d flow entries, flow has %d", len(seen), len(manifest.Flow))
+	}
+	if stories == 0 {
+		t.Error("expected story entries to be indexed in chapters")
+	}
+}
+
+func TestMigrateManifest(t *testing.T) {
+	old := board.BoardManifest{
+		Name: "Old",
+		Flow: []board.FlowEntry{{Index: 0, Kind: "slice", Name: "A"}, {Index: 1, Kind: "slice", Name: "B"}},
+	}
+	if warnings := board.MigrateManifest(&old); len(warnings) > 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	if old.SchemaVersion != board.IRSchemaVersion {
+		t.Errorf("schema version = %d, want %d", old.SchemaVersion, board.IRSchemaVersion)
+	}
+	if len(old.Contexts) != 1 || len(old.Contexts[0].Chapters) != 1 || len(old.Contexts[0].Chapters[0].FlowIndices) != 2 {
+		t.Errorf("expected a single default context holding the flow, got %+v", old.Contexts)
+	}
+
+	newer := board.BoardManifest{SchemaVersion: board.IRSchemaVersion + 1, Name: "Newer"}
+	if warnings := board.MigrateManifest(&newer); len(warnings) != 1 {
+		t.Errorf("expected a version warning, got %v", warnings)
+	}
+}
+
+func TestBoxWideCharacters(t *testing.T) {
+	box := render.NewBox(30)
+	box.AddLine("  📷 screenshot.png")
+	box.AddLine("  商品をカートに追加")
+	box.AddSection()
+	box.AddLine("  📷📷📷📷📷📷📷📷📷📷📷📷📷📷📷📷📷📷📷📷")
+	box.AddLine("  カートカートカートカートカートカートカート")
+	box.AddLine("  plain ascii")
+
+	for i, line := range strings.Split(strings.TrimSuffix(box.Render(), "\n"), "\n") {
+		if w := runewidth.StringWidth(line); w != 30 {
+			t.Errorf("line %d is %d cells wide, want 30: %q", i, w, line)
+		}
+	}
+}
+
+func TestBoxWrapsLongLines(t *testing.T) {
+	long := "    error: cart cannot hold more than three items at once"
+	box := render.NewBoxWrapped(30)
+	box.AddLine(long)
+
+	lines := strings.Split(strings.TrimSuffix(box.Render(), "\n"), "\n")
+	var text []string
+	for i, line := range lines[1 : len(lines)-1] {
+		if w := runewidth.StringWidth(line); w != 30 {
+			t.Errorf("line %d is %d cells wide, want 30: %q", i, w, line)
+		}
+		inner := strings.TrimSuffix(strings.TrimPrefix(line, render.Vertical), render.Vertical)
+		if !strings.HasPrefix(inner, "    ") {
+			t.Errorf("line %d lost its indentation: %q", i, inner)
+		}
+		text = append(text, strings.TrimSpace(inner))
+	}
+	if got := strings.Join(text, " "); got != strings.TrimSpace(long) {
+		t.Errorf("wrapped text = %q, want %q", got, strings.TrimSpace(long))
+	}
+}
+
+func TestBoxWrapsStyledLines(t *testing.T) {
+	// The style is carried over to each continuation line, and reset at the
+	// end of each line so it doesn't leak into the border
+	const green = "\x1b[32m"
+	text := "error: cart cannot hold more than three items at once"
+	box := render.NewBoxWrapped(30)
+	box.AddLine("    " + green + text + "\x1b[0m")
+
+	sgr := regexp.MustCompile("\x1b\\[[0-9;]*m")
+	lines := strings.Split(strings.TrimSuffix(box.Render(), "\n"), "\n")
+	var words []string
+	for i, line := range lines[1 : len(lines)-1] {
+		if w := lipgloss.Width(line); w != 30 {
+			t.Errorf("line %d is %d cells wide, want 30: %q", i, w, line)
+		}
+		inner := strings.TrimSuffix(strings.TrimPrefix(line, render.Vertical), render.Vertical)
+		if !strings.HasPrefix(inner, "    "+green) {
+			t.Errorf("line %d isn't styled after its indentation: %q", i, inner)
+		}
+		if !strings.HasSuffix(strings.TrimRight(inner, " "), "\x1b[0m") {
+			t.Errorf("line %d doesn't reset its style: %q", i, inner)
+		}
+		words = append(words, strings.TrimSpace(sgr.ReplaceAllString(inner, "")))
+	}
+	if len(words) < 2 {
+		t.Fatalf("line not wrapped: %q", lines)
+	}
+	if got := strings.Join(words, " "); got != text {
+		t.Errorf("wrapped text = %q, want %q", got, text)
+	}
+}
+
+func TestCUEErrorPositions(t *testing.T) {
+	// Three CUE errors: each is reported, at its own position
+	board := cuecontext.New().CompileString(`
+actors: {User: {name: "User"}}
+flow: [{
+	kind: "slice"
+	name: "CloseCart"
+	type: "automation"
+	n: int & "s"
+	q: >3 & 2
+}]
+x: int
+x: "a"
+`, cue.Filename("board.cue"))
+	want := map[string]int{"flow.0.n": 7, "flow.0.q": 8, "x": 10}
+	for _, d := range render.ValidateBoardStructured(board) {
+		path, _, _ := strings.Cut(d.Message, ":")
+		line, ok := want[path]
+		if !ok {
+			continue
+		}
+		delete(want, path)
+		if d.File != "board.cue" || d.Line != line {
+			t.Errorf("%s at %s:%d, want board.cue:%d", path, d.File, d.Line, line)
+		}
+	}
+	if len(want) > 0 {
+		t.Errorf("missing CUE errors %v, got %v", want, render.ValidateBoard(board))
+	}
+}
+
+func TestParseValidationError(t *testing.T) {
+	// The text gives code, message and position; the slice and field a
+	// diagnostic is about only come from the validation passes.
+	diag := `E101: slice "AddItem" field "quantity" must come from trigger [board.cue:12:3]`
+	got := render.ParseValidationError(diag)
+	want := render.ValidationError{
+		Code:    "E101",
+		Message: `slice "AddItem" field "quantity" must come from trigger`,
+		File:    "board.cue",
+		Line:    12,
+		Col:     3,
+	}
+	if got != want {
+		t.Errorf("ParseValidationError = %+v, want %+v", got, want)
+	}
+	for _, d := range []string{diag, "E601: event name \"x\" should be PascalCase", "load: expected '}'"} {
+		if s := render.ParseValidationError(d).String(); s != d {
+			t.Errorf("round trip of %q gave %q", d, s)
+		}
+	}
+}
+
+func TestManifestDiagnostics(t *testing.T) {
+	// Ghost is queried but never emitted: one E320 warning about Emit
+	const src = `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		Done: {fields: {a: string}, tags: []}
+		Ghost: {fields: {a: string}, tags: []}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "Emit"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {a: string}, path: "/test"}}
+				command: {name: "Emit", fields: {a: string}, query: {items: [{types: [events.Ghost], tags: []}]}}
+				emits: [events.Done]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+	b, warnings, err := board.LoadBoardFromSource(src, "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !slices.Equal(render.FormatDiagnostics(b.Diagnostics), warnings) {
+		t.Errorf("warnings = %v, want the formatted diagnostics %v", warnings, b.Diagnostics)
+	}
+	if len(b.Diagnostics) != 1 {
+		t.Fatalf("diagnostics = %v, want one E320", b.Diagnostics)
+	}
+	if d := b.Diagnostics[0]; d.Code != render.ErrQueriedEventNeverEmitted || d.Slice != "Emit" || d.File != "" {
+		t.Errorf("diagnostic = %+v, want an unpositioned E320 about Emit", d)
+	}
+
+	// The manifest keeps the parts, pointing the diagnostic at its slice
+	manifest, sliceFiles, _ := board.ReifyBoardFiles(b, b.Diagnostics, board.ReifyOptions{})
+	if !slices.Equal(manifest.Errors, warnings) || len(manifest.Diagnostics) != 1 {
+		t.Fatalf("manifest errors = %v, diagnostics = %v", manifest.Errors, manifest.Diagnostics)
+	}
+	if d := manifest.Diagnostics[0]; d.Slice != "Emit" || !strings.HasSuffix(d.File, ".cue") || d.Line == 0 {
+		t.Errorf("manifest diagnostic = %+v, want one located at slice Emit", d)
+	}
+	if b.Diagnostics[0].File != "" {
+		t.Error("ReifyBoardFiles located the board's own diagnostics")
+	}
+
+	dir := t.TempDir()
+	if _, err := board.WriteBoardFiles(dir, manifest, sliceFiles, "", nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "diagnostics.json"))
+	if err != nil {
+		t.Fatalf("read diagnostics.json: %v", err)
+	}
+	var written []render.ValidationError
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("decode diagnostics.json: %v", err)
+	}
+	if !slices.Equal(written, manifest.Diagnostics) {
+		t.Errorf("diagnostics.json = %+v, want %+v", written, manifest.Diagnostics)
+	}
+}
+
+func TestRenderSliceDeterministic(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, slices, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	for _, entry := range manifest.Flow {
+		if entry.File == "" {
+			continue
+		}
+		first, err := render.RenderSliceIR(slices[entry.File], 100)
+		if err != nil {
+			t.Fatalf("render %s: %v", entry.File, err)
+		}
+		for range 10 {
+			again, _ := render.RenderSliceIR(slices[entry.File], 100)
+			if again != first {
+				t.Fatalf("%s renders differently between runs:\n%s\n---\n%s", entry.File, first, again)
+			}
+		}
+	}
+}
+
+func TestRenderBoardASCIIColumns(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, slices, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	out := render.RenderBoardASCIIColumns(manifest.Name, board.FlowSlices(manifest, slices), 140)
+
+	for _, want := range []string{"TRIGGER", "READ MODEL", "■ CartCreated", "▭ CartItemsView", "⚡ InventoryChanged"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("columns output missing %q:\n%s", want, out)
+		}
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n")[1:] {
+		if n := len([]rune(line)); n > 140 {
+			t.Errorf("line %d is %d runes wide, want <= 140", i+1, n)
+		}
+	}
+}
+
+func TestRenderTimeline(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, slices, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	got := render.RenderTimeline(manifest.Name, board.FlowSlices(manifest, slices))
+
+	// Each slice once, in flow order; stories add nothing
+	want := `TIMELINE: Shopping Cart
+
+  [User] AddItem       ──▶ ■ CartCreated
+                           ■ ItemAdded
+  [User] RemoveItem    ──▶ ■ ItemRemoved
+  [User] ClearCart     ──▶ ■ CartCleared
+                           ▭ ViewCartItems (reads CartCreated, CartCleared, ItemAdded, ItemRemoved)
+  [User] DeleteCart    ──▶ ■ CartDeleted
+  ⚙ OnInventoryChanged ──▶ ■ InventoryChanged
+                           ▭ ViewProductsInventories (reads InventoryChanged)
+  [User] SubmitCart    ──▶ ■ CartSubmitted
+  ⚙ AutoCloseCart      ──▶ ■ CartClosed
+  ⚙ OnPriceChanged     ──▶ ■ PriceChanged
+                           ▭ ChangedPrices (reads PriceChanged)
+                           ▭ OpenCartsWithProducts (reads CartCreated, CartSubmitted, ItemAdded, CartCleared, ItemRemoved)
+  ⚙ ArchiveItems       ──▶ ■ ItemArchived
+`
+	if got != want {
+		t.Errorf("timeline:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderStateMachine(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, slices, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	flow := board.FlowSlices(manifest, slices)
+
+	// Every event tagged cart_id, in the order the flow first emits it
+	got, err := render.RenderStateMachine(flow, "cart_id")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := `stateDiagram-v2
+    %% lifecycle of cart_id
+    state "after CartCreated" as s1
+    state "after ItemAdded" as s2
+    state "after ItemRemoved" as s3
+    state "after CartCleared" as s4
+    state "after CartDeleted" as s5
+    state "after CartSubmitted" as s6
+    state "after CartClosed" as s7
+    state "after ItemArchived" as s8
+    [*] --> s1 : CartCreated
+    s1 --> s2 : ItemAdded
+    s2 --> s3 : ItemRemoved
+    s3 --> s4 : CartCleared
+    s4 --> s5 : CartDeleted
+    s5 --> s6 : CartSubmitted
+    s6 --> s7 : CartClosed
+    s7 --> s8 : ItemArchived
+`
+	if got != want {
+		t.Errorf("state machine:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := render.RenderStateMachine(flow, "region"); err == nil || !strings.Contains(err.Error(), `no emitted event is tagged "region"`) {
+		t.Errorf("untagged lifecycle error = %v", err)
+	}
+}
+
+func TestGoHandlers(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, slices, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	src, err := golang.Handlers("cart", board.FlowSlices(manifest, slices))
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "handlers.go", src, 0); err != nil {
+		t.Fatalf("generated code doesn't parse: %v", err)
+	}
+	for _, want := range []string{
+		"func AddItem(ctx context.Context, in AddItemInput) ([]Event, error)",
+		"func (CartCreated) EventType() string",
+		"func OpenCartsWithProducts(ctx context.Context, q OpenCartsWithProductsQuery) ([]OpenCartsWithProductsReadModel, error)",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code missing %q", want)
+		}
+	}
+}
+
+func TestGenerateOpenAPI(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	out, err := openapi.GenerateOpenAPI(b)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	var doc struct {
+		OpenAPI string `yaml:"openapi"`
+		Paths   map[string]map[string]struct {
+			OperationID string `yaml:"operationId"`
+			Parameters  []struct {
+				Name string `yaml:"name"`
+				In   string `yaml:"in"`
+			} `yaml:"parameters"`
+			RequestBody *struct{} `yaml:"requestBody"`
+		} `yaml:"paths"`
+		Components struct {
+			Schemas map[string]any `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("openapi = %q, want 3.1.0", doc.OpenAPI)
+	}
+	add := doc.Paths["/carts/{cartId}/items"]["post"]
+	if add.OperationID != "AddItem" || add.RequestBody == nil {
+		t.Errorf("POST /carts/{cartId}/items = %+v, want AddItem with a request body", add)
+	}
+	if len(add.Parameters) != 1 || add.Parameters[0].Name != "cartId" || add.Parameters[0].In != "path" {
+		t.Errorf("AddItem parameters = %+v, want path param cartId", add.Parameters)
+	}
+	if view := doc.Paths["/carts/{cartId}"]["get"]; view.OperationID != "ViewCartItems" {
+		t.Errorf("GET /carts/{cartId} = %+v, want ViewCartItems", view)
+	}
+	if _, ok := doc.Components.Schemas["CartItemsView"]; !ok {
+		t.Errorf("components.schemas missing CartItemsView: %v", doc.Components.Schemas)
+	}
+}
+
+func TestMermaidFlowchart(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, slices, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	out := mermaid.Flowchart(manifest, slices)
+	if !strings.HasPrefix(out, "flowchart LR\n") {
+		t.Errorf("flowchart doesn't start with the header:\n%s", out)
+	}
+	for _, want := range []string{
+		`subgraph ctx_Shopping["Shopping"]`,
+		`cmd_AddItem["AddItem"]:::command`,
+		`evt_ItemAdded(["ItemAdded"]):::event`,
+		`rm_CartItemsView("CartItemsView"):::readModel`,
+		"cmd_AddItem --> evt_ItemAdded",
+		"evt_ItemAdded --> rm_CartItemsView",
+		"evt_CartCreated -.-> cmd_RemoveItem",
+		"evt_CartSubmitted --> cmd_AutoCloseCart",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("flowchart missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerateEventSchemas(t *testing.T) {
+	res := buildValue(t, `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		OrderPlaced: {eventType: "OrderPlaced", fields: {ref: int | string, lines: [...{sku: string, qty: int}]}, tags: []}
+	}
+	actors: {}
+	contexts: []
+}
+`)
+	if res.err != nil {
+		t.Fatalf("build: %v", res.err)
+	}
+	schemas, err := jsonschema.GenerateEventSchemas(&board.Board{Value: res.value.LookupPath(cue.ParsePath("board"))})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(schemas["OrderPlaced"], &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	want := map[string]any{
+		"$schema": jsonschema.Draft,
+		"$id":     "OrderPlaced.schema.json",
+		"title":   "OrderPlaced",
+		"type":    "object",
+		"properties": map[string]any{
+			"ref": map[string]any{"anyOf": []any{map[string]any{"type": "integer"}, map[string]any{"type": "string"}}},
+			"lines": map[string]any{"type": "array", "items": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"sku": map[string]any{"type": "string"}, "qty": map[string]any{"type": "integer"}},
+				"required":   []any{"qty", "sku"},
+			}},
+		},
+		"required": []any{"lines", "ref"},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("schema =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestGoTypes(t *testing.T) {
+	events := map[string]map[string]any{
+		"OrderPlaced": {
+			"ref":   "(int|string)",
+			"lines": []any{map[string]any{"sku": "string", "qty": "int"}},
+		},
+	}
+	flow := []map[string]any{{
+		"kind": "slice", "type": "view", "name": "ViewOrder",
+		"readModel": map[string]any{"name": "OrderView", "fields": map[string]any{"total": "float", "paid": "bool"}},
+	}}
+	src, err := golang.Types("events", events, flow)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "events_gen.go", src, 0); err != nil {
+		t.Fatalf("generated code doesn't parse: %v\n%s", err, src)
+	}
+	for _, want := range []string{
+		"type OrderPlaced struct",
+		"Lines []OrderPlacedLines `json:\"lines\"`",
+		"Ref   any                `json:\"ref\"` // int | string",
+		"type OrderPlacedLines struct",
+		"Qty int    `json:\"qty\"`",
+		"type OrderView struct",
+		"Total float64 `json:\"total\"`",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code missing %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestTypeScriptTypes(t *testing.T) {
+	events := map[string]map[string]any{
+		"OrderPlaced": {
+			"ref":   "(int|string)",
+			"lines": []any{map[string]any{"sku": "string", "qty": "int"}},
+		},
+		"OrderShipped": {"carrier": "string"},
+	}
+	flow := []map[string]any{{
+		"kind": "slice", "type": "view", "name": "ViewOrder",
+		"readModel": map[string]any{"name": "OrderView", "fields": map[string]any{"total": "float", "paid": "bool"}},
+	}}
+	src := string(typescript.Types(events, flow))
+	for _, want := range []string{
+		"export interface OrderPlaced {\n    eventType: \"OrderPlaced\";\n    lines: OrderPlacedLines[];\n    ref: number | string;\n}",
+		"export interface OrderPlacedLines {\n    qty: number;\n    sku: string;\n}",
+		"export type Event =\n    | OrderPlaced\n    | OrderShipped;",
+		"export interface OrderView {\n    paid: boolean;\n    total: number;\n}",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code missing %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestDiffBoards(t *testing.T) {
+	ir := func(slices ...map[string]any) (*board.BoardManifest, map[string]map[string]any) {
+		m := &board.BoardManifest{}
+		files := map[string]map[string]any{}
+		for i, data := range slices {
+			file := fmt.Sprintf("%d.json", i)
+			m.Flow = append(m.Flow, board.FlowEntry{Index: i, Kind: "slice", Name: data["name"].(string), File: file})
+			files[file] = data
+		}
+		return m, files
+	}
+	change := func(name string, fields map[string]any, emits ...string) map[string]any {
+		var e []any
+		for _, t := range emits {
+			e = append(e, map[string]any{"type": t})
+		}
+		return map[string]any{"kind": "slice", "type": "change", "name": name, "command": map[string]any{"fields": fields}, "emits": e}
+	}
+
+	oldM, oldS := ir(
+		change("AddItem", map[string]any{"quantity": "int"}, "ItemAdded"),
+		change("ClearCart", nil, "CartCleared"),
+	)
+	newM, newS := ir(
+		change("AddItem", map[string]any{"quantity": "float", "note": "string"}, "ItemAdded", "CartCreated"),
+		change("EmptyCart", nil, "CartCleared"),
+	)
+
+	got := diff.Compare(oldM, oldS, newM, newS, diff.Options{})
+	want := diff.Result{
+		Added:   []string{"EmptyCart"},
+		Removed: []string{"ClearCart"},
+		Changed: []diff.SliceChange{{Slice: "AddItem", Changes: []diff.Change{
+			{Path: "command.fields.quantity", Kind: diff.KindChanged, Old: "int", New: "float"},
+			{Path: "command.fields.note", Kind: diff.KindAdded, New: "string"},
+			{Path: "emits", Kind: diff.KindAdded, New: "CartCreated"},
+		}}},
+	}
+	if fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", want) {
+		t.Errorf("Compare =\n%+v\nwant\n%+v", got, want)
+	}
+	if out := diff.Render(got, 60); !strings.Contains(out, "~ command.fields.quantity: int → float") || !strings.Contains(out, "- ClearCart") {
+		t.Errorf("Render missing changes:\n%s", out)
+	}
+
+	byIndex := diff.Compare(oldM, oldS, newM, newS, diff.Options{ByIndex: true})
+	if len(byIndex.Added) != 0 || len(byIndex.Removed) != 0 || len(byIndex.Changed) != 2 || byIndex.Changed[1].OldName != "ClearCart" {
+		t.Fatalf("Compare by index = %+v, want AddItem changed and ClearCart renamed to EmptyCart", byIndex)
+	}
+	if same := diff.Compare(oldM, oldS, oldM, oldS, diff.Options{}); !same.Empty() {
+		t.Errorf("Compare of a board with itself = %+v, want empty", same)
+	}
+}
+
+func TestListBoards(t *testing.T) {
+	res := buildValue(t, `
+package test
+
+zeta: {name: "Zeta", flow: []}
+notABoard: {name: "Other"}
+alpha: {name: "Alpha", flow: [{kind: "slice"}]}
+`)
+	if res.err != nil {
+		t.Fatalf("build: %v", res.err)
+	}
+	if got := board.ListBoards(res.value); fmt.Sprint(got) != "[alpha zeta]" {
+		t.Errorf("ListBoards = %v, want [alpha zeta]", got)
+	}
+
+	// #N selects by position among the boards sorted by name; the default
+	// is the first declared
+	for sel, want := range map[string]string{"#0": "Alpha", "#1": "Zeta", "": "Zeta", "alpha": "Alpha"} {
+		if got, _ := board.FindBoard(res.value, sel).LookupPath(cue.ParsePath("name")).String(); got != want {
+			t.Errorf("FindBoard(%q) = board %q, want %q", sel, got, want)
+		}
+	}
+	if v := board.FindBoardByIndex(res.value, 2); v.Exists() {
+		t.Errorf("FindBoardByIndex(2) = %v, want none", v)
+	}
+
+	_, _, err := board.LoadBoardPermissive("examples/cart.cue", "nope")
+	if err == nil || !strings.Contains(err.Error(), `board "nope" not found; available boards: cartBoard`) {
+		t.Errorf("LoadBoardPermissive(nope) error = %v, want the available boards", err)
+	}
+	if b, _, err := board.LoadBoardPermissive("examples/cart.cue", "#0"); err != nil || b.Name != "Shopping Cart" {
+		t.Errorf("LoadBoardPermissive(#0) error = %v, want the Shopping Cart board", err)
+	}
+	_, _, err = board.LoadBoardPermissive("examples/cart.cue", "#3")
+	if err == nil || !strings.Contains(err.Error(), "board #3 out of range: there are 1 boards (#0-#0): cartBoard") {
+		t.Errorf("LoadBoardPermissive(#3) error = %v, want the board count", err)
+	}
+}
+
+func TestTreeAutomationConsumes(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, files, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	tree := tui.NewTreeState(&manifest, files)
+	tree.SetFlat(true)
+
+	var node *tui.TreeNode
+	for _, n := range tree.FlatView {
+		if n.Name == "OnInventoryChanged" {
+			node = n
+		}
+	}
+	if node == nil {
+		t.Fatal("OnInventoryChanged not in the tree")
+	}
+	if node.SliceType != "automation" || !slices.Contains(node.Consumes, "InventoryChanged") {
+		t.Errorf("OnInventoryChanged: type %q, consumes %v; want automation consuming InventoryChanged", node.SliceType, node.Consumes)
+	}
+
+	out, err := render.RenderSliceIR(files[manifest.Flow[node.FlowIndex].File], 100)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(out, "External Event: InventoryChanged") || strings.Contains(out, "Actor:") {
+		t.Errorf("automation detail should show the external event and no actor:\n%s", out)
+	}
+}
+
+func TestTUIErrorNavigation(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	errs := []render.ValidationError{
+		{Code: render.ErrCmdFieldSource, Message: `slice "AddItem" command: field "quantity" must come from trigger, needs a mapping or be computed`, Slice: "AddItem", Field: "quantity"},
+		{Code: render.ErrViewFieldSource, Message: `view "CartItems" readModel: field "total" must come from queried events or computed`, Slice: "CartItems", Field: "total"},
+	}
+	manifest, files, images := board.ReifyBoardFiles(b, errs, board.ReifyOptions{})
+	dir := t.TempDir()
+	if _, err := board.WriteBoardFiles(dir, manifest, files, "examples", images); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	m, err := tui.NewIRModel(dir)
+	if err != nil {
+		t.Fatalf("model: %v", err)
+	}
+	var model tea.Model = m
+	update := func(msg tea.Msg) {
+		model, _ = model.Update(msg)
+	}
+	update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if out := model.View(); !strings.Contains(out, "Error 1/2") || !strings.Contains(out, "▶ E101") {
+		t.Errorf("error view should select the first error:\n%s", out)
+	}
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if out := model.View(); !strings.Contains(out, "Error 2/2") || !strings.Contains(out, "▶ E202") {
+		t.Errorf("n should select the second error and stop there:\n%s", out)
+	}
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	if out := model.View(); !strings.Contains(out, "Error 1/2") {
+		t.Errorf("N should select the first error again:\n%s", out)
+	}
+
+	// A resize re-wraps the errors to the new width
+	update(tea.WindowSizeMsg{Width: 40, Height: 40})
+	out := model.View()
+	for _, line := range strings.Split(out, "\n") {
+		if w := lipgloss.Width(line); w > 40 {
+			t.Errorf("line of width %d after resizing to 40: %q", w, line)
+		}
+	}
+	if !strings.Contains(out, "▶ E101") || !strings.Contains(out, `"quantity"`) {
+		t.Errorf("resized error view should still show the whole selected error:\n%s", out)
+	}
+
+	tree := tui.NewTreeState(&manifest, files)
+	tree.SetContextFilter(manifest.Contexts[len(manifest.Contexts)-1].Name)
+	if !tree.Select("AddItem") {
+		t.Fatal("Select(AddItem) = false")
+	}
+	if node := tree.Current(); node == nil || node.Name != "AddItem" {
+		t.Errorf("cursor on %v, want AddItem", node)
+	}
+	if tree.Select("Nope") {
+		t.Error("Select(Nope) = true for a missing slice")
+	}
+}
+
+func TestTreeQuery(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, files, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	tree := tui.NewTreeState(&manifest, files)
+	tree.SetFlat(true)
+	all := len(tree.FlatView)
+	tree.SetFlat(false)
+
+	sliceNames := func() []string {
+		var names []string
+		for _, n := range tree.FlatView {
+			if n.Kind == tui.NodeSlice {
+				names = append(names, n.Name)
+			}
+		}
+		return names
+	}
+
+	tree.SetQuery("type:view")
+	names := sliceNames()
+	if len(names) == 0 {
+		t.Fatal("type:view matches no slice")
+	}
+	for _, n := range tree.FlatView {
+		if n.Kind == tui.NodeSlice && n.SliceType != "view" {
+			t.Errorf("type:view shows %s slice %s", n.SliceType, n.Name)
+		}
+		if n.Kind != tui.NodeSlice && len(n.Children) == 0 {
+			t.Errorf("type:view shows empty %s", n.Name)
+		}
+	}
+
+	// Words search names, not the type and status columns
+	tree.SetQuery("type:change status:specifying cart")
+	names = sliceNames()
+	for _, name := range names {
+		if !strings.Contains(strings.ToLower(name), "cart") {
+			t.Errorf("query with word cart shows %s", name)
+		}
+	}
+	if !slices.Contains(names, "ClearCart") {
+		t.Errorf("ClearCart missing from %v", names)
+	}
+
+	tree.SetQuery("status:done")
+	if names := sliceNames(); len(names) != 0 {
+		t.Errorf("status:done shows %v", names)
+	}
+
+	tree.SetQuery("")
+	tree.SetFlat(true)
+	if got := len(tree.FlatView); got != all {
+		t.Errorf("flat tree after clearing the query shows %d slices, want %d", got, all)
+	}
+
+	// Typing a query keystroke by keystroke, narrowing or not, ends where
+	// setting it at once does
+	for _, query := range []string{"item", "type:view cart", "cart it", "remove"} {
+		tree.SetQuery(query)
+		want := sliceNames()
+		tree.SetQuery("")
+		for i := range query {
+			tree.SetQuery(query[:i+1])
+		}
+		if got := sliceNames(); !slices.Equal(got, want) {
+			t.Errorf("typed query %q shows %v, want %v", query, got, want)
+		}
+	}
+	tree.SetQuery("cart item")
+	tree.SetQuery("cart")
+	if got := sliceNames(); !slices.Contains(got, "ClearCart") {
+		t.Errorf("widening the query back to cart shows %v", got)
+	}
+}
+
+func TestEventCatalog(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, files, images := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	catalog := board.EventCatalog(board.FlowSlices(manifest, files))
+
+	byType := map[string]board.CatalogEntry{}
+	for _, e := range catalog {
+		byType[e.Type] = e
+	}
+	added, ok := byType["ItemAdded"]
+	if !ok {
+		t.Fatalf("ItemAdded missing from catalog %v", catalog)
+	}
+	if !slices.Contains(added.Emitters, "AddItem") || len(added.Consumers) == 0 || added.Fields == nil {
+		t.Errorf("ItemAdded: %+v; want fields, emitted by AddItem, consumed", added)
+	}
+	if inv := byType["InventoryChanged"]; !slices.Contains(inv.Consumers, "OnInventoryChanged") {
+		t.Errorf("InventoryChanged consumers %v, want the automation it triggers", inv.Consumers)
+	}
+	if !slices.IsSortedFunc(catalog, func(a, b board.CatalogEntry) int { return strings.Compare(a.Type, b.Type) }) {
+		t.Error("catalog not sorted by event type")
+	}
+
+	dir := t.TempDir()
+	if _, err := board.WriteBoardFiles(dir, manifest, files, "examples", images); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	m, err := tui.NewIRModel(dir)
+	if err != nil {
+		t.Fatalf("model: %v", err)
+	}
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 200})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	out := model.View()
+	for _, want := range []string{"Event catalog", "EVENT: ItemAdded", "emitted by:  AddItem"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("catalog view missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMarkdownDocument(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, files, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	out := markdown.Document(manifest, files)
+
+	for _, want := range []string{
+		"# Shopping Cart\n",
+		"\n## Shopping\n",
+		"\n### Cart Items\n",
+		"\n#### AddItem (change)\n",
+		"**Trigger:** `POST /carts/{cartId}/items`",
+		"| `quantity` | `int` |",
+		"- `ItemAdded`\n",
+		"  - When `AddItem` with `{\"cartId\":\"abc\"}`\n",
+		"  - Then fails: already created\n",
+		"\n#### ViewCartItems (view)\n",
+		"**Read model:** `CartItemsView` (single)",
+		"**Trigger:** external event `InventoryChanged` from Inventory Context",
+		"> **Story:** (AddItem)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("document missing %q:\n%s", want, out)
+		}
+	}
+
+	// Stories are quoted under the slice they reference
+	story := strings.Index(out, "> **Story:** (ViewCartItems)")
+	if view := strings.Index(out, "#### ViewCartItems"); story < view {
+		t.Errorf("ViewCartItems story at %d, before its slice at %d", story, view)
+	}
+}
+
+func TestWriteBoardFilesCanonical(t *testing.T) {
+	write := func() string {
+		b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		manifest, files, _ := board.ReifyBoardFiles(b, []render.ValidationError{{Code: render.ErrCmdFieldSource, Message: `slice "AddItem" command: a < b && c > d`, Slice: "AddItem"}}, board.ReifyOptions{})
+		dir := t.TempDir()
+		if _, err := board.WriteBoardFiles(dir, manifest, files, "", nil); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		return dir
+	}
+	dirA, dirB := write(), write()
+
+	entries, err := os.ReadDir(dirA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		a, err := os.ReadFile(filepath.Join(dirA, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(filepath.Join(dirB, e.Name()))
+		if err != nil {
+			t.Fatalf("%s only written once: %v", e.Name(), err)
+		}
+		if !bytes.Equal(a, b) {
+			t.Errorf("%s differs between two reifies of the same board", e.Name())
+		}
+		if !bytes.HasSuffix(a, []byte("}\n")) && !bytes.HasSuffix(a, []byte("]\n")) {
+			t.Errorf("%s doesn't end with a newline", e.Name())
+		}
+	}
+
+	manifest, err := os.ReadFile(filepath.Join(dirA, "board.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(manifest, []byte("a < b && c > d")) {
+		t.Errorf("board.json escapes HTML characters:\n%s", manifest)
+	}
+
+	// Emits keep their declaration order
+	data, err := os.ReadFile(filepath.Join(dirA, "AddItem.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i, j := bytes.Index(data, []byte(`"type": "CartCreated"`)), bytes.Index(data, []byte(`"type": "ItemAdded"`)); i < 0 || j < i {
+		t.Errorf("AddItem emits out of declaration order:\n%s", data)
+	}
+}
+
+func TestLoadBoardFromPackageDirAndGlob(t *testing.T) {
+	want, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load file: %v", err)
+	}
+	for _, path := range []string{"examples", "examples/*.cue"} {
+		b, _, err := board.LoadBoardPermissive(path, "")
+		if err != nil {
+			t.Errorf("load %s: %v", path, err)
+			continue
+		}
+		// ItemAdded is declared in events.cue, next to the board
+		if b.Name != want.Name || len(b.Flow) != len(want.Flow) || !b.Value.LookupPath(cue.ParsePath("events.ItemAdded")).Exists() {
+			t.Errorf("load %s: board %q with %d flow items, want %q with %d and events from events.cue", path, b.Name, len(b.Flow), want.Name, len(want.Flow))
+		}
+		if got := board.SourceDir(path); got != "examples" {
+			t.Errorf("SourceDir(%s) = %q, want examples", path, got)
+		}
+	}
+
+	if _, _, err := board.LoadBoardPermissive("examples/*.nope", ""); err == nil || !strings.Contains(err.Error(), "matches no .cue file") {
+		t.Errorf("glob without match: err = %v", err)
+	}
+	if _, _, err := board.LoadBoardPermissive("*/*.cue", ""); err == nil || !strings.Contains(err.Error(), "outside") {
+		t.Errorf("glob across directories: err = %v", err)
+	}
+}
+
+func TestLoadBoardFromSource(t *testing.T) {
+	src := `package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		PaymentMade: {eventType: "PaymentMade", fields: {amount: int}, tags: []}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "Pay"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {amount: int}, path: "/pay"}}
+				command: {name: "Pay", fields: {amount: int}, query: {items: []}}
+				emits: [events.PaymentMade]
+			}]
+		}]
+	}]
+}
+`
+	b, warnings, err := board.LoadBoardPermissiveWithOptions("-", "", board.LoadOptions{Source: []byte(src)})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if b.Name != "Test" || len(b.Flow) != 1 || len(warnings) != 0 {
+		t.Errorf("loaded board %q with %d flow items and warnings %v, want Test with 1 and none", b.Name, len(b.Flow), warnings)
+	}
+
+	_, _, err = board.LoadBoardPermissiveWithOptions("-", "", board.LoadOptions{Source: []byte(strings.Replace(src, `name: "Test"`, `name: 42`, 1))})
+	if err == nil || !strings.Contains(err.Error(), "board.name") {
+		t.Errorf("load of an invalid source: error = %v, want a board.name build error", err)
+	}
+}
+
+func TestLoadBoardFromSourceMatchesFile(t *testing.T) {
+	dir, err := os.MkdirTemp(".", "from-source-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	src := strings.Replace(largeBoardSource(3), `name: "Do0"`, `name: "Do0"
+				image: "do0.png"`, 1)
+	file := filepath.Join(dir, "board.cue")
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fromFile, _, err := board.LoadBoardPermissive(file, "")
+	if err != nil {
+		t.Fatalf("load file: %v", err)
+	}
+	fromSource, _, err := board.LoadBoardFromSource(src, "")
+	if err != nil {
+		t.Fatalf("load source: %v", err)
+	}
+
+	if got, want := render.ValidateBoard(fromSource.Value), render.ValidateBoard(fromFile.Value); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateBoard from source = %v, want %v", got, want)
+	}
+	wantManifest, wantSlices, _ := board.ReifyBoardFiles(fromFile, nil, board.ReifyOptions{})
+	gotManifest, gotSlices, images := board.ReifyBoardFiles(fromSource, nil, board.ReifyOptions{})
+	if !reflect.DeepEqual(gotManifest, wantManifest) || !reflect.DeepEqual(gotSlices, wantSlices) {
+		t.Error("ReifyBoardFiles differs between the source and the file")
+	}
+	if !slices.Equal(images, []string{"do0.png"}) {
+		t.Fatalf("images = %v, want [do0.png]", images)
+	}
+
+	// No source directory: the image is skipped, not reported as failed
+	outdir := t.TempDir()
+	failed, err := board.WriteBoardFiles(outdir, gotManifest, gotSlices, "", images)
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("WriteBoardFiles = %v, %v, want no failures", failed, err)
+	}
+	if _, err := os.Stat(filepath.Join(outdir, "do0.png")); !os.IsNotExist(err) {
+		t.Errorf("image copied (stat error %v), want skipped", err)
+	}
+}
+
+func TestWriteBoardFilesMissingImage(t *testing.T) {
+	manifest := board.BoardManifest{Name: "Test"}
+
+	// Never there: reported as failed once the retries run out, the rest is written
+	srcDir, outdir := t.TempDir(), t.TempDir()
+	failed, err := board.WriteBoardFiles(outdir, manifest, nil, srcDir, []string{"missing.png"})
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if !slices.Equal(failed, []string{"missing.png"}) {
+		t.Errorf("failed = %v, want [missing.png]", failed)
+	}
+	if _, err := os.Stat(filepath.Join(outdir, "board.json")); err != nil {
+		t.Errorf("board.json not written: %v", err)
+	}
+
+	// Saved between two attempts, as an editor replacing the file does: copied
+	srcDir, outdir = t.TempDir(), t.TempDir()
+	saved := make(chan error)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		saved <- os.WriteFile(filepath.Join(srcDir, "late.png"), []byte("png"), 0o644)
+	}()
+	failed, err = board.WriteBoardFiles(outdir, manifest, nil, srcDir, []string{"late.png"})
+	if err := <-saved; err != nil {
+		t.Fatal(err)
+	}
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("WriteBoardFiles = %v, %v, want no failures", failed, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(outdir, "late.png")); err != nil || string(data) != "png" {
+		t.Errorf("late.png = %q, %v, want copied", data, err)
+	}
+}
+
+func TestCompactManifest(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	// storyPayloads counts the stories carrying an instance and emits
+	storyPayloads := func(opts board.ReifyOptions) (instances, emits int) {
+		manifest, _, _ := board.ReifyBoardFiles(b, nil, opts)
+		out, err := json.Marshal(manifest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded struct {
+			Flow []map[string]any `json:"flow"`
+		}
+		if err := json.Unmarshal(out, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range decoded.Flow {
+			if entry["kind"] != "story" {
+				continue
+			}
+			if _, ok := entry["instance"]; ok {
+				instances++
+			}
+			if _, ok := entry["emits"]; ok {
+				emits++
+			}
+			if entry["name"] == nil || entry["sliceRef"] == nil {
+				t.Errorf("story entry lost its TOC fields: %v", entry)
+			}
+		}
+		return instances, emits
+	}
+
+	if instances, emits := storyPayloads(board.ReifyOptions{}); instances == 0 || emits == 0 {
+		t.Errorf("default manifest has %d story instances and %d story emits, want both kept", instances, emits)
+	}
+	if instances, emits := storyPayloads(board.ReifyOptions{CompactManifest: true}); instances != 0 || emits != 0 {
+		t.Errorf("compact manifest has %d story instances and %d story emits, want none", instances, emits)
+	}
+}
+
+func TestBoardToJSON(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	out, err := b.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON: %v", err)
+	}
+	var bundle board.BoardBundle
+	if err := json.Unmarshal(out, &bundle); err != nil {
+		t.Fatalf("ToJSON output doesn't decode as a bundle: %v", err)
+	}
+
+	want := board.ReifyBoardBundle(b, nil, board.ReifyOptions{})
+	if bundle.Name != b.Name || len(bundle.Flow) != len(want.Flow) || len(bundle.Contexts) != len(want.Contexts) {
+		t.Errorf("manifest = %q with %d flow entries and %d contexts, want %q with %d and %d",
+			bundle.Name, len(bundle.Flow), len(bundle.Contexts), b.Name, len(want.Flow), len(want.Contexts))
+	}
+	if len(bundle.Slices) == 0 || len(bundle.Slices) != len(want.Slices) {
+		t.Errorf("bundle has %d slices, want %d", len(bundle.Slices), len(want.Slices))
+	}
+	for _, entry := range bundle.Flow {
+		if entry.Kind != "slice" {
+			continue
+		}
+		if data, ok := bundle.Slices[entry.File]; !ok || data["name"] != entry.Name {
+			t.Errorf("flow entry %s: slice %s not in the bundle", entry.Name, entry.File)
+		}
+	}
+}
+
+func TestLoadWithModuleRoot(t *testing.T) {
+	// The board imports em, but a stray cue.mod directory (without a
+	// module.cue) sits between it and the module: discovery stops there and
+	// can't resolve the import. It is loaded from outside the module, so
+	// the working directory doesn't help either.
+	root, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := os.MkdirTemp(root, "module-root-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	if err := os.MkdirAll(filepath.Join(dir, "cue.mod"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.CopyFS(filepath.Join(dir, "examples"), os.DirFS("examples")); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "examples", "cart.cue")
+	t.Chdir(t.TempDir())
+
+	if _, _, err := board.LoadBoardPermissive(file, ""); err == nil || !strings.Contains(err.Error(), "import failed") {
+		t.Errorf("load with the discovered module root: error %v, want the em import to fail", err)
+	}
+	b, warnings, err := board.LoadBoardPermissiveWithOptions(file, "", board.LoadOptions{ModuleRoot: root})
+	if err != nil {
+		t.Fatalf("load with ModuleRoot: %v", err)
+	}
+	if len(warnings) != 0 || b.Name == "" {
+		t.Errorf("board %q loaded with diagnostics %v", b.Name, warnings)
+	}
+}
+
+func TestExamplesValidateClean(t *testing.T) {
+	// The shipped example must pass emspec validate (exit 0): no errors and
+	// no warnings
+	_, warnings, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("examples/cart.cue has diagnostics:\n%s", strings.Join(warnings, "\n"))
+	}
+}
+
+func TestLoadBoardValidationErrors(t *testing.T) {
+	dir, err := os.MkdirTemp(".", "validation-errors-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A board that loads with a warning: Ghost is queried but never emitted
+	boardSrc := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		Done: {fields: {a: string}, tags: []}
+		Ghost: {fields: {a: string}, tags: []}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "Emit"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {a: string}, path: "/test"}}
+				command: {name: "Emit", fields: {a: string}, query: {items: [{types: [events.Ghost], tags: []}]}}
+				emits: [events.Done]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+	file := filepath.Join(dir, "board.cue")
+	if err := os.WriteFile(file, []byte(boardSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, warnings, err := board.LoadBoardPermissive(file, "")
+	if err != nil || len(warnings) == 0 {
+		t.Fatalf("LoadBoardPermissive = %v, %v, want warnings", warnings, err)
+	}
+	_, err = board.LoadBoard(file, "")
+	var verrs board.ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("LoadBoard error = %v, want ValidationErrors", err)
+	}
+	if !slices.Equal(verrs, warnings) {
+		t.Errorf("ValidationErrors = %v, want %v", verrs, warnings)
+	}
+	// The formatted messages, one per line
+	if want := "validation errors:\n" + strings.Join(warnings, "\n"); err.Error() != want {
+		t.Errorf("error = %q, want %q", err.Error(), want)
+	}
+
+	// Strict mode (-strict) writes the error manifest over a previous render
+	outdir := t.TempDir()
+	manifest, sliceFiles, _ := board.ReifyBoardFiles(b, b.Diagnostics, board.ReifyOptions{})
+	if _, err := board.WriteBoardFiles(outdir, manifest, sliceFiles, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := board.WriteBoardError(outdir, b.Name, b.Diagnostics); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(outdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if !slices.Equal(names, []string{"board.json", "diagnostics.json"}) {
+		t.Errorf("files = %v, want only the error manifest", names)
+	}
+	var got board.BoardManifest
+	data, err := os.ReadFile(filepath.Join(outdir, "board.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got.Errors, warnings) || len(got.Flow) != 0 {
+		t.Errorf("manifest errors = %v, flow = %v, want the warnings and no flow", got.Errors, got.Flow)
+	}
+}
+
+func TestReifyIndexPrefix(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	opts := board.ReifyOptions{IndexPrefix: true}
+	dir := t.TempDir()
+	manifest, files, _ := board.ReifyBoardFiles(b, b.Diagnostics, opts)
+	if _, err := board.WriteBoardFiles(dir, manifest, files, "", nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	// The TUI and web server reload the dir through the manifest's file names
+	reloaded, reloadedFiles, err := board.ReadBoardFiles(dir)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	width := len(strconv.Itoa(len(b.Flow)))
+	for _, entry := range reloaded.Flow {
+		if entry.Kind != "slice" {
+			continue
+		}
+		if prefix := fmt.Sprintf("%0*d_", width, entry.Index); !strings.HasPrefix(entry.File, prefix) {
+			t.Errorf("flow %d file = %q, want prefix %q", entry.Index, entry.File, prefix)
+		}
+		if reloadedFiles[entry.File] == nil {
+			t.Errorf("flow %d file %q not reloaded", entry.Index, entry.File)
+		}
+	}
+
+	// Re-reifying fewer slices (the first three instants, two slices and a
+	// story) narrows the prefix and removes every stale file
+	smaller := *b
+	smaller.Flow = b.Flow[:3]
+	manifest, files, _ = board.ReifyBoardFiles(&smaller, b.Diagnostics, opts)
+	if _, err := board.WriteBoardFiles(dir, manifest, files, "", nil); err != nil {
+		t.Fatalf("rewrite: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	want := []string{"0_AddItem.json", "2_RemoveItem.json", "board.json", "diagnostics.json"}
+	if !slices.Equal(got, want) {
+		t.Errorf("files after re-reify = %v, want %v", got, want)
+	}
+}
+
+func TestReifyHooks(t *testing.T) {
+	b, _, err := board.LoadBoardFromSource(largeBoardSource(3), "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	// Hooks stay registered for the rest of the test binary: only act
+	// while this test reifies
+	active := true
+	defer func() { active = false }()
+	var calls []string
+	board.RegisterReifyHook(func(name string, data map[string]any) map[string]any {
+		if active {
+			calls = append(calls, name)
+			data["hooked"] = 1
+		}
+		return data
+	})
+	board.RegisterReifyHook(func(name string, data map[string]any) map[string]any {
+		if active && data["hooked"] == 1 {
+			data["hooked"] = 2 // runs after the first hook
+		}
+		return data
+	})
+
+	manifest, files, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	if want := []string{"Do0", "Do1", "Do2", "ReadLast"}; !slices.Equal(calls, want) {
+		t.Errorf("hook calls = %v, want %v in flow order", calls, want)
+	}
+	for _, entry := range manifest.Flow {
+		if got := files[entry.File]["hooked"]; got != 2 {
+			t.Errorf("%s hooked = %v, want 2", entry.File, got)
+		}
+	}
+
+	active = false
+	_, files, _ = board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	for name, data := range files {
+		if _, ok := data["hooked"]; ok {
+			t.Errorf("%s hooked by an inactive hook", name)
+		}
+	}
+}
+
+func TestReifyBoardFilesIncremental(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	first := board.ReifyBoardFilesIncremental(b, nil, board.ReifyOptions{}, nil)
+	if len(first.Changed) != len(first.Slices) || len(first.Keep) != len(first.Slices) {
+		t.Fatalf("first render changed %d and kept %d of %d slice files, want all", len(first.Changed), len(first.Keep), len(first.Slices))
+	}
+	dir := t.TempDir()
+	if _, err := board.WriteBoardFilesIncremental(dir, first, "examples"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	second := board.ReifyBoardFilesIncremental(b, nil, board.ReifyOptions{}, first.Slices)
+	if len(second.Changed) != 0 {
+		t.Errorf("re-render of the same board changed %v, want nothing", slices.Collect(maps.Keys(second.Changed)))
+	}
+
+	prev := maps.Clone(first.Slices)
+	prev["AddItem.json"] = map[string]any{"name": "stale"}
+	third := board.ReifyBoardFilesIncremental(b, nil, board.ReifyOptions{}, prev)
+	if _, ok := third.Changed["AddItem.json"]; !ok || len(third.Changed) != 1 {
+		t.Errorf("changed = %v, want only AddItem.json", slices.Collect(maps.Keys(third.Changed)))
+	}
+
+	// Unchanged files deleted behind our back are written again
+	os.Remove(filepath.Join(dir, "RemoveItem.json"))
+	if _, err := board.WriteBoardFilesIncremental(dir, second, "examples"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "RemoveItem.json")); err != nil {
+		t.Errorf("RemoveItem.json not restored: %v", err)
+	}
+}
+
+func TestLoaderReload(t *testing.T) {
+	dir, err := os.MkdirTemp(".", "loader-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	file := filepath.Join(dir, "board.cue")
+	src := largeBoardSource(2)
+
+	loader := board.NewLoader(board.LoadOptions{})
+	for _, name := range []string{"Large", "Renamed"} {
+		if err := os.WriteFile(file, []byte(strings.Replace(src, `name: "Large"`, `name: "`+name+`"`, 1)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		b, _, err := loader.Reload(file, "")
+		if err != nil {
+			t.Fatalf("reload: %v", err)
+		}
+		if b.Name != name {
+			t.Errorf("reloaded board name = %q, want %q", b.Name, name)
+		}
+	}
+}
+
+func TestDiagnosticCode(t *testing.T) {
+	cases := []struct {
+		diag, code, severity string
+	}{
+		{`E102: slice "A" field "x" type mismatch`, "E102", render.SeverityError},
+		{`E601: event "x" doesn't match ^[A-Z]`, "E601", render.SeverityWarning},
+		{"build: unexpected token", "", render.SeverityError},
+	}
+	for _, c := range cases {
+		code := render.DiagnosticCode(c.diag)
+		if code != c.code {
+			t.Errorf("DiagnosticCode(%q) = %q, want %q", c.diag, code, c.code)
+		}
+		if sev := render.CodeSeverity(code); sev != c.severity {
+			t.Errorf("CodeSeverity(%q) = %q, want %q", code, sev, c.severity)
+		}
+	}
+}
+
+func TestErrorCodeCatalog(t *testing.T) {
+	// Every Err* constant of validate.go must be in the hand-written catalog,
+	// under its own name and with a severity, and nothing else may be
+	f, err := parser.ParseFile(token.NewFileSet(), "pkg/render/validate.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	consts := map[string]string{} // code -> constant name
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if !strings.HasPrefix(name.Name, "Err") || i >= len(vs.Values) {
+					continue
+				}
+				lit, ok := vs.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				code := strings.Trim(lit.Value, `"`)
+				if prev, dup := consts[code]; dup {
+					t.Errorf("%s and %s share code %s", prev, name.Name, code)
+				}
+				consts[code] = name.Name
+			}
+		}
+	}
+	if len(consts) == 0 {
+		t.Fatal("no Err* constants found in validate.go")
+	}
+
+	catalog := render.ErrorCodeCatalog()
+	seen := map[string]bool{}
+	for i, c := range catalog {
+		if seen[c.Code] {
+			t.Errorf("%s is in the catalog twice", c.Code)
+		}
+		seen[c.Code] = true
+		if i > 0 && catalog[i-1].Code >= c.Code {
+			t.Errorf("catalog not in code order: %s before %s", catalog[i-1].Code, c.Code)
+		}
+		name, ok := consts[c.Code]
+		if !ok {
+			t.Errorf("catalog entry %s (%s) has no constant in validate.go", c.Code, c.Name)
+			continue
+		}
+		if c.Name != name {
+			t.Errorf("catalog names %s %q, want %q", c.Code, c.Name, name)
+		}
+		if c.Severity != render.SeverityError && c.Severity != render.SeverityWarning {
+			t.Errorf("%s has severity %q", c.Code, c.Severity)
+		}
+		if c.Description == "" {
+			t.Errorf("%s has no description", c.Code)
+		}
+	}
+	for code, name := range consts {
+		if !seen[code] {
+			t.Errorf("%s (%s) is missing from the catalog in codes.go", name, code)
+		}
+	}
+}
+
+func TestSharedEventsFile(t *testing.T) {
+	dir, err := os.MkdirTemp(".", "shared-events-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	boardSrc := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		Local: {fields: {a: string}, tags: []}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "Emit"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {a: string, b: int}, path: "/test"}}
+				command: {name: "Emit", fields: {a: string, b: int}, query: {items: []}}
+				emits: [events.Local, events.Shared]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+	sharedSrc := `
+package shared
+
+events: {
+	Shared: {eventType: "Shared", fields: {b: int}, tags: []}
+	Local: {eventType: "Local", fields: {a: int}, tags: []}
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "board.cue"), []byte(boardSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sharedFile := filepath.Join(t.TempDir(), "shared.cue")
+	if err := os.WriteFile(sharedFile, []byte(sharedSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, warnings, err := board.LoadBoardPermissiveWithOptions(filepath.Join(dir, "board.cue"), "", board.LoadOptions{EventsFile: sharedFile})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "E306") || !strings.Contains(warnings[0], `"Local"`) {
+		t.Errorf("expected a single E306 for Local, got: %v", warnings)
+	}
+	if !b.Value.LookupPath(cue.ParsePath("events.Shared")).Exists() {
+		t.Error("expected shared event to be merged into board events")
+	}
+}
+
+func TestSliceComplexity(t *testing.T) {
+	change := map[string]any{
+		"kind": "slice",
+		"type": "change",
+		"command": map[string]any{
+			"fields": map[string]any{"cartId": "string", "itemId": "string"},
+			"query":  []any{map[string]any{"types": []any{"CartCreated"}}},
+		},
+		"emits":     []any{map[string]any{"type": "ItemAdded"}},
+		"scenarios": []any{map[string]any{"name": "a"}, map[string]any{"name": "b"}},
+	}
+	if got := board.SliceComplexity(change); got != 6 {
+		t.Errorf("change slice complexity = %d, want 6", got)
+	}
+
+	view := map[string]any{
+		"kind":           "slice",
+		"type":           "view",
+		"query":          []any{map[string]any{}, map[string]any{}},
+		"dependentQuery": map[string]any{"items": []any{map[string]any{}}},
+		"readModel":      map[string]any{"fields": map[string]any{"total": "int"}},
+	}
+	if got := board.SliceComplexity(view); got != 4 {
+		t.Errorf("view slice complexity = %d, want 4", got)
+	}
+
+	if got := board.SliceComplexity(map[string]any{"kind": "story"}); got != 0 {
+		t.Errorf("story complexity = %d, want 0", got)
+	}
+}
+
+// largeBoardSource generates a board of n change slices, each querying the
+// event emitted by the previous one, followed by a view over the last event.
+func largeBoardSource(n int) string {
+	var events, flow strings.Builder
+	for i := range n {
+		fmt.Fprintf(&events, "\t\tE%d: {eventType: \"E%d\", fields: {id: string, n: int}, tags: []}\n", i, i)
+		query := "[]"
+		if i > 0 {
+			query = fmt.Sprintf("[{types: [events.E%d], tags: []}]", i-1)
+		}
+		fmt.Fprintf(&flow, `{
+				kind: "slice"
+				name: "Do%d"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {id: string}, body: {n: int}, path: "/things%d/{id}"}}
+				command: {name: "Do%d", fields: {id: string, n: int}, query: {items: %s}}
+				emits: [events.E%d]
+				scenarios: []
+			},
+			`, i, i, i, query, i)
+	}
+	fmt.Fprintf(&flow, `{
+				kind: "slice"
+				name: "ReadLast"
+				type: "view"
+				actor: {name: "User"}
+				endpoint: {verb: "GET", params: {}, body: {}, path: "/last"}
+				readModel: {name: "Last", cardinality: "single", persistence: "transient", fields: {id: string, n: int}}
+				query: {items: [{types: [events.E%d], tags: []}]}
+				scenarios: []
+			}`, n-1)
+
+	return fmt.Sprintf(`
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Large"
+	tags: {}
+	events: {
+%s	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+			%s,
+			]
+		}]
+	}]
+}
+`, events.String(), flow.String())
+}
+
+// writeLargeBoard writes a generated board of n slices in the module and
+// returns its file.
+func writeLargeBoard(b *testing.B, n int) string {
+	b.Helper()
+	dir, err := os.MkdirTemp(".", "large-board-")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { os.RemoveAll(dir) })
+	file := filepath.Join(dir, "board.cue")
+	if err := os.WriteFile(file, []byte(largeBoardSource(n)), 0o644); err != nil {
+		b.Fatal(err)
+	}
+	return file
+}
+
+// loadLargeBoard loads a generated board of n slices.
+func loadLargeBoard(b *testing.B, n int) *board.Board {
+	b.Helper()
+	brd, _, err := board.LoadBoardPermissive(writeLargeBoard(b, n), "")
+	if err != nil {
+		b.Fatalf("load: %v", err)
+	}
+	return brd
+}
+
+func BenchmarkReifyLargeBoard(b *testing.B) {
+	brd := loadLargeBoard(b, 200)
+	for b.Loop() {
+		board.ReifyBoardFiles(brd, nil, board.ReifyOptions{})
+	}
+}
+
+func BenchmarkLoadLargeBoard(b *testing.B) {
+	file := writeLargeBoard(b, 200)
+	for b.Loop() {
+		if _, _, err := board.LoadBoardPermissive(file, ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoaderReloadLargeBoard(b *testing.B) {
+	file := writeLargeBoard(b, 200)
+	loader := board.NewLoader(board.LoadOptions{})
+	for b.Loop() {
+		if _, _, err := loader.Reload(file, ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkTreeQuery types a search into the tree of a 500-slice board,
+// one keystroke at a time, then clears it.
+func BenchmarkTreeQuery(b *testing.B) {
+	manifest := board.BoardManifest{Name: "Large"}
+	files := make(map[string]map[string]any)
+	types := []string{"change", "view", "automation"}
+	for ci := range 5 {
+		ctx := board.ContextEntry{Name: fmt.Sprintf("Context%d", ci)}
+		for hi := range 10 {
+			chap := board.ChapterEntry{Name: fmt.Sprintf("Chapter%d", hi)}
+			for si := range 10 {
+				idx := len(manifest.Flow)
+				name := fmt.Sprintf("Handle%sItem%d", []string{"Cart", "Order", "Stock"}[si%3], idx)
+				file := name + ".json"
+				manifest.Flow = append(manifest.Flow, board.FlowEntry{Index: idx, Kind: "slice", Type: types[idx%3], Name: name, File: file})
+				files[file] = map[string]any{"name": name, "type": types[idx%3], "devstatus": render.DevStatuses[idx%len(render.DevStatuses)]}
+				chap.FlowIndices = append(chap.FlowIndices, idx)
+			}
+			ctx.Chapters = append(ctx.Chapters, chap)
+		}
+		manifest.Contexts = append(manifest.Contexts, ctx)
+	}
+	tree := tui.NewTreeState(&manifest, files)
+	query := "status:done handlecartitem4"
+
+	for b.Loop() {
+		for i := range query {
+			tree.SetQuery(query[:i+1])
+		}
+		tree.SetQuery("")
+	}
+}
+
+func TestIRHandlerGzipETag(t *testing.T) {
+	dir := t.TempDir()
+	content := []byte(`{"name": "Board", "flow": []}` + strings.Repeat(" ", 1000))
+	if err := os.WriteFile(filepath.Join(dir, "board.json"), content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.StripPrefix("/.board/", web.IRHandler(dir)))
+	defer srv.Close()
+
+	get := func(encoding, etag string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest("GET", srv.URL+"/.board/board.json", nil)
+		// Set explicitly, the transport then leaves the body compressed
+		req.Header.Set("Accept-Encoding", encoding)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	plain := get("identity", "")
+	body, _ := io.ReadAll(plain.Body)
+	etag := plain.Header.Get("ETag")
+	if plain.StatusCode != http.StatusOK || !bytes.Equal(body, content) || !strings.HasPrefix(etag, `"`) || plain.Header.Get("Content-Encoding") != "" {
+		t.Fatalf("plain GET: %s, etag %s, %d bytes", plain.Status, etag, len(body))
+	}
+
+	zipped := get("gzip, deflate", "")
+	zetag := zipped.Header.Get("ETag")
+	if zipped.Header.Get("Content-Encoding") != "gzip" || zetag == etag || zetag == "" {
+		t.Fatalf("gzip GET: encoding %q, etag %s (plain %s)", zipped.Header.Get("Content-Encoding"), zetag, etag)
+	}
+	zr, err := gzip.NewReader(zipped.Body)
+	if err != nil {
+		t.Fatalf("gzip body: %v", err)
+	}
+	if unzipped, _ := io.ReadAll(zr); !bytes.Equal(unzipped, content) {
+		t.Errorf("gunzipped body differs from the file")
+	}
+
+	// Unchanged: 304 for either representation's tag
+	if resp := get("identity", etag); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("If-None-Match with the ETag: %s, want 304", resp.Status)
+	}
+	if resp := get("gzip", zetag); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("If-None-Match with the gzip ETag: %s, want 304", resp.Status)
+	}
+
+	// Changed: a new tag and the new content
+	time.Sleep(10 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(dir, "board.json"), []byte(`{"name": "Renamed"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resp := get("identity", etag)
+	body, _ = io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != `{"name": "Renamed"}` || resp.Header.Get("ETag") == etag {
+		t.Errorf("changed file: %s, etag %s, body %s", resp.Status, resp.Header.Get("ETag"), body)
+	}
+
+	// Missing files and other files go to the file server
+	missing, _ := http.Get(srv.URL + "/.board/nope.json")
+	missing.Body.Close()
+	if missing.StatusCode != http.StatusNotFound {
+		t.Errorf("missing file: %s, want 404", missing.Status)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "mockup.png"), []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if resp := get("gzip", ""); resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Errorf("board.json no longer gzipped")
+	}
+	req, _ := http.NewRequest("GET", srv.URL+"/.board/mockup.png", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	image, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	image.Body.Close()
+	if image.StatusCode != http.StatusOK || image.Header.Get("Content-Encoding") != "" {
+		t.Errorf("image: %s, encoding %q, want it as is", image.Status, image.Header.Get("Content-Encoding"))
+	}
+}
+
+func TestGoScenarioTests(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, files, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	src, err := gotests.Tests("cart", board.FlowSlices(manifest, files))
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "slices_test.go", src, 0); err != nil {
+		t.Fatalf("generated code doesn't parse: %v\n%s", err, src)
+	}
+	for _, want := range []string{
+		"func TestAddItem(t *testing.T)",
+		`name: "Err: duplicate cart creation",`,
+		`{Type: "CartCreated", Values: map[string]any{"cartId": "abc"}},`,
+		`wantErr: "already created",`,
+		`t.Skip("TODO: replay tt.given, then run AddItem with tt.when")`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code missing %q", want)
+		}
+	}
+	// Views have no command to run
+	if strings.Contains(string(src), "func TestViewCartItems(") {
+		t.Error("view slices should not get a test")
+	}
+}
+
+func TestWatchReturnsOnCancel(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	changed := make(chan struct{}, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- board.Watch(ctx, []string{dir}, func() { changed <- struct{}{} }, board.WatchOptions{Settle: 10 * time.Millisecond})
+	}()
+
+	// Wait for a regeneration so the watch is known to be set up
+	deadline := time.After(5 * time.Second)
+	tick := time.NewTicker(50 * time.Millisecond)
+	defer tick.Stop()
+	for waiting := true; waiting; {
+		select {
+		case <-changed:
+			waiting = false
+		case <-tick.C:
+			if err := os.WriteFile(filepath.Join(dir, "board.cue"), []byte("package x\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		case err := <-done:
+			t.Fatalf("Watch returned early: %v", err)
+		case <-deadline:
+			t.Fatal("no change reported")
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Watch: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch didn't return after cancel")
+	}
+}
+
+func TestWatchDebouncesRenameSave(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "board.cue")
+	if err := os.WriteFile(target, []byte("package x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var mu sync.Mutex
+	count := 0
+	go board.Watch(ctx, []string{dir}, func() {
+		mu.Lock()
+		count++
+		mu.Unlock()
+	}, board.WatchOptions{Settle: 100 * time.Millisecond})
+	regenerations := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return count
+	}
+
+	// Touch the file until the watch is set up (slower than Settle, or the
+	// debounce never fires), then let it settle
+	deadline := time.Now().Add(5 * time.Second)
+	for regenerations() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("no change reported")
+		}
+		if err := os.WriteFile(target, []byte("package x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	time.Sleep(300 * time.Millisecond)
+	before := regenerations()
+
+	// An atomic save: write a temporary file, then rename it over the board
+	tmp := filepath.Join(dir, ".board.cue.swp")
+	if err := os.WriteFile(tmp, []byte("package x\n\nfoo: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	if got := regenerations() - before; got != 1 {
+		t.Errorf("rename save regenerated %d times, want 1", got)
+	}
+}
+
+func TestRenderSliceStyled(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, files, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	roles := map[render.Role]bool{}
+	color := func(role render.Role, s string) string {
+		roles[role] = true
+		return fmt.Sprintf("\x1b[1;3%dm%s\x1b[0m", int(role)%7+1, s)
+	}
+	escapes := regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+	// Styling changes nothing but the escape sequences, wrapped or not
+	for _, opts := range []render.RenderOptions{{Width: 100}, {Width: 40, Wrap: true}, {Width: 24}} {
+		for _, entry := range manifest.Flow {
+			if entry.File == "" {
+				continue
+			}
+			plain, err := render.RenderSliceIRWithOptions(files[entry.File], opts)
+			if err != nil {
+				t.Fatalf("render %s: %v", entry.File, err)
+			}
+			styledOpts := opts
+			styledOpts.Style = color
+			styled, _ := render.RenderSliceIRWithOptions(files[entry.File], styledOpts)
+			if got := escapes.ReplaceAllString(styled, ""); got != plain {
+				t.Errorf("%s at width %d (wrap %v) styled differs from plain:\n%s\n---\n%s", entry.File, opts.Width, opts.Wrap, got, plain)
+			}
+		}
+	}
+	for _, role := range []render.Role{render.RoleChangeSlice, render.RoleAutomationSlice, render.RoleViewSlice, render.RoleTrigger, render.RoleEmit, render.RoleQuery, render.RoleReadModel, render.RoleSuccess, render.RoleError} {
+		if !roles[role] {
+			t.Errorf("role %d never styled", role)
+		}
+	}
+}
+
+func TestRenderSliceIRPlain(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, files, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	boxDrawing := render.TopLeft + render.TopRight + render.BottomLeft + render.BottomRight + render.Horizontal + render.Vertical + render.LeftT + render.RightT
+
+	// nonEmpty returns the non-blank lines of out, trimmed on the right
+	nonEmpty := func(out string) []string {
+		var lines []string
+		for line := range strings.SplitSeq(out, "\n") {
+			if line = strings.TrimRight(line, " "); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+
+	for _, entry := range manifest.Flow {
+		if entry.File == "" {
+			continue
+		}
+		data := files[entry.File]
+		plain, err := render.RenderSliceIRPlain(data, 0)
+		if err != nil {
+			t.Fatalf("render %s: %v", entry.File, err)
+		}
+		if strings.ContainsAny(plain, boxDrawing) {
+			t.Errorf("%s: plain rendering has box drawing:\n%s", entry.File, plain)
+		}
+
+		// Same text as in the box, wide enough not to truncate
+		boxed, _ := render.RenderSliceIR(data, 300)
+		var want []string
+		for _, line := range nonEmpty(boxed) {
+			if strings.HasPrefix(line, render.Vertical) {
+				line = strings.TrimSuffix(strings.TrimPrefix(line, render.Vertical), render.Vertical)
+				line = strings.Replace(line, "  "+render.Vertical+"  Actor:", "  |  Actor:", 1)
+				if line = strings.TrimRight(line, " "); line != "" {
+					want = append(want, line)
+				}
+			}
+		}
+		if got := nonEmpty(plain); !slices.Equal(got, want) {
+			t.Errorf("%s: plain text differs from the box content:\n%s\n---\n%s", entry.File, strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+
+		// Wrapping keeps lines within the width, breaking at spaces
+		wrapped, _ := render.RenderSliceIRPlain(data, 60)
+		for line := range strings.SplitSeq(wrapped, "\n") {
+			if w := lipgloss.Width(line); w > 60 && strings.Contains(strings.TrimSpace(line), " ") {
+				t.Errorf("%s: wrapped line is %d wide: %q", entry.File, w, line)
+			}
+		}
+	}
+}
+
+// twoContextBoardSrc has one slice in each of two contexts, for the TUI tests.
+const twoContextBoardSrc = `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		EventA: {eventType: "EventA", fields: {}, tags: []}
+	}
+	actors: {User: {name: "User"}}
+	contexts: [{
+		name: "Ordering"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "PlaceOrder"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/orders"}}
+				command: {name: "PlaceOrder", fields: {}, query: {items: []}}
+				emits: [events.EventA]
+				scenarios: []
+			}]
+		}]
+	}, {
+		name: "Shipping"
+		chapters: [{
+			name: "Main"
+			flow: [{
+				kind: "slice"
+				name: "ShipOrder"
+				type: "change"
+				actor: {name: "User"}
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/shipments"}}
+				command: {name: "ShipOrder", fields: {}, query: {items: []}}
+				emits: [events.EventA]
+				scenarios: []
+			}]
+		}]
+	}]
+}
+`
+
+// newTwoContextModel returns a TUI model, sized 100x40, over the IR of
+// twoContextBoardSrc.
+func newTwoContextModel(t *testing.T) tea.Model {
+	t.Helper()
+	b, _, err := board.LoadBoardFromSource(twoContextBoardSrc, "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, files, images := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	dir := t.TempDir()
+	if _, err := board.WriteBoardFiles(dir, manifest, files, "", images); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	m, err := tui.NewIRModel(dir)
+	if err != nil {
+		t.Fatalf("model: %v", err)
+	}
+	model, _ := tea.Model(m).Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	return model
+}
+
+func TestTUIContextFilter(t *testing.T) {
+	model := newTwoContextModel(t)
+	key := func(k string) {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+
+	// In the flat list, c cycles through each context, then back to all
+	key("t")
+	for _, tt := range []struct {
+		context string
+		visible []string
+	}{
+		{"Ordering", []string{"PlaceOrder"}},
+		{"Shipping", []string{"ShipOrder"}},
+		{"", []string{"PlaceOrder", "ShipOrder"}},
+	} {
+		key("c")
+		out := model.View()
+		for _, name := range []string{"PlaceOrder", "ShipOrder"} {
+			if got := strings.Contains(out, "[CMD] "+name); got != slices.Contains(tt.visible, name) {
+				t.Errorf("context %q: %s shown = %v, want %v:\n%s", tt.context, name, got, !got, out)
+			}
+		}
+		if tt.context != "" && !strings.Contains(out, "context: "+tt.context) || tt.context == "" && strings.Contains(out, "context: ") {
+			t.Errorf("context %q: footer doesn't show the filter:\n%s", tt.context, out)
+		}
+	}
+
+	// The tree hides the other contexts altogether
+	key("t")
+	key("c")
+	if out := model.View(); !strings.Contains(out, "Ordering") || strings.Contains(out, "Shipping") {
+		t.Errorf("tree filtered on Ordering should only show it:\n%s", out)
+	}
+}
+
+func TestTUITreeToggle(t *testing.T) {
+	model := newTwoContextModel(t)
+	key := func(k string) {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+	enter := func() {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+
+	// The tree starts with the contexts expanded and their chapters collapsed
+	if out := model.View(); !strings.Contains(out, "▼ Ordering") || !strings.Contains(out, "▶ Main") || strings.Contains(out, "PlaceOrder") {
+		t.Errorf("tree should show contexts and collapsed chapters:\n%s", out)
+	}
+
+	// t flattens it to the slices
+	key("t")
+	if out := model.View(); !strings.Contains(out, "[CMD] PlaceOrder") || !strings.Contains(out, "[CMD] ShipOrder") || strings.Contains(out, "Ordering") {
+		t.Errorf("flat list should show only the slices:\n%s", out)
+	}
+
+	// Back in the tree, the highlighted slice stays selected, its chapter
+	// expanded, and enter opens its detail
+	key("t")
+	if out := model.View(); !strings.Contains(out, "▼ Ordering") || !strings.Contains(out, "[CMD] PlaceOrder") || strings.Contains(out, "ShipOrder") {
+		t.Errorf("t should restore the tree around the highlighted slice:\n%s", out)
+	}
+	enter()
+	if out := model.View(); !strings.Contains(out, "SLICE: PlaceOrder") {
+		t.Errorf("enter on a slice should open its detail:\n%s", out)
+	}
+}
+
+func TestTUISplitView(t *testing.T) {
+	model := newTwoContextModel(t)
+	key := func(k string) {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+	// rowWith returns the first line of the view containing s
+	rowWith := func(out, s string) string {
+		for _, line := range strings.Split(out, "\n") {
+			if strings.Contains(line, s) {
+				return line
+			}
+		}
+		return ""
+	}
+	key("t")
+
+	// Narrow: the tree only
+	if out := model.View(); strings.Contains(out, "SLICE:") {
+		t.Errorf("a 100-column board view shouldn't show a detail:\n%s", out)
+	}
+
+	// Wide: the highlighted slice's detail on the right of the tree,
+	// following the cursor
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 200, Height: 40})
+	out := model.View()
+	if row := rowWith(out, "[CMD] PlaceOrder"); !strings.Contains(row, "│") {
+		t.Errorf("tree row should be followed by the detail pane: %q", row)
+	}
+	if !strings.Contains(out, "SLICE: PlaceOrder") || strings.Contains(out, "SLICE: ShipOrder") {
+		t.Errorf("split view should show the highlighted slice:\n%s", out)
+	}
+	key("j")
+	out = model.View()
+	if !strings.Contains(out, "SLICE: ShipOrder") || strings.Contains(out, "SLICE: PlaceOrder") {
+		t.Errorf("the detail should follow the cursor:\n%s", out)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if w := lipgloss.Width(line); w > 200 {
+			t.Errorf("line of width %d in a 200-column split view: %q", w, line)
+		}
+	}
+}
+
+func TestTUIHelpOverlay(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, files, images := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	dir := t.TempDir()
+	if _, err := board.WriteBoardFiles(dir, manifest, files, "examples", images); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	m, err := tui.NewIRModel(dir)
+	if err != nil {
+		t.Fatalf("model: %v", err)
+	}
+	var model tea.Model = m
+	key := func(k string) {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 200})
+
+	// The help overlays the catalog and gives it back on close
+	key("C")
+	key("?")
+	out := model.View()
+	for _, want := range []string{"> Help", "KEYS", "[AUTO]", "specifying, todo, doing or done", "E101"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("help missing %q:\n%s", want, out)
+		}
+	}
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 200})
+	if out := model.View(); !strings.Contains(out, "> Help") {
+		t.Errorf("a resize should keep the help open:\n%s", out)
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if out := model.View(); !strings.Contains(out, "> Event catalog") {
+		t.Errorf("esc should restore the catalog:\n%s", out)
+	}
+
+	key("q")
+	key("?")
+	key("?")
+	if out := model.View(); strings.Contains(out, "> Help") || !strings.Contains(out, "?: help") {
+		t.Errorf("? should toggle the help off, back to the board:\n%s", out)
+	}
+}
+
+func TestTUIExportSlice(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, files, images := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	dir := t.TempDir()
+	if _, err := board.WriteBoardFiles(dir, manifest, files, "examples", images); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	m, err := tui.NewIRModel(dir)
+	if err != nil {
+		t.Fatalf("model: %v", err)
+	}
+	var model tea.Model = m
+	key := func(k string) {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+	enter := func() {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 200})
+
+	// Open AddItem: the flat list, searched for it
+	key("t")
+	key("/")
+	for _, r := range "type:change AddItem" {
+		key(string(r))
+	}
+	enter()
+	enter()
+	if out := model.View(); !strings.Contains(out, "SLICE: AddItem") {
+		t.Fatalf("AddItem should be open:\n%s", out)
+	}
+
+	out := t.TempDir()
+	t.Chdir(out)
+	key("w")
+	if view := model.View(); !strings.Contains(view, "wrote "+filepath.Join(out, "AddItem.txt")) {
+		t.Errorf("footer should confirm the write:\n%s", view)
+	}
+	text, err := os.ReadFile(filepath.Join(out, "AddItem.txt"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.Contains(string(text), "SLICE: AddItem (change)") || strings.Contains(string(text), "\x1b") {
+		t.Errorf("AddItem.txt should be the plain box:\n%s", text)
+	}
+
+	key("W")
+	got, err := os.ReadFile(filepath.Join(out, "AddItem.json"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want, _ := os.ReadFile(filepath.Join(dir, manifest.Flow[slices.IndexFunc(manifest.Flow, func(e board.FlowEntry) bool {
+		return e.Kind == "slice" && e.Name == "AddItem"
+	})].File))
+	if !bytes.Equal(got, want) {
+		t.Errorf("AddItem.json should be the slice IR file:\n%s", got)
+	}
+
+	key("j")
+	if view := model.View(); strings.Contains(view, "wrote ") {
+		t.Errorf("the confirmation should go away on the next key:\n%s", view)
+	}
+}
+
+func TestInvalidViewQueriesLaterEvent(t *testing.T) {
+	src := `
+package test
+
+import "github.com/err0r500/event-modeling-dcb-spec/em"
+
+board: em.#Board & {
+	name: "Test"
+	tags: {}
+	events: {
+		EventA: {eventType: "EventA", fields: {userId: string}, tags: []}
+		EventB: {eventType: "EventB", fields: {userId: string}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
+	}
+	contexts: [{
+		name: "Default"
+		chapters: [{
+			name: "Main"
+			flow: [
+				{
+					kind: "slice"
+					name: "EmitA"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/a"}}
+					command: {name: "EmitA", fields: {userId: string}, query: {items: []}}
+					emits: [events.EventA]
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "ReadUser"
+					type: "view"
+					actor: {name: "User"}
+					endpoint: {verb: "GET", params: {}, body: {}, path: "/users"}
+					readModel: {
+						name: "UserView"
+						cardinality: "single"
+						fields: {userId: string}
 					}
-					emits: []
+					query: {items: [{types: [events.EventA, events.EventB], tags: []}]}
+					scenarios: []
+				},
+				{
+					kind: "slice"
+					name: "EmitB"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {userId: string}, body: {}, path: "/b"}}
+					command: {name: "EmitB", fields: {userId: string}, query: {items: []}}
+					emits: [events.EventB]
 					scenarios: []
 				},
 			]
@@ -1737,26 +6192,32 @@ board: em.#Board & {
 	}]
 }
 `
-	assertInvalid(t, src, "empty disjunction")
+	assertInvalidGo(t, src, `view "ReadUser" queries event "EventB", first emitted by slice "EmitB"`, "E201")
+
+	// EventA comes from an earlier slice
+	res := buildValue(t, src)
+	for _, e := range render.ValidateBoard(res.value.LookupPath(cue.ParsePath("board"))) {
+		if strings.HasPrefix(e, render.ErrEventOrdering) && strings.Contains(e, `"EventA"`) {
+			t.Errorf("unexpected ordering error: %s", e)
+		}
+	}
 }
 
-func TestInvalidDependentQueryFromExtractInPrimaryQuery(t *testing.T) {
+func TestInvalidEmitFieldSource(t *testing.T) {
 	src := `
 package test
 
 import "github.com/err0r500/event-modeling-dcb-spec/em"
 
-_tags: {
-	cart_id: em.#Tag & {name: "cart_id", param: "cartId", type: string}
-}
-
 board: em.#Board & {
 	name: "Test"
-	tags: _tags
+	tags: {}
 	events: {
-		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string}, tags: [_tags.cart_id]}
+		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string, price: int, addedAt: string, sku: string}, tags: []}
+	}
+	actors: {
+		User: {name: "User"}
 	}
-	actors: {User: {name: "User"}}
 	contexts: [{
 		name: "Default"
 		chapters: [{
@@ -1764,27 +6225,15 @@ board: em.#Board & {
 			flow: [
 				{
 					kind: "slice"
-					name: "EmitItem"
-					type: "change"
-					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/items"}}
-					command: {name: "AddItem", fields: {cartId: string}, query: {items: []}}
-					emits: [events.ItemAdded]
-					scenarios: []
-				},
-				{
-					kind: "slice"
-					name: "SubmitCart"
+					name: "AddItem"
 					type: "change"
 					actor: {name: "User"}
-					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {}, path: "/cart/{cartId}/submit"}}
-					command: {
-						name: "SubmitCart"
-						fields: {cartId: string}
-						// fromExtract in primary query - not allowed
-						query: {items: [{types: [events.ItemAdded], tags: [{tag: _tags.cart_id, fromExtract: "something"}]}]}
-					}
-					emits: []
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {cartId: string}, body: {productId: string}, path: "/carts/{cartId}/items"}}
+					command: {name: "AddItem", fields: {cartId: string, productId: string}, query: {items: []}}
+					emits: [events.ItemAdded & {
+						mapping: {sku: command.fields.productId}
+						computed: {addedAt: "time of the request"}
+					}]
 					scenarios: []
 				},
 			]
@@ -1792,75 +6241,87 @@ board: em.#Board & {
 	}]
 }
 `
-	assertInvalidGo(t, src, "SubmitCart", "fromExtract only allowed in dependentQuery")
-}
-
-// --- Union type tests ---
+	want := `E103: slice "AddItem" emit "ItemAdded": field "price" is not a command field, nor in the emit mapping or computed`
+	if found := emitSourceDiagnostics(t, src); len(found) != 1 || !strings.HasPrefix(found[0], want) {
+		t.Errorf("E103 diagnostics = %q, want just %q", found, want)
+	}
 
-// TestInvalidUnionTypeEndpointIncompatibleWithCommand checks that when an endpoint field has
-// a union type (int | string) and the command field has a completely incompatible type (float),
-// a type mismatch error is reported. CUE detects this as an empty disjunction; the fix in
-// formatSingleError ensures the error is surfaced rather than silently dropped.
-func TestInvalidUnionTypeEndpointIncompatibleWithCommand(t *testing.T) {
-	src := `
+	// Automation emits are checked the same way, consumed read models included
+	auto := `
 package test
 
 import "github.com/err0r500/event-modeling-dcb-spec/em"
 
+_events: {
+	CartClosed: em.#Event & {eventType: "CartClosed", fields: {cartId: string, reason: string, closedAt: string}, tags: []}
+}
+
 board: em.#Board & {
 	name: "Test"
 	tags: {}
-	events: {
-		TestEvent: {eventType: "TestEvent", fields: {x: int | string}, tags: []}
-	}
-	actors: {
-		User: {name: "User"}
-	}
+	events: _events
+	actors: {}
 	contexts: [{
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [{
-				kind: "slice"
-				name: "TestSlice"
-				type: "change"
-				actor: {name: "User"}
-				trigger: {kind: "endpoint", endpoint: {
-					verb: "POST"
-					params: {x: int | string}  // union type
-					body: {}
-					path: "/test"
-				}}
-				command: {
-					name: "TestCmd"
-					fields: {x: float}  // float: incompatible with int | string
-					query: {items: []}
-				}
-				emits: [events.TestEvent]
-				scenarios: []
-			}]
+			flow: [
+				em.#AutomationSlice & {
+					name: "AutoCloseCart"
+					trigger: {kind: "externalEvent", externalEvent: {name: "PaymentTimedOut", source: "payments", fields: {cartId: string}}}
+					command: {fields: {cartId: string}, query: {items: []}}
+					emits: [_events.CartClosed & {computed: {closedAt: "now"}}]
+				},
+			]
 		}]
 	}]
 }
 `
-	assertInvalid(t, src, "slice_TestSlice_field_x_type")
+	want = `E103: automation "AutoCloseCart" emit "CartClosed": field "reason" is not a command field, nor in a consumed read model, the emit mapping or computed`
+	if found := emitSourceDiagnostics(t, auto); len(found) != 1 || !strings.HasPrefix(found[0], want) {
+		t.Errorf("automation E103 diagnostics = %q, want just %q", found, want)
+	}
 }
 
-// TestInvalidUnionTypeCommandTooNarrow checks that when an endpoint provides a union type
-// (int | string) but the command only declares a subset (string), a Go-side subsumption
-// error is reported. CUE's unification (int|string & string = string) passes, but the
-// command is too narrow to safely handle all values the endpoint might provide.
-func TestInvalidUnionTypeCommandTooNarrow(t *testing.T) {
+// emitSourceDiagnostics returns the E103 diagnostics of a board source. The
+// CUE constraint behind E103 is concrete, so they may come from the build
+// error as well as from validation.
+func emitSourceDiagnostics(t *testing.T, src string) []string {
+	t.Helper()
+	res := buildValue(t, src)
+	var diags []string
+	if res.err != nil {
+		diags = strings.Split(render.FormatCUEError(res.err), "\n")
+	} else {
+		diags = render.ValidateBoard(res.value.LookupPath(cue.ParsePath("board")))
+	}
+	var found []string
+	for _, d := range diags {
+		if render.DiagnosticCode(d) == render.ErrEmitFieldSource {
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+func TestInvalidEventTagField(t *testing.T) {
 	src := `
 package test
 
 import "github.com/err0r500/event-modeling-dcb-spec/em"
 
+_tags: [Name=string]: em.#Tag & {name: Name}
+_tags: {
+	cart_id: {param: "cartId", type: string}
+	item_id: {param: "itemId", type: int}
+}
+
 board: em.#Board & {
 	name: "Test"
-	tags: {}
+	tags: _tags
 	events: {
-		TestEvent: {eventType: "TestEvent", fields: {x: string}, tags: []}
+		CartCreated: {eventType: "CartCreated", fields: {shopperId: string}, tags: [_tags.cart_id]}
+		ItemAdded: {eventType: "ItemAdded", fields: {cartId: string, itemId: string}, tags: [_tags.cart_id, _tags.item_id]}
 	}
 	actors: {
 		User: {name: "User"}
@@ -1869,45 +6330,50 @@ board: em.#Board & {
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [{
-				kind: "slice"
-				name: "TestSlice"
-				type: "change"
-				actor: {name: "User"}
-				trigger: {kind: "endpoint", endpoint: {
-					verb: "POST"
-					params: {x: int | string}  // union: endpoint can provide int OR string
-					body: {}
-					path: "/test"
-				}}
-				command: {
-					name: "TestCmd"
-					fields: {x: string}  // too narrow: can't handle ints from the endpoint
-					query: {items: []}
-				}
-				emits: [events.TestEvent]
-				scenarios: []
-			}]
+			flow: [
+				{
+					kind: "slice"
+					name: "CreateCart"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {shopperId: string}, path: "/carts"}}
+					command: {name: "CreateCart", fields: {shopperId: string}, query: {items: []}}
+					emits: [events.CartCreated]
+					scenarios: []
+				},
+			]
 		}]
 	}]
 }
 `
-	assertInvalidGo(t, src, "TestSlice", "type too narrow")
+	assertInvalidGo(t, src, `event "CartCreated" carries tag "cart_id" but has no field "cartId"`, "E303")
+	assertInvalidGo(t, src, `event "ItemAdded" field "itemId" is string, but tag "item_id" has type int`, "E303")
+
+	// ItemAdded supplies cartId as a string, as the tag wants
+	res := buildValue(t, src)
+	for _, e := range render.ValidateBoard(res.value.LookupPath(cue.ParsePath("board"))) {
+		if strings.HasPrefix(e, render.ErrEventTagField) && strings.Contains(e, `"cart_id"`) && strings.Contains(e, `"ItemAdded"`) {
+			t.Errorf("unexpected tag field error: %s", e)
+		}
+	}
 }
 
-// TestValidUnionTypeSameOnBothSides checks that when endpoint and command both declare the
-// same union type (int | string), no error is reported.
-func TestValidUnionTypeSameOnBothSides(t *testing.T) {
+func TestTagOnlyQueryItem(t *testing.T) {
 	src := `
 package test
 
 import "github.com/err0r500/event-modeling-dcb-spec/em"
 
+_tags: [Name=string]: em.#Tag & {name: Name}
+_tags: {
+	cart_id: {param: "cartId", type: string}
+}
+
 board: em.#Board & {
 	name: "Test"
-	tags: {}
+	tags: _tags
 	events: {
-		TestEvent: {eventType: "TestEvent", fields: {x: int | string}, tags: []}
+		CartCreated: {eventType: "CartCreated", fields: {cartId: string}, tags: [_tags.cart_id]}
 	}
 	actors: {
 		User: {name: "User"}
@@ -1916,37 +6382,108 @@ board: em.#Board & {
 		name: "Default"
 		chapters: [{
 			name: "Main"
-			flow: [{
-				kind: "slice"
-				name: "TestSlice"
-				type: "change"
-				actor: {name: "User"}
-				trigger: {kind: "endpoint", endpoint: {
-					verb: "POST"
-					params: {x: int | string}
-					body: {}
-					path: "/test"
-				}}
-				command: {
-					name: "TestCmd"
-					fields: {x: int | string}  // same union type: command handles everything
-					query: {items: []}
-				}
-				emits: [events.TestEvent]
-				scenarios: []
-			}]
+			flow: [
+				{
+					kind: "slice"
+					name: "CreateCart"
+					type: "change"
+					actor: {name: "User"}
+					trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {cartId: string}, path: "/carts"}}
+					command: {name: "CreateCart", fields: {cartId: string}, query: {items: [{types: [], tags: [{tag: _tags.cart_id, value: fields.cartId}]}]}}
+					emits: [events.CartCreated]
+					scenarios: []
+				},
+			]
 		}]
 	}]
 }
 `
-	assertValid(t, src)
+	b, warnings, err := board.LoadBoardFromSource(src, "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	for _, w := range warnings {
+		if render.CodeSeverity(render.DiagnosticCode(w)) == render.SeverityError {
+			t.Errorf("tag-only query item rejected: %s", w)
+		}
+	}
+
+	_, files, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	data := files["CreateCart.json"]
+	out, err := json.Marshal(data["command"].(map[string]any)["query"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"types":[]`) {
+		t.Errorf("query IR = %s, want empty types", out)
+	}
+	rendered, err := render.RenderSliceIR(data, 100)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(rendered, "[*] tagged cart_id=command.fields.cartId") {
+		t.Errorf("tag-only query should render as a wildcard:\n%s", rendered)
+	}
 }
 
-// TestValidUnionTypeCommandWiderThanEndpoint checks that when an endpoint provides a concrete
-// type (string) and the command declares a wider union type (int | string), no error is
-// reported — the command can safely handle the narrower endpoint type.
-func TestValidUnionTypeCommandWiderThanEndpoint(t *testing.T) {
-	src := `
+func TestRenderScenarioThen(t *testing.T) {
+	data := map[string]any{
+		"kind": "slice",
+		"type": "change",
+		"name": "PlaceOrder",
+		"scenarios": []any{
+			map[string]any{"name": "places", "then": map[string]any{"success": true, "events": []any{
+				map[string]any{"type": "OrderPlaced", "values": map[string]any{"amount": 100}},
+				"CartCleared",
+			}}},
+			map[string]any{"name": "rejects", "then": map[string]any{"success": false, "error": "cart is empty"}},
+			map[string]any{"name": "no-op", "then": map[string]any{"success": true}},
+		},
+	}
+	out, err := render.RenderSliceIRPlain(data, 0)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, want := range []string{
+		"Then:  ✓ OrderPlaced {amount: 100}\n",
+		"\n               CartCleared\n",
+		"Then:  ✗ cart is empty\n",
+		"Then:  ✓ (no events)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestEndpointAuthReified(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	_, files, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+
+	endpoints := map[string]map[string]any{
+		"AddItem.json":       files["AddItem.json"]["trigger"].(map[string]any)["endpoint"].(map[string]any),
+		"ViewCartItems.json": files["ViewCartItems.json"]["endpoint"].(map[string]any),
+	}
+	for file, ep := range endpoints {
+		auth, _ := ep["auth"].(map[string]any)
+		if auth["userId"] != "string" {
+			t.Errorf("%s endpoint auth = %v, want userId: string", file, ep["auth"])
+		}
+		out, err := render.RenderSliceIR(files[file], 100)
+		if err != nil {
+			t.Fatalf("render %s: %v", file, err)
+		}
+		if !regexp.MustCompile(`auth:[^\n]*\n[^\n]*userId: string`).MatchString(out) {
+			t.Errorf("%s render missing the auth section:\n%s", file, out)
+		}
+	}
+}
+
+func TestInvalidDevStatus(t *testing.T) {
+	const src = `
 package test
 
 import "github.com/err0r500/event-modeling-dcb-spec/em"
@@ -1955,37 +6492,161 @@ board: em.#Board & {
 	name: "Test"
 	tags: {}
 	events: {
-		TestEvent: {eventType: "TestEvent", fields: {x: string}, tags: []}
-	}
-	actors: {
-		User: {name: "User"}
+		EventA: {eventType: "EventA", fields: {}, tags: []}
 	}
+	actors: {User: {name: "User"}}
 	contexts: [{
 		name: "Default"
 		chapters: [{
 			name: "Main"
 			flow: [{
 				kind: "slice"
-				name: "TestSlice"
+				name: "Emit"
 				type: "change"
+				devstatus: "doing"
 				actor: {name: "User"}
-				trigger: {kind: "endpoint", endpoint: {
-					verb: "POST"
-					params: {x: string}  // concrete type
-					body: {}
-					path: "/test"
-				}}
-				command: {
-					name: "TestCmd"
-					fields: {x: int | string}  // wider union: command accepts more than endpoint provides
-					query: {items: []}
-				}
-				emits: [events.TestEvent]
-				scenarios: []
+				trigger: {kind: "endpoint", endpoint: {verb: "POST", params: {}, body: {}, path: "/a"}}
+				command: {name: "Emit", fields: {}, query: {items: []}}
+				emits: [events.EventA]
+			}, {
+				kind: "slice"
+				name: "ReadA"
+				type: "view"
+				devstatus: "blocked"
+				actor: {name: "User"}
+				endpoint: {verb: "GET", params: {}, body: {}, path: "/a"}
+				readModel: {name: "ViewA", cardinality: "single", fields: {}}
+				query: {items: [{types: [events.EventA], tags: []}]}
 			}]
 		}]
 	}]
 }
 `
-	assertValid(t, src)
+	// #DevStatus rejects "blocked" in every slice branch; it is reported
+	// once, positioned, without the branch mismatches.
+	_, _, err := board.LoadBoardFromSource(src, "")
+	if err == nil {
+		t.Fatal("expected a build error")
+	}
+	want := `slice at flow index 1 devstatus "blocked" is not one of specifying, todo, doing, done`
+	d := render.ParseValidationError(strings.TrimPrefix(err.Error(), "build: "))
+	if d.Code != render.ErrDevStatus || d.Message != want || d.File == "" {
+		t.Errorf("error = %q, want a single positioned E111 %q", err, want)
+	}
+}
+
+func TestStatusSummary(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, files, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	byName := map[string]map[string]any{}
+	for _, entry := range manifest.Flow {
+		if entry.Kind == "slice" {
+			byName[entry.Name] = files[entry.File]
+		}
+	}
+	byName["AddItem"]["devstatus"] = "done"
+	byName["SubmitCart"]["devstatus"] = "doing"
+	delete(byName["ClearCart"], "devstatus")
+	byName["RemoveItem"]["devstatus"] = "blocked"
+
+	summary := board.StatusSummary(manifest, files)
+	if len(summary) != len(manifest.Contexts) {
+		t.Fatalf("got %d contexts, want %d", len(summary), len(manifest.Contexts))
+	}
+	shopping := summary[0]
+	// ViewOneItemCart and ViewEmptyCart appear twice in the flow but count once
+	want := map[string]int{"specifying": 9, "doing": 1, "done": 1, board.StatusUnspecified: 2}
+	if shopping.Context != "Shopping" || !maps.Equal(shopping.Counts, want) || shopping.Total != 13 {
+		t.Errorf("Shopping = %+v, want counts %v and total 13", shopping, want)
+	}
+	if other := summary[1]; other.Total != 0 {
+		t.Errorf("%s should have no slices, got %+v", other.Context, other)
+	}
+}
+
+func TestManifestGraph(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	plain, _, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{})
+	if data, _ := json.Marshal(plain); plain.Graph != nil || strings.Contains(string(data), `"graph"`) {
+		t.Errorf("the graph should be opt-in, got %v", plain.Graph)
+	}
+
+	manifest, files, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{Graph: true})
+	graph := map[string]board.GraphEntry{}
+	for _, g := range manifest.Graph {
+		graph[g.Slice] = g
+		if entry := manifest.Flow[g.Index]; entry.Name != g.Slice || entry.Kind != "slice" {
+			t.Errorf("graph entry %s points at flow entry %+v", g.Slice, entry)
+		}
+	}
+	sliceCount := 0
+	for _, entry := range manifest.Flow {
+		if entry.Kind == "slice" {
+			sliceCount++
+			data := files[entry.File]
+			if g := graph[entry.Name]; !reflect.DeepEqual(g.Emits, board.SliceEmits(data)) || !reflect.DeepEqual(g.Consumes, board.SliceInputs(data)) {
+				t.Errorf("graph entry %+v doesn't match the slice file", g)
+			}
+		}
+	}
+	if len(manifest.Graph) != sliceCount {
+		t.Errorf("graph has %d entries, want one per slice (%d)", len(manifest.Graph), sliceCount)
+	}
+	if got := graph["AddItem"].Emits; !slices.Contains(got, "ItemAdded") {
+		t.Errorf("AddItem emits %v, want ItemAdded", got)
+	}
+	if got := graph["OnInventoryChanged"].Consumes; len(got) == 0 || got[0] != "InventoryChanged" {
+		t.Errorf("OnInventoryChanged consumes %v, want its trigger first", got)
+	}
+}
+
+func TestDotGraph(t *testing.T) {
+	b, _, err := board.LoadBoardPermissive("examples/cart.cue", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	manifest, _, _ := board.ReifyBoardFiles(b, nil, board.ReifyOptions{Graph: true})
+	out, err := dot.Graph(manifest, "")
+	if err != nil {
+		t.Fatalf("graph: %v", err)
+	}
+	for _, want := range []string{
+		"digraph \"Shopping Cart\" {\n",
+		"    rankdir=LR;\n",
+		"    subgraph cluster_0 {\n        label=\"Shopping\";\n",
+		`"slice:AddItem" [label="AddItem\n(change)", shape=box`,
+		`"event:ItemAdded" [label="ItemAdded", shape=ellipse`,
+		`"slice:AddItem" -> "event:ItemAdded";`,
+		`"event:InventoryChanged" -> "slice:OnInventoryChanged";`,
+		`"event:ItemAdded" -> "slice:ViewCartItems";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT missing %q:\n%s", want, out)
+		}
+	}
+	declared := map[string]bool{}
+	for line := range strings.SplitSeq(out, "\n") {
+		if id, _, ok := strings.Cut(strings.TrimSpace(line), " ["); ok && !strings.Contains(id, "->") {
+			if declared[id] {
+				t.Errorf("node %s declared twice", id)
+			}
+			declared[id] = true
+		}
+	}
+	if strings.Count(out, "{") != strings.Count(out, "}") {
+		t.Errorf("unbalanced braces:\n%s", out)
+	}
+
+	if out, _ := dot.Graph(manifest, "TB"); !strings.Contains(out, "rankdir=TB;") {
+		t.Errorf("rankdir should pass through:\n%s", out)
+	}
+	if _, err := dot.Graph(manifest, "sideways"); err == nil {
+		t.Error("want an error for an unknown rankdir")
+	}
 }
@@ -49,6 +49,8 @@ function calcSliceWidth(slice: ChangeSlice | ViewSlice | AutomationSlice): numbe
       triggerW = textWidth(`⚡ ${cs.trigger.externalEvent.name}`);
     } else if (cs.trigger.kind === 'internalEvent') {
       triggerW = textWidth(`⟲ ${cs.trigger.internalEvent.eventType}`);
+    } else if (cs.trigger.kind === 'ui') {
+      triggerW = textWidth(cs.trigger.ui.name);
     }
     const commandW = textWidth(cs.name);
     const eventsW = cs.emits.reduce((sum, e) => sum + textWidth(e.type) + 5, -5);
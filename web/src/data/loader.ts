@@ -1,4 +1,4 @@
-import type { BoardManifest, Slice } from './types';
+import type { BoardManifest, Slice, ValidationError } from './types';
 
 export const BOARD_PATH = '/.board';
 
@@ -138,51 +138,37 @@ export async function loadBoard(): Promise<LoadedBoard> {
     return { manifest, slices };
 }
 
-async function hashText(text: string): Promise<string> {
-    // crypto.subtle not available in insecure contexts (Safari)
-    if (!crypto.subtle) {
-        // Simple hash fallback - just use length + first/last chars
-        return `${text.length}-${text.slice(0, 100)}-${text.slice(-100)}`;
-    }
-    const encoder = new TextEncoder();
-    const data = encoder.encode(text);
-    const hashBuffer = await crypto.subtle.digest('SHA-256', data);
-    const hashArray = Array.from(new Uint8Array(hashBuffer));
-    return hashArray.map(b => b.toString(16).padStart(2, '0')).join('');
+// Load the structured diagnostics (code, message, source position) for inline annotations
+export async function loadDiagnostics(): Promise<ValidationError[]> {
+    const res = await fetch(`${BOARD_PATH}/diagnostics.json`, { cache: 'no-store' });
+    if (!res.ok) {
+        return [];
+    }
+    return await res.json() as ValidationError[];
 }
 
-export function watchBoard(callback: () => void): void {
-    let lastHash = '';
+// Message pushed by the emspec web server on /.ws
+interface LiveMessage {
+    type: 'reloaded' | 'error';
+    errors?: string[];
+}
 
-    setInterval(async () => {
+// watchBoard calls callback whenever the server regenerates the board, and
+// reconnects when the connection drops (server restart).
+export function watchBoard(callback: () => void): void {
+    const scheme = location.protocol === 'https:' ? 'wss' : 'ws';
+    const ws = new WebSocket(`${scheme}://${location.host}/.ws`);
+    ws.onmessage = (ev) => {
         try {
-            // Fetch manifest
-            const manifestRes = await fetch(`${BOARD_PATH}/board.json`, { cache: 'no-store' });
-            const manifestText = await manifestRes.text();
-            const manifest = JSON.parse(manifestText);
-
-            // Fetch all slice files
-            const sliceFiles = (manifest.flow || [])
-                .filter((e: any) => e.file)
-                .map((e: any) => e.file);
-
-            const sliceTexts = await Promise.all(
-                sliceFiles.map(async (file: string) => {
-                    const res = await fetch(`${BOARD_PATH}/${file}`, { cache: 'no-store' });
-                    return res.ok ? res.text() : '';
-                })
-            );
-
-            // Hash everything together
-            const allContent = manifestText + sliceTexts.join('');
-            const hash = await hashText(allContent);
-
-            if (lastHash && hash !== lastHash) {
+            const msg = JSON.parse(ev.data) as LiveMessage;
+            if (msg.type === 'reloaded' || msg.type === 'error') {
                 callback();
             }
-            lastHash = hash;
         } catch {
             // ignore
         }
-    }, 500);
+    };
+    ws.onclose = () => {
+        setTimeout(() => watchBoard(callback), 1000);
+    };
 }
@@ -1,10 +1,23 @@
 // Board manifest structure
 export interface BoardManifest {
+  schemaVersion?: number;
   name: string;
   actors: string[];
   contexts: ContextEntry[];
   flow: FlowEntry[];
   errors?: string[];
+  diagnostics?: ValidationError[];
+}
+
+// A diagnostic of errors, broken into its parts
+export interface ValidationError {
+  code?: string;
+  message: string;
+  slice?: string;
+  field?: string;
+  file?: string;
+  line?: number;
+  col?: number;
 }
 
 export interface ContextEntry {
@@ -30,6 +43,7 @@ export interface FlowEntry {
   instance?: Record<string, unknown>;
   emits?: StoryEventInstance[];
   image?: string;
+  actor?: string;
 }
 
 export interface StoryEventInstance {
@@ -114,7 +128,17 @@ export interface InternalEventTrigger {
   internalEvent: InternalEvent;
 }
 
-export type Trigger = EndpointTrigger | ExternalEventTrigger | InternalEventTrigger;
+export interface UIAction {
+  name: string;
+  fields: Record<string, string>;
+}
+
+export interface UITrigger {
+  kind: 'ui';
+  ui: UIAction;
+}
+
+export type Trigger = EndpointTrigger | ExternalEventTrigger | InternalEventTrigger | UITrigger;
 
 export interface DependentQuery {
   extract: Record<string, { event: string; field: string; many?: boolean }>;
@@ -149,6 +173,7 @@ export interface ReadModel {
   cardinality: 'single' | 'multiple';
   fields: Record<string, unknown>;
   mapping: Record<string, string>;
+  references?: Record<string, string>;
 }
 
 export interface ChangeScenario {
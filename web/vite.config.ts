@@ -45,6 +45,10 @@ export default defineConfig({
     emptyDirBeforeWrite: true
   },
   server: {
+    // Live reloads come from a running `emspec -web` (EMSPEC_PORT, default 3000)
+    proxy: {
+      '/.ws': { target: `ws://localhost:${process.env.EMSPEC_PORT || 3000}`, ws: true }
+    },
     watch: {
       usePolling: true
     },